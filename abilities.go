@@ -0,0 +1,105 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+)
+
+// How many sets of scores GenerateAbilityScores rolls before giving up on meeting its options.
+const maxAbilityScoreAttempts = 1000
+
+// The D&D 5e point-buy cost of each score from 3 to 18, extended past the usual 8 to 15 so any rolled score has an equivalent.
+var pointBuyCosts = map[int]int{
+	3: -9, 4: -6, 5: -4, 6: -2, 7: -1, 8: 0, 9: 1, 10: 2, 11: 3, 12: 4, 13: 5, 14: 7, 15: 9, 16: 12, 17: 15, 18: 19,
+}
+
+// AbilityScoreOptions holds the house rules for GenerateAbilityScores. The zero value is plain 4d6 drop the lowest.
+type AbilityScoreOptions struct {
+	RerollOnes   bool // Whether dice which come up 1 are rerolled once, keeping the new roll.
+	MinimumTotal int  // Reroll the whole set if the six scores add up to less than this, e.g. 70. Zero means no minimum.
+	MinPointBuy  int  // Reroll the whole set if its point-buy equivalent is less than this, e.g. 20. Zero means no minimum.
+	MaxPointBuy  int  // Reroll the whole set if its point-buy equivalent is more than this, e.g. 35. Zero means no maximum.
+}
+
+// AbilityScores holds a set of D&D 5e ability scores, and how they were rolled.
+type AbilityScores struct {
+	Scores   [6]int      // Each score, in the order rolled.
+	Rolls    [6]DiceRoll // The 4d6kh3 roll behind each score.
+	Total    int         // The scores added up.
+	PointBuy int         // What the scores would cost with point buy (where 27 is standard), extended outside 8 to 15.
+	Attempts int         // How many sets were rolled to meet the options, starting at 1.
+}
+
+/*
+ * GenerateAbilityScores rolls six D&D 5e ability scores with 4d6, dropping the lowest die of each, and rerolls the whole set until it
+ *   meets the options. It gives up with an error if the options can't be met in 1000 sets.
+ * e.g. GenerateAbilityScores(AbilityScoreOptions{MinimumTotal: 75}) // {[16 13 10 14 10 14] [...] 77 35 1}
+ */
+func GenerateAbilityScores(options AbilityScoreOptions) (output AbilityScores, err error) {
+	if options.MaxPointBuy != 0 && options.MinPointBuy > options.MaxPointBuy {
+		return AbilityScores{}, errors.New("the minimum point buy is more than the maximum")
+	}
+
+	spec, err := parseRoll("4d6kh3")
+	if err != nil {
+		return
+	}
+
+	if options.RerollOnes {
+		spec.reroll = 1
+	}
+
+	for output.Attempts = 1; output.Attempts <= maxAbilityScoreAttempts; output.Attempts++ {
+		output.Total, output.PointBuy = 0, 0
+
+		for i := range output.Scores {
+			if output.Rolls[i], err = postProcess(spec.roll()); err != nil {
+				return AbilityScores{}, err
+			}
+
+			output.Scores[i] = output.Rolls[i].Total
+			output.Total += output.Scores[i]
+			output.PointBuy += pointBuyCosts[min(max(output.Scores[i], 3), 18)]
+		}
+
+		if options.meets(output) {
+			return
+		}
+	}
+
+	return AbilityScores{}, fmt.Errorf("no set of ability scores met the options in %d attempts", maxAbilityScoreAttempts)
+}
+
+/*
+ * meets returns whether a set of scores meets the options.
+ */
+func (options AbilityScoreOptions) meets(scores AbilityScores) bool {
+	switch {
+	case options.MinimumTotal != 0 && scores.Total < options.MinimumTotal:
+		return false
+	case options.MinPointBuy != 0 && scores.PointBuy < options.MinPointBuy:
+		return false
+	case options.MaxPointBuy != 0 && scores.PointBuy > options.MaxPointBuy:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type generateAbilityScoresTest struct {
+	options      AbilityScoreOptions
+	wantScores   [6]int
+	wantTotal    int
+	wantPointBuy int
+	wantAttempts int
+}
+
+var generateAbilityScoresTests = []generateAbilityScoresTest{
+	{AbilityScoreOptions{}, [6]int{16, 13, 10, 14, 10, 14}, 77, 35, 1},
+	{AbilityScoreOptions{MinimumTotal: 75}, [6]int{10, 15, 14, 10, 13, 17}, 79, 40, 1},
+	{AbilityScoreOptions{RerollOnes: true, MinPointBuy: 27, MaxPointBuy: 30}, [6]int{10, 14, 9, 13, 10, 16}, 72, 29, 20},
+}
+
+// TestGenerateAbilityScores calls diceroller.GenerateAbilityScores with many options, checking for valid return values.
+func TestGenerateAbilityScores(t *testing.T) {
+	reseed()
+
+	for _, test := range generateAbilityScoresTests {
+		output, err := GenerateAbilityScores(test.options)
+
+		if output.Scores != test.wantScores || output.Total != test.wantTotal || output.PointBuy != test.wantPointBuy ||
+			output.Attempts != test.wantAttempts || err != nil {
+			t.Errorf("have %v %d %d %d, wanted %v %d %d %d, err %v", output.Scores, output.Total, output.PointBuy, output.Attempts,
+				test.wantScores, test.wantTotal, test.wantPointBuy, test.wantAttempts, err)
+		}
+
+		for i, dr := range output.Rolls {
+			if dr.DiscoveredRoll != "4d6kh3" || dr.Total != output.Scores[i] || len(dr.Dropped) != 1 {
+				t.Errorf("have %v, wanted a 4d6kh3 roll totalling %d", dr, output.Scores[i])
+			}
+		}
+	}
+
+	for _, options := range []AbilityScoreOptions{{MinimumTotal: 200}, {MinPointBuy: 30, MaxPointBuy: 20}} {
+		if output, err := GenerateAbilityScores(options); err == nil || !reflect.DeepEqual(output, AbilityScores{}) {
+			t.Errorf("have %v, nil error for %+v, wanted an error", output, options)
+		}
+	}
+}
+
+// TestPointBuyCosts checks the point-buy costs are the standard ones from 8 to 15, and keep rising either side.
+func TestPointBuyCosts(t *testing.T) {
+	for score, want := range map[int]int{8: 0, 10: 2, 13: 5, 14: 7, 15: 9} {
+		if pointBuyCosts[score] != want {
+			t.Errorf("have %d, wanted %d for %d", pointBuyCosts[score], want, score)
+		}
+	}
+
+	for score := 4; score <= 18; score++ {
+		if pointBuyCosts[score] <= pointBuyCosts[score-1] {
+			t.Errorf("have %d for %d, wanted more than %d for %d", pointBuyCosts[score], score, pointBuyCosts[score-1], score-1)
+		}
+	}
+}
+
+// BenchmarkGenerateAbilityScores benchmarks diceroller.GenerateAbilityScores.
+func BenchmarkGenerateAbilityScores(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = GenerateAbilityScores(AbilityScoreOptions{RerollOnes: true})
+	}
+}
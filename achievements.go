@@ -0,0 +1,188 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Achievement is a milestone a user can unlock by rolling, such as their first natural 20.
+type Achievement struct {
+	Name        string                   // A short, unique name, e.g. 'first-nat-20'.
+	Description string                   // What the user did, e.g. 'Rolled a natural 20'.
+	Tracker     func() func(Record) bool // Makes a fresh tracker for each user, which is given their rolls oldest first and reports the milestone.
+}
+
+// AchievementEvent is sent when a user unlocks an Achievement.
+type AchievementEvent struct {
+	Time        time.Time   // When the roll which unlocked the achievement was made.
+	User        string      // Who unlocked it.
+	Session     string      // The session the roll which unlocked it was made in.
+	Achievement Achievement // What they unlocked.
+}
+
+// Achievements tracks each user's rolls and tells you when they unlock an achievement. Rolls aren't kept: each achievement's tracker keeps
+// whatever running counts it needs. It's safe for concurrent use.
+type Achievements struct {
+	OnUnlock func(AchievementEvent) // Optional hook called for every achievement unlocked.
+
+	achievements []Achievement
+	mu           sync.Mutex
+	trackers     map[string][]func(Record) bool // Each user's trackers, one per achievement, or nil once it's unlocked.
+	unlocked     map[string]map[string]bool     // The names of each user's unlocked achievements.
+}
+
+/*
+ * NewAchievements returns an achievements engine tracking the given achievements, or DefaultAchievements() if none are given.
+ */
+func NewAchievements(achievements ...Achievement) *Achievements {
+	if len(achievements) == 0 {
+		achievements = DefaultAchievements()
+	}
+
+	return &Achievements{
+		achievements: achievements,
+		trackers:     map[string][]func(Record) bool{},
+		unlocked:     map[string]map[string]bool{},
+	}
+}
+
+/*
+ * DefaultAchievements returns the built-in achievements: a first natural 20, three natural 20s in a session, and rolling every standard die.
+ */
+func DefaultAchievements() []Achievement {
+	return []Achievement{
+		{
+			Name:        "first-nat-20",
+			Description: "Rolled a natural 20",
+			Tracker: func() func(Record) bool {
+				return func(record Record) bool {
+					return naturalTwenties(record.Roll) > 0
+				}
+			},
+		},
+		{
+			Name:        "three-crits-in-a-session",
+			Description: "Rolled three natural 20s in one session",
+			Tracker: func() func(Record) bool {
+				crits := map[string]int{} // Natural 20s in each session.
+
+				return func(record Record) bool {
+					crits[record.Session] += naturalTwenties(record.Roll)
+
+					return crits[record.Session] >= 3
+				}
+			},
+		},
+		{
+			Name:        "every-standard-die",
+			Description: "Rolled a d4, d6, d8, d10, d12 and d20",
+			Tracker: func() func(Record) bool {
+				standard := []int{4, 6, 8, 10, 12, 20}
+
+				return func(record Record) bool {
+					standard = slices.DeleteFunc(standard, func(faces int) bool {
+						return faces == record.Roll.Faces
+					})
+
+					return len(standard) == 0
+				}
+			},
+		},
+	}
+}
+
+/*
+ * Record adds a roll to the user's history and returns any achievements it unlocked, calling OnUnlock for each.
+ */
+func (a *Achievements) Record(record Record) (output []AchievementEvent) {
+	a.mu.Lock()
+
+	trackers := a.trackers[record.User]
+	if trackers == nil {
+		trackers = make([]func(Record) bool, len(a.achievements))
+		for i, achievement := range a.achievements {
+			trackers[i] = achievement.Tracker()
+		}
+
+		a.trackers[record.User] = trackers
+		a.unlocked[record.User] = map[string]bool{}
+	}
+
+	for i, achievement := range a.achievements {
+		if trackers[i] == nil || !trackers[i](record) {
+			continue
+		}
+
+		trackers[i] = nil
+		a.unlocked[record.User][achievement.Name] = true
+		output = append(output, AchievementEvent{
+			Time:        record.Time,
+			User:        record.User,
+			Session:     record.Session,
+			Achievement: achievement,
+		})
+	}
+
+	a.mu.Unlock()
+
+	// The hook is called without the lock held, so it's free to look at the engine itself.
+	if a.OnUnlock != nil {
+		for _, event := range output {
+			a.OnUnlock(event)
+		}
+	}
+
+	return
+}
+
+/*
+ * Unlocked returns the names of the achievements a user has unlocked, in the order the achievements were given to NewAchievements.
+ */
+func (a *Achievements) Unlocked(user string) (output []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, achievement := range a.achievements {
+		if a.unlocked[user][achievement.Name] {
+			output = append(output, achievement.Name)
+		}
+	}
+
+	return
+}
+
+/*
+ * naturalTwenties counts the d20s in a roll which came up 20, ignoring dropped dice.
+ */
+func naturalTwenties(input DiceRoll) (output int) {
+	if input.Faces != 20 {
+		return
+	}
+
+	for _, v := range keptResults(input) {
+		if v == 20 {
+			output++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,129 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type achievementsTest struct {
+	got  Record
+	want []string
+}
+
+var achievementsTests = []achievementsTest{
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{20}, Total: 20}}, []string{"first-nat-20"}},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "2d20kh1", Faces: 20, Rolls: 2, Results: []int{20, 3}, Total: 20, Dropped: []int{1}}}, nil},
+	{Record{User: "bob", Session: "one", Roll: DiceRoll{DiscoveredRoll: "2d20kl1", Faces: 20, Rolls: 2, Results: []int{20, 3}, Total: 3, Dropped: []int{0}}}, nil},
+	{Record{User: "alice", Session: "two", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{20}, Total: 20}}, nil},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{20}, Total: 20}}, []string{"three-crits-in-a-session"}},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d4", Faces: 4, Rolls: 1, Results: []int{1}, Total: 1}}, nil},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Results: []int{1}, Total: 1}}, nil},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d8", Faces: 8, Rolls: 1, Results: []int{1}, Total: 1}}, nil},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d10", Faces: 10, Rolls: 1, Results: []int{1}, Total: 1}}, nil},
+	{Record{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d12", Faces: 12, Rolls: 1, Results: []int{1}, Total: 1}}, []string{"every-standard-die"}},
+}
+
+// TestAchievements calls diceroller.Achievements.Record with a series of rolls, checking the achievements unlocked and the OnUnlock hook.
+func TestAchievements(t *testing.T) {
+	var (
+		achievements = NewAchievements()
+		hooked       []string
+	)
+
+	achievements.OnUnlock = func(event AchievementEvent) {
+		hooked = append(hooked, event.User+" "+event.Achievement.Name)
+	}
+
+	for _, test := range achievementsTests {
+		var names []string
+		for _, event := range achievements.Record(test.got) {
+			names = append(names, event.Achievement.Name)
+		}
+
+		if !reflect.DeepEqual(names, test.want) {
+			t.Errorf("have %v, wanted %v", names, test.want)
+		}
+	}
+
+	wantHooked := []string{"alice first-nat-20", "alice three-crits-in-a-session", "alice every-standard-die"}
+	if !reflect.DeepEqual(hooked, wantHooked) {
+		t.Errorf("have %v, wanted %v", hooked, wantHooked)
+	}
+
+	wantUnlocked := []string{"first-nat-20", "three-crits-in-a-session", "every-standard-die"}
+	if unlocked := achievements.Unlocked("alice"); !reflect.DeepEqual(unlocked, wantUnlocked) {
+		t.Errorf("have %v, wanted %v", unlocked, wantUnlocked)
+	}
+
+	if unlocked := achievements.Unlocked("bob"); unlocked != nil {
+		t.Errorf("have %v, wanted nil", unlocked)
+	}
+}
+
+// TestAchievementsTracker calls diceroller.Achievements.Record with a custom achievement, checking each user gets their own tracker and
+// it isn't called again once unlocked.
+func TestAchievementsTracker(t *testing.T) {
+	var calls int
+
+	achievements := NewAchievements(Achievement{
+		Name:        "ten-rolls",
+		Description: "Rolled ten times",
+		Tracker: func() func(Record) bool {
+			var rolls int
+
+			return func(Record) bool {
+				calls++
+				rolls++
+
+				return rolls == 10
+			}
+		},
+	})
+
+	for i := range 12 {
+		events := achievements.Record(Record{User: "alice", Roll: DiceRoll{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Results: []int{1}, Total: 1}})
+		if unlocked := len(events) == 1; unlocked != (i == 9) {
+			t.Errorf("have %v for roll %d, wanted an unlock on roll 10 only", events, i+1)
+		}
+	}
+
+	if events := achievements.Record(Record{User: "bob"}); events != nil {
+		t.Errorf("have %v for bob's first roll, wanted nil", events)
+	}
+
+	if calls != 11 {
+		t.Errorf("have %d calls, wanted 11", calls)
+	}
+}
+
+// BenchmarkAchievements benchmarks diceroller.Achievements.Record.
+func BenchmarkAchievements(b *testing.B) {
+	var achievements *Achievements
+
+	for i := 0; i < b.N; i++ {
+		// Start afresh each time round, so there's always something to unlock.
+		if i%len(achievementsTests) == 0 {
+			achievements = NewAchievements()
+		}
+
+		achievements.Record(achievementsTests[i%len(achievementsTests)].got)
+	}
+}
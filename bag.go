@@ -0,0 +1,138 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrBagEmpty is returned when drawing more from a Bag than it has left.
+var ErrBagEmpty = errors.New("not enough left in the bag")
+
+// Bag is a die which is drawn from without replacement, like a bag of tokens: each face can only come up as many times as it's in
+// the bag, until the bag is reset. It draws from the same random source as the dice, or a Roller's, with Roller.NewBag. It's safe
+// for concurrent use.
+type Bag struct {
+	mu     sync.Mutex
+	random RandSource // Where the draws get their random numbers from.
+	counts []int      // How many of each face a full bag holds, lowest face first.
+	left   []int      // How many of each face are left in the bag.
+}
+
+/*
+ * NewBag returns a full Bag holding the given number of each face, lowest face first.
+ * e.g. NewBag(3, 2, 1) // a bag of three 1s, two 2s and a 3
+ */
+func NewBag(counts ...int) (*Bag, error) {
+	return defaultRoller.NewBag(counts...)
+}
+
+/*
+ * NewBag is like the package's NewBag, but the bag is drawn from with the Roller's random source, e.g. a seeded one.
+ * e.g. NewRoller(WithSeed(42, 1024)).NewBag(3, 2, 1)
+ */
+func (roller *Roller) NewBag(counts ...int) (*Bag, error) {
+	if err := (DieSpec{Faces: len(counts), Weights: counts}).validate(); err != nil {
+		return nil, err
+	}
+
+	return &Bag{random: roller.source(), counts: slices.Clone(counts), left: slices.Clone(counts)}, nil
+}
+
+/*
+ * Draw takes one face from the bag at random, or returns ErrBagEmpty if the bag is empty.
+ */
+func (bag *Bag) Draw() (int, error) {
+	output, err := bag.Roll(1, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	return output.Results[0], nil
+}
+
+/*
+ * Roll draws faces from the bag the given number of times, adds the modifier, and returns a DiceRoll struct with the details.
+ *   It returns ErrBagEmpty, and draws nothing, if there aren't enough left in the bag, and ErrZeroDice for fewer than one draw.
+ * e.g. bag.Roll(2, 1) // {2d3+1 3 2 1 [1 3] 5 ...}
+ */
+func (bag *Bag) Roll(rolls, modifier int) (output DiceRoll, err error) {
+	bag.mu.Lock()
+	defer bag.mu.Unlock()
+
+	if rolls < 1 {
+		return DiceRoll{}, fmt.Errorf("%w: drawing %d", ErrZeroDice, rolls)
+	}
+
+	if left := bag.remaining(); rolls > left {
+		return DiceRoll{}, fmt.Errorf("%w: drawing %d with %d left", ErrBagEmpty, rolls, left)
+	}
+
+	spec := newRollSpec(rolls, DieSpec{Faces: len(bag.counts)}, modifier)
+	output = DiceRoll{DiscoveredRoll: spec.discovered, Faces: spec.die.Faces, Rolls: rolls, Modifier: modifier, Results: make([]int, rolls)}
+
+	for i := range output.Results {
+		// What's left in the bag weights the draw.
+		face := DieSpec{Faces: len(bag.left), Weights: bag.left}.rollFrom(bag.random)
+		bag.left[face-1]--
+
+		output.Results[i] = face
+		output.Total += face
+	}
+
+	output.Total += modifier
+
+	countNaturals(&output)
+
+	return postProcess(output)
+}
+
+/*
+ * Remaining returns how many faces are left in the bag.
+ */
+func (bag *Bag) Remaining() int {
+	bag.mu.Lock()
+	defer bag.mu.Unlock()
+
+	return bag.remaining()
+}
+
+/*
+ * Reset puts every face drawn back in the bag.
+ */
+func (bag *Bag) Reset() {
+	bag.mu.Lock()
+	defer bag.mu.Unlock()
+
+	copy(bag.left, bag.counts)
+}
+
+/*
+ * remaining returns how many faces are left in the bag. The caller must hold the lock.
+ */
+func (bag *Bag) remaining() (output int) {
+	for _, count := range bag.left {
+		output += count
+	}
+
+	return
+}
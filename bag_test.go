@@ -0,0 +1,113 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestBag draws a bag empty, checking every face comes up exactly as many times as it's in the bag, then that it resets.
+func TestBag(t *testing.T) {
+	reseed()
+
+	bag, err := NewBag(3, 0, 2, 1)
+	if err != nil {
+		t.Fatalf("have err %v, wanted nil", err)
+	}
+
+	for round := 0; round < 2; round++ {
+		output, err := bag.Roll(4, 1)
+		if err != nil || len(output.Results) != 4 || output.DiscoveredRoll != "4d4+1" || bag.Remaining() != 2 {
+			t.Fatalf("have %v with %d left, err %v, wanted 4 draws and 2 left", output, bag.Remaining(), err)
+		}
+
+		drawn := output.Results
+		for bag.Remaining() > 0 {
+			face, err := bag.Draw()
+			if err != nil {
+				t.Fatalf("have err %v, wanted nil", err)
+			}
+
+			drawn = append(drawn, face)
+		}
+
+		slices.Sort(drawn)
+		if want := []int{1, 1, 1, 3, 3, 4}; !slices.Equal(drawn, want) {
+			t.Errorf("have %v, wanted %v", drawn, want)
+		}
+
+		if _, err := bag.Draw(); !errors.Is(err, ErrBagEmpty) {
+			t.Errorf("have err %v, wanted %v", err, ErrBagEmpty)
+		}
+
+		bag.Reset()
+	}
+
+	if _, err := bag.Roll(7, 0); !errors.Is(err, ErrBagEmpty) || bag.Remaining() != 6 {
+		t.Errorf("have err %v with %d left, wanted %v with 6 left", err, bag.Remaining(), ErrBagEmpty)
+	}
+
+	for _, rolls := range []int{0, -1} {
+		if _, err := bag.Roll(rolls, 0); !errors.Is(err, ErrZeroDice) || bag.Remaining() != 6 {
+			t.Errorf("have err %v with %d left, wanted %v with 6 left for %d draws", err, bag.Remaining(), ErrZeroDice, rolls)
+		}
+	}
+}
+
+// TestNewBagErrors calls diceroller.NewBag with bad counts, checking for errors.
+func TestNewBagErrors(t *testing.T) {
+	for _, counts := range [][]int{nil, {0, 0}, {2, -1}} {
+		if _, err := NewBag(counts...); err == nil {
+			t.Errorf("have nil error for %v, wanted an error", counts)
+		}
+	}
+}
+
+// TestRollerBag calls diceroller.Roller.NewBag, checking the bag is drawn from with the Roller's source, not the package's.
+func TestRollerBag(t *testing.T) {
+	reseed()
+
+	bag, _ := NewBag(3, 2, 1)
+	want, _ := bag.Roll(6, 0)
+
+	for range 2 {
+		random.IntN(6)
+
+		bag, err := NewRoller(WithSeed(42, 1024)).NewBag(3, 2, 1)
+		if err != nil {
+			t.Fatalf("have err %v, wanted nil", err)
+		}
+
+		if output, err := bag.Roll(6, 0); !slices.Equal(output.Results, want.Results) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output.Results, want.Results, err)
+		}
+	}
+}
+
+// BenchmarkBag benchmarks drawing a diceroller.Bag empty and resetting it.
+func BenchmarkBag(b *testing.B) {
+	bag, _ := NewBag(4, 4, 4, 4, 4, 4)
+
+	for i := 0; i < b.N; i++ {
+		_, _ = bag.Roll(24, 0)
+		bag.Reset()
+	}
+}
@@ -0,0 +1,249 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The flags which can follow a die's value in the canonical text format.
+const (
+	canonicalDropped = 'x' // The die was dropped, e.g. by 'kh3'.
+	canonicalMax     = 'c' // The die is a critical die, kept, and came up on its highest face, e.g. a natural 20.
+	canonicalMin     = 'f' // The die is a critical die, kept, and came up 1.
+)
+
+// The fields which can follow the total in the canonical text format, for rolls whose own differ from what the rest of the line says.
+const (
+	canonicalFieldRolls    = "rolls"    // How many dice were rolled, e.g. doubled by a house rule for a critical hit.
+	canonicalFieldModifier = "modifier" // The modifier, e.g. raised by a house rule's minimum total.
+	canonicalFieldMax      = "max"      // How many dice counted towards NaturalMax.
+	canonicalFieldMin      = "min"      // How many dice counted towards NaturalMin.
+)
+
+/*
+ * FormatCanonical encodes a roll as one stable line of text: the roll in lower case, each die in the order it was rolled with any flags
+ *   ('x' dropped, 'c' natural highest face, 'f' natural 1), and the total. If the roll's number of dice, modifier, NaturalMax or
+ *   NaturalMin aren't what the rest of the line says, e.g. after house rules, they follow the total, e.g. ';modifier=19'.
+ *   ParseCanonical reads it back. Sets aren't included.
+ * e.g. FormatCanonical(roll) // "4d6kh3+1=[3,1x,6,4]=14"
+ */
+func FormatCanonical(input DiceRoll) string {
+	var (
+		rolled  = input.Results
+		dropped = input.Dropped
+	)
+
+	// Sorted results are put back in the order they were rolled, so sorting them again on parsing gives the same dice.
+	if len(input.Unsorted) > 0 {
+		order := sortOrder(input.Unsorted, !slices.IsSorted(input.Results))
+		rolled = input.Unsorted
+		dropped = make([]int, len(input.Dropped))
+
+		for i, from := range input.Dropped {
+			dropped[i] = order[from]
+		}
+	}
+
+	var (
+		dice                   = make([]string, len(rolled))
+		naturalMax, naturalMin int
+	)
+
+	for i, v := range rolled {
+		dice[i] = strconv.Itoa(v)
+
+		switch {
+		case slices.Contains(dropped, i):
+			dice[i] += string(canonicalDropped)
+		case v == input.Faces && input.NaturalMax > 0:
+			dice[i] += string(canonicalMax)
+			naturalMax++
+		case v == 1 && input.NaturalMin > 0:
+			dice[i] += string(canonicalMin)
+			naturalMin++
+		}
+	}
+
+	output := fmt.Sprintf("%s=[%s]=%d", strings.ToLower(input.DiscoveredRoll), strings.Join(dice, ","), input.Total)
+
+	if spec, err := parseWholeRoll(input.DiscoveredRoll); err == nil {
+		if input.Rolls != spec.rolls {
+			output += fmt.Sprintf(";%s=%d", canonicalFieldRolls, input.Rolls)
+		}
+
+		if input.Modifier != spec.modifier {
+			output += fmt.Sprintf(";%s=%d", canonicalFieldModifier, input.Modifier)
+		}
+	}
+
+	if input.NaturalMax != naturalMax {
+		output += fmt.Sprintf(";%s=%d", canonicalFieldMax, input.NaturalMax)
+	}
+
+	if input.NaturalMin != naturalMin {
+		output += fmt.Sprintf(";%s=%d", canonicalFieldMin, input.NaturalMin)
+	}
+
+	return output
+}
+
+/*
+ * ParseCanonical decodes a roll encoded by FormatCanonical. Dropped comes back in ascending order.
+ * e.g. ParseCanonical("4d6kh3+1=[3,1x,6,4]=14") // {4d6kh3+1 6 4 1 [3 1 6 4] 14 [] [1] ...}
+ */
+func ParseCanonical(input string) (output DiceRoll, err error) {
+	expression, rest, found := strings.Cut(input, "=[")
+	dice, total, found2 := strings.Cut(rest, "]=")
+	total, fields, hasFields := strings.Cut(total, ";")
+	if !found || !found2 {
+		return DiceRoll{}, fmt.Errorf("%q is not a canonical roll: expected 'roll=[dice]=total'", input)
+	}
+
+	spec, err := parseWholeRoll(expression)
+	if err != nil {
+		return DiceRoll{}, err
+	}
+
+	output = DiceRoll{
+		DiscoveredRoll: spec.discovered,
+		Faces:          spec.die.Faces,
+		Rolls:          spec.rolls,
+		Modifier:       spec.modifier,
+		DieModifier:    spec.dieModifier,
+	}
+
+	if output.Total, err = strconv.Atoi(total); err != nil {
+		return DiceRoll{}, fmt.Errorf("%q is not a canonical roll: bad total %q", input, total)
+	}
+
+	for i, die := range strings.Split(dice, ",") {
+		value := strings.TrimRight(die, string([]rune{canonicalDropped, canonicalMax, canonicalMin}))
+
+		v, err := strconv.Atoi(value)
+		if err != nil || v < 1 || v > output.Faces || len(die)-len(value) > 1 {
+			return DiceRoll{}, fmt.Errorf("%q is not a canonical roll: bad die %q", input, die)
+		}
+
+		output.Results = append(output.Results, v)
+
+		switch flag := strings.TrimPrefix(die, value); {
+		case flag == string(canonicalDropped):
+			output.Dropped = append(output.Dropped, i)
+		case flag == string(canonicalMax) && v == output.Faces:
+			output.NaturalMax++
+		case flag == string(canonicalMin) && v == 1:
+			output.NaturalMin++
+		case flag != "":
+			return DiceRoll{}, fmt.Errorf("%q is not a canonical roll: bad die %q", input, die)
+		}
+	}
+
+	if hasFields {
+		for _, field := range strings.Split(fields, ";") {
+			if err = parseCanonicalField(&output, field); err != nil {
+				return DiceRoll{}, fmt.Errorf("%q is not a canonical roll: %w", input, err)
+			}
+		}
+	}
+
+	sumNatural(&output)
+
+	if spec.sort != "" {
+		sortResults(&output, spec.sort == "sd")
+	}
+
+	return
+}
+
+/*
+ * parseCanonicalField sets the field which follows a canonical roll's total, e.g. 'modifier=19', on the roll.
+ */
+func parseCanonicalField(output *DiceRoll, field string) error {
+	name, value, _ := strings.Cut(field, "=")
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("bad field %q", field)
+	}
+
+	switch {
+	case name == canonicalFieldRolls && v >= 1:
+		output.Rolls = v
+	case name == canonicalFieldModifier:
+		output.Modifier = v
+	case name == canonicalFieldMax && v >= 0:
+		output.NaturalMax = v
+	case name == canonicalFieldMin && v >= 0:
+		output.NaturalMin = v
+	default:
+		return fmt.Errorf("bad field %q", field)
+	}
+
+	return nil
+}
+
+/*
+ * MarshalText encodes a DiceRoll as FormatCanonical does, so it can be stored as one line in logs, URLs and flat files. It returns an
+ *   error for a roll which wouldn't decode back the same, such as one in another notation, e.g. from WithRoll20 or WithFoundry.
+ * e.g. roll.MarshalText() // []byte("4d6kh3+1=[3,1x,6,4]=14")
+ */
+func (roll DiceRoll) MarshalText() ([]byte, error) {
+	text := FormatCanonical(roll)
+
+	decoded, err := ParseCanonical(text)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode %q as text: %w", roll.DiscoveredRoll, err)
+	}
+
+	if !sameCanonical(decoded, roll) {
+		return nil, fmt.Errorf("can't encode %q as text: it wouldn't decode back the same", roll.DiscoveredRoll)
+	}
+
+	return []byte(text), nil
+}
+
+/*
+ * sameCanonical says whether two rolls are the same as far as the canonical text format goes: ignoring the case of the discovered
+ *   roll, the order of the dropped dice, and sets.
+ */
+func sameCanonical(a, b DiceRoll) bool {
+	return strings.EqualFold(a.DiscoveredRoll, b.DiscoveredRoll) && a.Faces == b.Faces && a.Rolls == b.Rolls &&
+		a.Modifier == b.Modifier && slices.Equal(a.Results, b.Results) && a.Total == b.Total && a.NaturalTotal == b.NaturalTotal &&
+		slices.Equal(a.Unsorted, b.Unsorted) && a.DieModifier == b.DieModifier && a.NaturalMax == b.NaturalMax &&
+		a.NaturalMin == b.NaturalMin && slices.Equal(slices.Sorted(slices.Values(a.Dropped)), slices.Sorted(slices.Values(b.Dropped)))
+}
+
+/*
+ * UnmarshalText decodes a DiceRoll encoded by MarshalText, as ParseCanonical does. The roll is unchanged if the text is bad.
+ * e.g. roll.UnmarshalText([]byte("4d6kh3+1=[3,1x,6,4]=14"))
+ */
+func (roll *DiceRoll) UnmarshalText(text []byte) error {
+	decoded, err := ParseCanonical(string(text))
+	if err != nil {
+		return err
+	}
+
+	*roll = decoded
+
+	return nil
+}
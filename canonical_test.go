@@ -0,0 +1,269 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+var formatCanonicalTests = map[string]string{
+	"1d6":        "1d6=[4]=4",
+	"3D6+2":      "3d6+2=[6,2,5]=15",
+	"1d20-1":     "1d20-1=[1f]=0",
+	"4d6kh3":     "4d6kh3=[3,1x,6,4]=13",
+	"5d8km3+1":   "5d8km3+1=[8x,2,5,7,1x]=15",
+	"d20adv+5":   "d20adv+5=[20c,11x]=25",
+	"2d20dis":    "2d20dis=[1f,1f,17x,20x]=2",
+	"4d6s++1":    "4d6s++1=[3,6,1,3]=17",
+	"3d8sd--1-2": "3d8sd--1-2=[4,2,7]=8",
+	"6d10":       "6d10=[7,3,7,3,7,9]=36",
+}
+
+// TestFormatCanonical calls diceroller.FormatCanonical with the golden rolls, checking for valid return values, and that
+// diceroller.ParseCanonical reads them back.
+func TestFormatCanonical(t *testing.T) {
+	for _, roll := range goldenRolls {
+		want := formatCanonicalTests[roll.DiscoveredRoll]
+
+		output := FormatCanonical(roll)
+		if output != want {
+			t.Errorf("have %q, wanted %q", output, want)
+		}
+
+		parsed, err := ParseCanonical(output)
+
+		// The roll comes back in lower case, with the dropped dice in order and without any sets.
+		roll.DiscoveredRoll = parsed.DiscoveredRoll
+		roll.Dropped = slices.Clone(roll.Dropped)
+		slices.Sort(roll.Dropped)
+		roll.Sets = nil
+
+		if !reflect.DeepEqual(parsed, roll) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", parsed, roll, err)
+		}
+	}
+}
+
+// TestParseCanonical rolls many dice, checking diceroller.FormatCanonical and diceroller.ParseCanonical round-trip them.
+func TestParseCanonical(t *testing.T) {
+	reseed()
+
+	for _, input := range []string{"1d20", "4d6kh3", "2d20adv", "3d20dis+2", "6d6sd--1", "5d6kl2s+3", "4d20km2s", "10d4"} {
+		for range 20 {
+			rolls, _ := RollDetails(input)
+			roll := rolls[0]
+			slices.Sort(roll.Dropped)
+
+			parsed, err := ParseCanonical(FormatCanonical(roll))
+			if !reflect.DeepEqual(parsed, roll) || err != nil {
+				t.Errorf("have %v, wanted %v, err %v", parsed, roll, err)
+			}
+		}
+	}
+}
+
+/*
+ * canonicalProducers rolls once with each way of making a DiceRoll in the package's notation, including house rules which change
+ *   a roll's modifier or number of dice.
+ */
+func canonicalProducers(t *testing.T) (output []DiceRoll) {
+	t.Helper()
+	reseed()
+
+	add := func(roll DiceRoll, err error) {
+		if err != nil {
+			t.Fatalf("have err %v", err)
+		}
+
+		output = append(output, roll)
+	}
+
+	rolls, err := RollDetails("2d6+1", "4d6kh3", "d20adv+5", "3d8sd--1-2", "4d20km2s")
+	for _, roll := range rolls {
+		add(roll, err)
+	}
+
+	sets, err := RollSets("10d10")
+	add(sets[0], err)
+
+	loaded, _ := NewWeightedDie(1, 1, 4)
+	add(RollDie(loaded, 3, 2))
+
+	bag, _ := NewBag(2, 2, 2)
+	add(bag.Roll(2, 1))
+
+	savage, err := RollSavage(8, 1)
+	add(savage.Trait, err)
+	add(savage.Wild, err)
+
+	coc, err := RollCoC(50, 1, 0)
+	add(coc.Roll, err)
+
+	wod, err := RollWoD(8, 10)
+	add(wod.Roll, err)
+
+	shadowrun, err := RollShadowrun(6, true)
+	add(shadowrun.Roll, err)
+
+	add(NewRoller(WithSeed(42, 1024), WithNaturalLanguage()).RollDetailsWith("roll two d six plus three"))
+	add(HouseRules{MinimumTotal: 22}.Damage("1d4+1", false))
+	add(HouseRules{}.Damage("1d8+3", true))
+	add(HouseRules{MaxDamageCrits: true}.Damage("1d8+3", true))
+
+	return
+}
+
+// TestParseCanonicalProducers checks diceroller.FormatCanonical and diceroller.ParseCanonical round-trip every kind of roll in the
+// package's notation, and that house-ruled rolls keep their own modifier and number of dice.
+func TestParseCanonicalProducers(t *testing.T) {
+	for _, roll := range canonicalProducers(t) {
+		roll.Sets = nil
+		roll.Dropped = slices.Clone(roll.Dropped)
+		slices.Sort(roll.Dropped)
+
+		parsed, err := ParseCanonical(FormatCanonical(roll))
+		if !reflect.DeepEqual(parsed, roll) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", parsed, roll, err)
+		}
+	}
+
+	for _, test := range []struct {
+		roll DiceRoll
+		want string
+	}{
+		{DiceRoll{DiscoveredRoll: "1d4+1", Faces: 4, Rolls: 1, Modifier: 19, Results: []int{3}, Total: 22}, "1d4+1=[3]=22;modifier=19"},
+		{DiceRoll{DiscoveredRoll: "1d8+3", Faces: 8, Rolls: 2, Modifier: 3, Results: []int{6, 6}, Total: 15}, "1d8+3=[6,6]=15;rolls=2"},
+		{DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{19}, Total: 19, NaturalMax: 1}, "1d20=[19]=19;max=1"},
+		{DiceRoll{DiscoveredRoll: "2d20", Faces: 20, Rolls: 2, Results: []int{20, 2}, Total: 22, NaturalMax: 1, NaturalMin: 1}, "2d20=[20c,2]=22;min=1"},
+	} {
+		if output := FormatCanonical(test.roll); output != test.want {
+			t.Errorf("have %q, wanted %q", output, test.want)
+		}
+	}
+}
+
+// TestParseCanonicalErrors calls diceroller.ParseCanonical with bad text, checking for errors.
+func TestParseCanonicalErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"2d6",
+		"2d6=[3,4]",
+		"nonsense=[3,4]=7",
+		"2d6=[]=0",
+		"2d6=[3,7]=10",
+		"2d6=[3,0]=3",
+		"2d6=[3,four]=7",
+		"2d6=[3,4q]=7",
+		"2d6=[3,4xx]=7",
+		"1d20=[19c]=19",
+		"2d6=[3,4]=seven",
+		"2d6=[3,4]=7;",
+		"2d6=[3,4]=7;rolls=0",
+		"2d6=[3,4]=7;modifier",
+		"2d6=[3,4]=7;max=-1",
+		"2d6=[3,4]=7;faces=8",
+	} {
+		if _, err := ParseCanonical(input); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+}
+
+// TestDiceRollText rolls many dice, checking a DiceRoll's MarshalText and UnmarshalText round-trip them, and that bad text
+// leaves the roll unchanged.
+func TestDiceRollText(t *testing.T) {
+	reseed()
+
+	for _, input := range []string{"2d6+1", "4d6kh3", "d20adv+5", "5d6kl2s+3", "3d8sd--1-2"} {
+		for range 20 {
+			rolls, _ := RollDetails(input)
+			roll := rolls[0]
+			slices.Sort(roll.Dropped)
+
+			text, err := roll.MarshalText()
+			if string(text) != FormatCanonical(roll) || err != nil {
+				t.Errorf("have %q, wanted %q, err %v", text, FormatCanonical(roll), err)
+			}
+
+			var decoded DiceRoll
+			if err := decoded.UnmarshalText(text); !reflect.DeepEqual(decoded, roll) || err != nil {
+				t.Errorf("have %v, wanted %v, err %v", decoded, roll, err)
+			}
+		}
+	}
+
+	for _, roll := range canonicalProducers(t) {
+		text, err := roll.MarshalText()
+		if err != nil {
+			t.Errorf("have err %v encoding %v, wanted nil", err, roll)
+			continue
+		}
+
+		var decoded DiceRoll
+		if err := decoded.UnmarshalText(text); !sameCanonical(decoded, roll) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", decoded, roll, err)
+		}
+	}
+
+	// Rolls in other notations can't be read back, so they can't be encoded.
+	for _, test := range []struct {
+		options []RollerOption
+		input   string
+	}{
+		{[]RollerOption{WithRoll20()}, "[[2d20kh1cs>15+5]]"},
+		{[]RollerOption{WithRoll20()}, "[[{2d6,3d6}kh1]]"},
+		{[]RollerOption{WithFoundry(nil)}, "4d6x"},
+		{[]RollerOption{WithDiceLetters('w')}, "2w6"},
+	} {
+		roll, err := NewRoller(append(test.options, WithSeed(42, 1024))...).RollDetailsWith(test.input)
+		if err != nil {
+			t.Fatalf("have err %v rolling %q", err, test.input)
+		}
+
+		if text, err := roll.MarshalText(); err == nil {
+			t.Errorf("have %q for %q, wanted an error", text, test.input)
+		}
+	}
+
+	roll := goldenRolls[0]
+	if err := roll.UnmarshalText([]byte("2d6=[3,7]=10")); err == nil || !reflect.DeepEqual(roll, goldenRolls[0]) {
+		t.Errorf("have %v, err %v, wanted an unchanged roll and an error", roll, err)
+	}
+}
+
+// BenchmarkParseCanonical benchmarks diceroller.ParseCanonical.
+func BenchmarkParseCanonical(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseCanonical("4d6kh3s+1=[3,1x,6,4]=14")
+	}
+}
+
+// BenchmarkDiceRollUnmarshalText benchmarks DiceRoll.UnmarshalText.
+func BenchmarkDiceRollUnmarshalText(b *testing.B) {
+	var (
+		roll DiceRoll
+		text = []byte("2d6+1=[4,3]=8")
+	)
+
+	for i := 0; i < b.N; i++ {
+		_ = roll.UnmarshalText(text)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// Feature is a piece of dice notation which rolls accept, so clients can tell what the backend supports, e.g. to hide buttons for notation it doesn't.
+type Feature struct {
+	Name        string `json:"name"`        // A stable name for the feature, e.g. 'keep'.
+	Example     string `json:"example"`     // A roll using it, e.g. '4d6kh3'.
+	Description string `json:"description"` // What it does, in words.
+}
+
+// The notation features rolls accept, in the order they were added.
+var features = []Feature{
+	{"dice", "2d6", "Roll a number of dice with a number of faces."},
+	{"modifier", "2d6+2", "Add or take a modifier off the total."},
+	{"keep", "4d6kh3", "Keep the highest (kh), lowest (kl) or middle (km) dice."},
+	{"advantage", "d20adv", "Roll twice and keep the highest (adv) or lowest (dis)."},
+	{"sort", "4d6sd", "Sort the results ascending (s) or descending (sd)."},
+	{"die-modifier", "4d6++1", "Add (++) or take (--) a modifier off every kept die."},
+}
+
+/*
+ * Capabilities returns the notation features rolls accept.
+ * e.g. Capabilities() // [{dice 2d6 ...} {modifier 2d6+2 ...} ...]
+ */
+func Capabilities() []Feature {
+	return append([]Feature(nil), features...)
+}
@@ -0,0 +1,45 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "testing"
+
+// TestCapabilities calls diceroller.Capabilities, checking every feature's example is a valid roll and every name is unique.
+func TestCapabilities(t *testing.T) {
+	names := map[string]bool{}
+
+	for _, feature := range Capabilities() {
+		if _, err := parseWholeRoll(feature.Example); err != nil {
+			t.Errorf("have err %v for the %s example %q, wanted nil", err, feature.Name, feature.Example)
+		}
+
+		if names[feature.Name] {
+			t.Errorf("have %q twice, wanted unique names", feature.Name)
+		}
+
+		names[feature.Name] = true
+	}
+
+	// Changing the returned slice doesn't change the capabilities.
+	Capabilities()[0].Name = "changed"
+
+	if Capabilities()[0].Name != "dice" {
+		t.Errorf("have %q, wanted %q", Capabilities()[0].Name, "dice")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChainPredicate decides whether a step in a chain is rolled, given the results of the steps rolled before it.
+type ChainPredicate func(results []ChainResult) bool
+
+// ChainStep is one roll in a chain, e.g. the damage after an attack, rolled some number of times if its predicate is met.
+type ChainStep struct {
+	Name  string         // What the step is called, so later steps can refer to it, e.g. 'attack'.
+	Roll  string         // The roll, e.g. '2d6+3'.
+	Times int            // How many times to roll it. Zero means once.
+	When  ChainPredicate // Whether to roll it. If nil, it's always rolled.
+}
+
+// ChainResult holds the rolls made for one step of a chain.
+type ChainResult struct {
+	Name  string     // The step's name.
+	Rolls []DiceRoll // Each roll made for the step.
+	Total int        // The rolls' totals, added up.
+}
+
+/*
+ * RollChain rolls each step in turn, skipping any whose predicate isn't met, and returns the results of the steps which were rolled,
+ *   e.g. an attack, then damage twice on a natural 20 or once on a hit. Every roll is checked before anything is rolled.
+ * e.g. RollChain(
+ *          ChainStep{Name: "attack", Roll: "1d20+5"},
+ *          ChainStep{Name: "damage", Roll: "2d6+3", Times: 2, When: OnCritical("attack")},
+ *          ChainStep{Name: "damage", Roll: "2d6+3", When: ChainAll(OnTotalAtLeast("attack", 15), ChainNot(OnCritical("attack")))},
+ *      ) // [{attack [...] 25} {damage [...] 21}]
+ */
+func RollChain(steps ...ChainStep) (output []ChainResult, err error) {
+	specs := make([]rollSpec, len(steps))
+
+	for i, step := range steps {
+		if specs[i], err = parseWholeRoll(step.Roll); err != nil {
+			return nil, fmt.Errorf("chain step %q: %w", step.Name, err)
+		}
+
+		if step.Times < 0 {
+			return nil, fmt.Errorf("chain step %q can't be rolled %d times", step.Name, step.Times)
+		}
+	}
+
+	for i, step := range steps {
+		if step.When != nil && !step.When(output) {
+			continue
+		}
+
+		result := ChainResult{Name: step.Name}
+
+		for range max(step.Times, 1) {
+			dr, err := postProcess(specs[i].roll())
+			if err != nil {
+				return nil, err
+			}
+
+			result.Rolls = append(result.Rolls, dr)
+			result.Total += dr.Total
+		}
+
+		output = append(output, result)
+	}
+
+	return
+}
+
+/*
+ * lastChainResult returns the latest result of the step with a name, and whether it was rolled.
+ */
+func lastChainResult(results []ChainResult, name string) (ChainResult, bool) {
+	for i := len(results) - 1; i >= 0; i-- {
+		if results[i].Name == name {
+			return results[i], true
+		}
+	}
+
+	return ChainResult{}, false
+}
+
+/*
+ * OnCritical is met when the latest roll of a named step came up on a natural max on any kept critical die, e.g. a natural 20.
+ */
+func OnCritical(name string) ChainPredicate {
+	return func(results []ChainResult) bool {
+		result, ok := lastChainResult(results, name)
+
+		return ok && result.Rolls[len(result.Rolls)-1].NaturalMax > 0
+	}
+}
+
+/*
+ * OnFumble is met when the latest roll of a named step came up a natural 1 on any kept critical die.
+ */
+func OnFumble(name string) ChainPredicate {
+	return func(results []ChainResult) bool {
+		result, ok := lastChainResult(results, name)
+
+		return ok && result.Rolls[len(result.Rolls)-1].NaturalMin > 0
+	}
+}
+
+/*
+ * OnTotalAtLeast is met when the named step was rolled and its total met or beat a target, e.g. an attack against an AC.
+ */
+func OnTotalAtLeast(name string, target int) ChainPredicate {
+	return func(results []ChainResult) bool {
+		result, ok := lastChainResult(results, name)
+
+		return ok && result.Total >= target
+	}
+}
+
+/*
+ * ChainNot is met when a predicate isn't.
+ */
+func ChainNot(predicate ChainPredicate) ChainPredicate {
+	return func(results []ChainResult) bool {
+		return !predicate(results)
+	}
+}
+
+/*
+ * ChainAll is met when every predicate is.
+ */
+func ChainAll(predicates ...ChainPredicate) ChainPredicate {
+	return func(results []ChainResult) bool {
+		for _, predicate := range predicates {
+			if !predicate(results) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+/*
+ * ChainAny is met when at least one predicate is.
+ */
+func ChainAny(predicates ...ChainPredicate) ChainPredicate {
+	return func(results []ChainResult) bool {
+		for _, predicate := range predicates {
+			if predicate(results) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+/*
+ * PrettifyChain returns a chain's results as one line, with the full breakdown of each roll.
+ * e.g. "attack: 1d20+5: 20 (+5) = 25 (nat 20); damage: 2d6+3: 3 + 4 (+3) = 10, 2d6+3: 6 + 1 (+3) = 10 (total 20)"
+ */
+func PrettifyChain(results []ChainResult) string {
+	steps := make([]string, len(results))
+
+	for i, result := range results {
+		steps[i] = result.Name + ": " + strings.Join(PrettifyFull(result.Rolls), ", ")
+
+		if len(result.Rolls) > 1 {
+			steps[i] += fmt.Sprintf(" (total %d)", result.Total)
+		}
+	}
+
+	return strings.Join(steps, "; ")
+}
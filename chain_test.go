@@ -0,0 +1,116 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "testing"
+
+// An attack against AC 15 which does double damage on a natural 20, and nothing on a miss or a natural 1.
+var chainAttack = []ChainStep{
+	{Name: "attack", Roll: "1d20+5"},
+	{Name: "damage", Roll: "2d6+3", Times: 2, When: OnCritical("attack")},
+	{Name: "damage", Roll: "2d6+3", When: ChainAll(OnTotalAtLeast("attack", 15), ChainNot(ChainAny(OnCritical("attack"), OnFumble("attack"))))},
+}
+
+var chainAttackWant = []string{
+	"attack: 1d20+5: 19 (+5) = 24; damage: 2d6+3: 3 + 6 (+3) = 12",
+	"attack: 1d20+5: 11 (+5) = 16; damage: 2d6+3: 6 + 1 (+3) = 10",
+	"attack: 1d20+5: 8 (+5) = 13",
+	"attack: 1d20+5: 13 (+5) = 18; damage: 2d6+3: 2 + 2 (+3) = 7",
+	"attack: 1d20+5: 3 (+5) = 8",
+	"attack: 1d20+5: 20 (+5) = 25 (nat 20); damage: 2d6+3: 3 + 6 (+3) = 12, 2d6+3: 5 + 2 (+3) = 10 (total 22)",
+}
+
+// TestRollChain calls diceroller.RollChain with an attack and damage, checking the right steps are rolled.
+func TestRollChain(t *testing.T) {
+	reseed()
+
+	for _, want := range chainAttackWant {
+		output, err := RollChain(chainAttack...)
+
+		if PrettifyChain(output) != want || err != nil {
+			t.Errorf("have %q, wanted %q, err %v", PrettifyChain(output), want, err)
+		}
+	}
+
+	// The last chain was a critical: two damage rolls, totalled.
+	reseed()
+
+	var output []ChainResult
+	for range chainAttackWant {
+		output, _ = RollChain(chainAttack...)
+	}
+
+	if len(output) != 2 || output[0].Total != 25 || len(output[1].Rolls) != 2 || output[1].Total != 22 {
+		t.Errorf("have %v, wanted an attack of 25 and two damage rolls totalling 22", output)
+	}
+
+	for _, steps := range [][]ChainStep{
+		{{Name: "attack", Roll: "1d20+5"}, {Name: "damage", Roll: "2d6 please"}},
+		{{Name: "attack", Roll: "1d0"}},
+		{{Name: "attack", Roll: "1d20", Times: -1}},
+	} {
+		if output, err := RollChain(steps...); err == nil || output != nil {
+			t.Errorf("have %v, nil error for %v, wanted an error", output, steps)
+		}
+	}
+}
+
+// TestChainPredicates calls each chain predicate with some results, checking for valid return values.
+func TestChainPredicates(t *testing.T) {
+	results := []ChainResult{
+		{Name: "attack", Rolls: []DiceRoll{{NaturalMax: 1}}, Total: 25},
+		{Name: "save", Rolls: []DiceRoll{{NaturalMin: 1}}, Total: 3},
+		{Name: "attack", Rolls: []DiceRoll{{}}, Total: 12},
+	}
+
+	predicates := map[string]ChainPredicate{
+		"critical attack":         OnCritical("attack"),
+		"fumbled save":            OnFumble("save"),
+		"missing":                 OnTotalAtLeast("missing", 0),
+		"attack at least 12":      OnTotalAtLeast("attack", 12),
+		"attack at least 13":      OnTotalAtLeast("attack", 13),
+		"not a fumbled save":      ChainNot(OnFumble("save")),
+		"all":                     ChainAll(OnFumble("save"), OnTotalAtLeast("attack", 12)),
+		"all, one missing":        ChainAll(OnFumble("save"), OnTotalAtLeast("missing", 0)),
+		"any":                     ChainAny(OnCritical("attack"), OnFumble("save")),
+		"any, none met":           ChainAny(OnCritical("attack"), OnCritical("save")),
+		"all, with no predicates": ChainAll(),
+	}
+
+	want := map[string]bool{
+		"fumbled save": true, "attack at least 12": true, "all": true, "any": true, "all, with no predicates": true,
+	}
+
+	for name, predicate := range predicates {
+		if predicate(results) != want[name] {
+			t.Errorf("have %v, wanted %v for %q", predicate(results), want[name], name)
+		}
+	}
+
+	if PrettifyChain(nil) != "" {
+		t.Errorf("have %q, wanted an empty string", PrettifyChain(nil))
+	}
+}
+
+// BenchmarkRollChain benchmarks diceroller.RollChain.
+func BenchmarkRollChain(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollChain(chainAttack...)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// Check holds the details of a roll made against a DC (difficulty class).
+type Check struct {
+	Roll     DiceRoll // The roll.
+	DC       int      // The DC it was made against.
+	Success  bool     // Whether the roll's total met or beat the DC. HouseRules can make criticals and fumbles succeed or fail outright.
+	Margin   int      // The roll's total minus the DC: zero or more on a success, negative on a failure.
+	Critical bool     // Whether a single d20 came up a natural 20, or in the crit range of any HouseRules used.
+	Fumble   bool     // Whether a single d20 came up a natural 1, or in the fumble range of any HouseRules used.
+}
+
+// GroupCheck holds the details of a group of rolls made against the same DC, such as a party sneaking past a guard.
+type GroupCheck struct {
+	Checks    []Check // Each roll, in the order given.
+	Successes int     // How many of the rolls succeeded.
+	Success   bool    // Whether at least half of the rolls succeeded, so the group as a whole does.
+}
+
+/*
+ * RollAgainstDC accepts one string in the correct 'nDn+n' format, rolls it, and compares the total to the DC.
+ * e.g. RollAgainstDC("1d20+5", 15) // {{1d20+5 20 1 5 [12] 17 ...} 15 true 2 false false}
+ */
+func RollAgainstDC(input string, dc int) (output Check, err error) {
+	return HouseRules{}.Check(input, dc)
+}
+
+/*
+ * RollGroupAgainstDC accepts a DC and one or more strings in the correct 'nDn+n' format, one per member of the group, rolls them all and
+ *   compares each total to the DC. The group succeeds if at least half of its members do.
+ * e.g. RollGroupAgainstDC(12, "1d20+3", "1d20", "1d20+1") // {[...] 2 true}
+ */
+func RollGroupAgainstDC(dc int, input ...string) (output GroupCheck, err error) {
+	var check Check
+
+	for _, in := range input {
+		check, err = RollAgainstDC(in, dc)
+		if err != nil {
+			return
+		}
+
+		output.Checks = append(output.Checks, check)
+
+		if check.Success {
+			output.Successes++
+		}
+	}
+
+	output.Success = len(output.Checks) > 0 && output.Successes*2 >= len(output.Checks)
+
+	return
+}
+
+/*
+ * newCheck compares a roll's total to a DC.
+ */
+func newCheck(dr DiceRoll, dc int) Check {
+	return Check{
+		Roll:    dr,
+		DC:      dc,
+		Success: dr.Total >= dc,
+		Margin:  dr.Total - dc,
+	}
+}
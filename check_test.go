@@ -0,0 +1,86 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rollAgainstDCTest struct {
+	got  string
+	dc   int
+	want Check
+}
+
+var rollAgainstDCTests = []rollAgainstDCTest{
+	{"1d20+5", 24, Check{DiceRoll{DiscoveredRoll: "1d20+5", Faces: 20, Rolls: 1, Modifier: 5, Results: []int{19}, Total: 24, NaturalTotal: 19}, 24, true, 0, false, false}},
+	{"1d20+3", 15, Check{DiceRoll{DiscoveredRoll: "1d20+3", Faces: 20, Rolls: 1, Modifier: 3, Results: []int{10}, Total: 13, NaturalTotal: 10}, 15, false, -2, false, false}},
+}
+
+// TestRollAgainstDC calls diceroller.RollAgainstDC with valid dice roll strings and DCs, checking for valid return values.
+func TestRollAgainstDC(t *testing.T) {
+	reseed()
+
+	for _, test := range rollAgainstDCTests {
+		output, err := RollAgainstDC(test.got, test.dc)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	if _, err := RollAgainstDC("nonsense", 10); err == nil {
+		t.Errorf("have nil error for no roll, wanted an error")
+	}
+}
+
+// BenchmarkRollAgainstDC benchmarks diceroller.RollAgainstDC.
+func BenchmarkRollAgainstDC(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollAgainstDC(rollAgainstDCTests[0].got, rollAgainstDCTests[0].dc)
+	}
+}
+
+type rollGroupAgainstDCTest struct {
+	dc             int
+	got            []string
+	wantSuccesses  int
+	wantSuccess    bool
+	wantChecksMade int
+}
+
+var rollGroupAgainstDCTests = []rollGroupAgainstDCTest{
+	{14, []string{"1d20+5", "1d20+3", "1d20", "1d20"}, 2, true, 4},
+	{14, []string{"1d20", "1d20", "1d20"}, 1, false, 3},
+	{14, nil, 0, false, 0},
+}
+
+// TestRollGroupAgainstDC calls diceroller.RollGroupAgainstDC with groups of valid dice roll strings, checking for valid return values.
+func TestRollGroupAgainstDC(t *testing.T) {
+	reseed()
+
+	for _, test := range rollGroupAgainstDCTests {
+		output, err := RollGroupAgainstDC(test.dc, test.got...)
+
+		if output.Successes != test.wantSuccesses || output.Success != test.wantSuccess || len(output.Checks) != test.wantChecksMade || err != nil {
+			t.Errorf("have %v, wanted %d successes, success %v, err %v", output, test.wantSuccesses, test.wantSuccess, err)
+		}
+	}
+}
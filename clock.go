@@ -0,0 +1,115 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Clock is a progress clock, as used by Blades in the Dark and PbtA fronts: a number of segments which fill as things happen,
+// until it's complete. It has JSON tags, so it can be saved between sessions.
+type Clock struct {
+	Name     string `json:"name"`     // What the clock tracks, e.g. 'The alarm is raised'.
+	Segments int    `json:"segments"` // How many segments it has, usually 4, 6 or 8.
+	Filled   int    `json:"filled"`   // How many segments are filled.
+}
+
+// ClockTicks says how many segments a roll ticks a clock by, e.g. 2 on a miss and none on a hit.
+type ClockTicks func(roll DiceRoll) int
+
+/*
+ * NewClock returns an empty Clock with a number of segments.
+ * e.g. NewClock("The alarm is raised", 6)
+ */
+func NewClock(name string, segments int) (*Clock, error) {
+	if segments < 1 {
+		return nil, fmt.Errorf("a clock needs at least one segment, not %d", segments)
+	}
+
+	return &Clock{Name: name, Segments: segments}, nil
+}
+
+/*
+ * Tick fills a number of segments (or empties them, if negative), stopping at empty and full, and returns whether the clock is complete.
+ */
+func (clock *Clock) Tick(segments int) bool {
+	clock.Filled = min(max(clock.Filled+segments, 0), clock.Segments)
+
+	return clock.Complete()
+}
+
+/*
+ * TickFor ticks the clock by however many segments a roll's outcome is worth, and returns whether the clock is complete.
+ * e.g. clock.TickFor(move.Roll, func(roll DiceRoll) int { if roll.Total <= 6 { return 2 }; return 0 })
+ */
+func (clock *Clock) TickFor(roll DiceRoll, ticks ClockTicks) bool {
+	return clock.Tick(ticks(roll))
+}
+
+/*
+ * Complete returns whether every segment is filled.
+ */
+func (clock *Clock) Complete() bool {
+	return clock.Filled >= clock.Segments
+}
+
+/*
+ * Reset empties the clock.
+ */
+func (clock *Clock) Reset() {
+	clock.Filled = 0
+}
+
+/*
+ * UnmarshalJSON loads a saved Clock, checking it has at least one segment, and no more filled than it has, or fewer than none.
+ */
+func (clock *Clock) UnmarshalJSON(data []byte) error {
+	type saved Clock // Without Clock's methods, so decoding it doesn't come back here.
+
+	var input saved
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+
+	if input.Segments < 1 || input.Filled < 0 || input.Filled > input.Segments {
+		return fmt.Errorf("a clock can't have %d of %d segments filled", input.Filled, input.Segments)
+	}
+
+	*clock = Clock(input)
+
+	return nil
+}
+
+/*
+ * String returns the clock's name and segments, filled ones first. Filled segments out of range, e.g. in a Clock made without
+ *   NewClock, are shown as empty or full.
+ * e.g. "The alarm is raised: [###---] 3/6"
+ */
+func (clock Clock) String() string {
+	filled := min(max(clock.Filled, 0), max(clock.Segments, 0))
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", max(clock.Segments, 0)-filled)
+
+	if clock.Name == "" {
+		return fmt.Sprintf("[%s] %d/%d", bar, clock.Filled, clock.Segments)
+	}
+
+	return fmt.Sprintf("%s: [%s] %d/%d", clock.Name, bar, clock.Filled, clock.Segments)
+}
@@ -0,0 +1,96 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestClock ticks a diceroller.Clock up and down, checking it stays in range, completes, and formats.
+func TestClock(t *testing.T) {
+	clock, err := NewClock("The alarm is raised", 6)
+	if err != nil {
+		t.Fatalf("have err %v, wanted nil", err)
+	}
+
+	if complete := clock.Tick(3); complete || clock.String() != "The alarm is raised: [###---] 3/6" {
+		t.Errorf("have %q complete %v, wanted %q false", clock, complete, "The alarm is raised: [###---] 3/6")
+	}
+
+	if clock.Tick(-5); clock.Filled != 0 {
+		t.Errorf("have %d filled, wanted 0", clock.Filled)
+	}
+
+	missTicks := func(roll DiceRoll) int {
+		if pbtaOutcome(roll.Total) == PbtAMiss {
+			return 2
+		}
+
+		return 0
+	}
+
+	for _, total := range []int{10, 5, 8, 2, 6} {
+		clock.TickFor(DiceRoll{Total: total}, missTicks)
+	}
+
+	if !clock.Complete() || clock.Filled != 6 {
+		t.Errorf("have %v, wanted a complete clock", clock)
+	}
+
+	clock.Reset()
+	if unnamed := (Clock{Segments: 4, Filled: 1}); clock.Filled != 0 || unnamed.String() != "[#---] 1/4" {
+		t.Errorf("have %d filled and %q, wanted 0 and %q", clock.Filled, unnamed, "[#---] 1/4")
+	}
+
+	if _, err := NewClock("nothing", 0); err == nil {
+		t.Errorf("have nil error for no segments, wanted an error")
+	}
+}
+
+// TestClockJSON saves and loads a diceroller.Clock, checking it comes back the same, and that malformed clocks don't load.
+func TestClockJSON(t *testing.T) {
+	clock := Clock{Name: "Doom", Segments: 8, Filled: 5}
+
+	saved, err := json.Marshal(clock)
+	if want := `{"name":"Doom","segments":8,"filled":5}`; string(saved) != want || err != nil {
+		t.Errorf("have %s, wanted %s, err %v", saved, want, err)
+	}
+
+	var loaded Clock
+	if err := json.Unmarshal(saved, &loaded); loaded != clock || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", loaded, clock, err)
+	}
+
+	for _, input := range []string{`{"segments":4,"filled":5}`, `{"segments":4,"filled":-1}`, `{"segments":0}`, `{"segments":"four"}`} {
+		loaded := Clock{Name: "Unchanged"}
+		if err := json.Unmarshal([]byte(input), &loaded); err == nil || loaded.Name != "Unchanged" {
+			t.Errorf("have %v, nil error for %s, wanted an error and the clock unchanged", loaded, input)
+		}
+	}
+
+	// A clock out of range some other way still formats.
+	if output := (Clock{Segments: 4, Filled: 5}).String(); output != "[####] 5/4" {
+		t.Errorf("have %q, wanted %q", output, "[####] 5/4")
+	}
+
+	if output := (Clock{Segments: -1, Filled: -2}).String(); output != "[] -2/-1" {
+		t.Errorf("have %q, wanted %q", output, "[] -2/-1")
+	}
+}
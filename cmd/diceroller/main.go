@@ -0,0 +1,130 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Command diceroller rolls dice, and answers questions about them, from the command line.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vaughany/diceroller"
+)
+
+const usage = `Usage:
+  diceroller [roll] <roll>... [--overlay dir] [--roll20]
+                                   Roll dice, e.g. 'diceroller 2d6 4d4+1', optionally writing them to an OBS overlay in dir, or
+                                   reading Roll20's notation, e.g. 'diceroller --roll20 "[[{1d20+5, 1d20+8}kh1]]"'.
+  diceroller stats <roll>          Print the chance of each total of a roll, e.g. 'diceroller stats 4d6kh3'.
+  diceroller odds "<question>"     Answer a question about a roll, e.g. 'diceroller odds "at least 18 on 3d6"'.
+  diceroller sim <roll> [-n 1e6] [--csv | --chart]
+                                   Roll a roll many times and print how often each total came up, optionally as CSV or a bar chart.
+`
+
+// A subcommand takes the arguments after its name, and returns an error to be printed if it fails. Anything other than its output,
+// such as a summary alongside CSV, goes to stderr.
+type subcommand func(args []string, stdout, stderr io.Writer) error
+
+var subcommands = map[string]subcommand{
+	"roll":  roll,
+	"stats": stats,
+	"odds":  odds,
+	"sim":   sim,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+/*
+ * run runs the subcommand named by the first argument, or 'roll' if it isn't one, and returns the exit code.
+ */
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	command, ok := subcommands[args[0]]
+	if ok {
+		args = args[1:]
+	} else {
+		command = roll
+	}
+
+	if err := command(args, stdout, stderr); err != nil {
+		fmt.Fprintf(stderr, "diceroller: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+/*
+ * roll rolls each argument and prints the results, one per line. With --overlay, the rolls are also written to overlay.json and
+ *   overlay.html in the given directory, for an OBS browser source. With --roll20, they're read as Roll20's notation.
+ */
+func roll(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("roll", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		overlay = flags.String("overlay", "", "a directory to write an OBS overlay to")
+		roll20  = flags.Bool("roll20", false, "read rolls in Roll20's notation")
+		inputs  []string
+	)
+
+	// As with sim, the flags can come before or after the rolls.
+	for {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		if flags.NArg() == 0 {
+			break
+		}
+
+		inputs = append(inputs, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(inputs) == 0 {
+		return fmt.Errorf("nothing to roll, e.g. 'diceroller 2d6'")
+	}
+
+	var options []diceroller.RollerOption
+	if *roll20 {
+		options = append(options, diceroller.WithRoll20())
+	}
+
+	rolls, err := diceroller.NewRoller(options...).RollDetails(inputs...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, strings.Join(diceroller.PrettifyFull(rolls), "\n"))
+
+	if *overlay != "" {
+		return diceroller.Overlay{Dir: *overlay}.Write(rolls...)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vaughany/diceroller"
+)
+
+type runTest struct {
+	args       []string
+	wantCode   int
+	wantStdout []string // Lines which should be in stdout, in order.
+}
+
+var runTests = []runTest{
+	{[]string{"stats", "2d6"}, 0, []string{"2d6: 2 to 12, mean 7.00, standard deviation 2.42", "     7   16.67%   58.33%   58.33%", "    12    2.78%    2.78%  100.00%"}},
+	{[]string{"stats", "4d6kh3"}, 0, []string{"4d6kh3: 3 to 18, mean 12.24, standard deviation 2.85"}},
+	{[]string{"odds", "at least 18 on 3d6"}, 0, []string{"0.46% (1 in 216)"}},
+	{[]string{"odds", "What", "are", "the", "odds", "of", "rolling", "over", "10", "on", "2d6?"}, 0, []string{"8.33% (1 in 12)"}},
+	{[]string{"odds", "exactly 30 on 2d6"}, 0, []string{"0.00% (never)"}},
+	{[]string{"roll", "1d1+1", "2d1"}, 0, []string{"1d1+1: 1 (+1) = 2", "2d1: 1 + 1 = 2"}},
+	{[]string{"roll", "--roll20", "[[d1+1]]", "{2d1, 3d1}kh1"}, 0, []string{"d1+1: 1 (+1) = 2", "{2d1,3d1}kh1: ~~2~~ + 3 = 3"}},
+	{[]string{"3d1"}, 0, []string{"3d1: 1 + 1 + 1 = 3"}},
+	{[]string{"sim", "1d1", "-n", "1000", "--csv"}, 0, []string{"total,count,frequency", "1,1000,1"}},
+	{[]string{"sim", "1d1", "-n", "10", "--chart"}, 0, []string{"1d1, 10 rolls: 1 to 1, mean 1.00, standard deviation 0.00", "1 | ######################################## 100.00%"}},
+	{[]string{"sim", "-n", "10", "1d1+1"}, 0, []string{"1d1+1, 10 rolls: 2 to 2, mean 2.00, standard deviation 0.00", "     2         10    100.00%"}},
+	{[]string{"stats"}, 1, nil},
+	{[]string{"stats", "2d6", "1d4"}, 1, nil},
+	{[]string{"stats", "2d0"}, 1, nil},
+	{[]string{"odds"}, 1, nil},
+	{[]string{"odds", "what is 2d6"}, 1, nil},
+	{[]string{"sim"}, 1, nil},
+	{[]string{"sim", "1d6", "2d6"}, 1, nil},
+	{[]string{"sim", "1d6", "-n", "0"}, 1, nil},
+	{[]string{"sim", "1d6", "-n", "1.5"}, 1, nil},
+	{[]string{"sim", "1d6", "--bogus"}, 1, nil},
+	{[]string{"sim", "1d0"}, 1, nil},
+	{[]string{"roll"}, 1, nil},
+	{[]string{"roll", "nothing"}, 1, nil},
+	{[]string{"roll", "--roll20", "2d6r<2"}, 1, nil},
+	{[]string{"roll", "1d6", "--overlay", "/nonexistent/overlay"}, 1, nil},
+	{[]string{}, 2, nil},
+	{[]string{"help"}, 2, nil},
+}
+
+// TestRun calls run with many arguments, checking the exit code and output.
+func TestRun(t *testing.T) {
+	for _, test := range runTests {
+		var stdout, stderr bytes.Buffer
+
+		code := run(test.args, &stdout, &stderr)
+		if code != test.wantCode {
+			t.Errorf("have exit code %d, wanted %d for %q, stderr %q", code, test.wantCode, test.args, stderr.String())
+		}
+
+		if code != 0 && stderr.Len() == 0 {
+			t.Errorf("have no error output for %q, wanted some", test.args)
+		}
+
+		output := stdout.String()
+		for _, want := range test.wantStdout {
+			index := strings.Index(output, want+"\n")
+			if index == -1 {
+				t.Errorf("have %q, wanted it to contain %q for %q", stdout.String(), want, test.args)
+				break
+			}
+
+			output = output[index+len(want):]
+		}
+	}
+}
+
+// TestRunOverlay calls run with --overlay, checking the rolls are printed and written to the overlay files.
+func TestRunOverlay(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	dir := t.TempDir()
+	if code := run([]string{"1d1", "--overlay", dir, "2d1"}, &stdout, &stderr); code != 0 || stdout.String() != "1d1: 1\n2d1: 1 + 1 = 2\n" {
+		t.Errorf("have exit code %d and %q, wanted 0 and both rolls, stderr %q", code, stdout.String(), stderr.String())
+	}
+
+	for _, name := range []string{diceroller.OverlayJSONFile, diceroller.OverlayHTMLFile} {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err != nil || !strings.Contains(string(data), "2d1: 1 ") {
+			t.Errorf("have %q, wanted %s to contain the rolls, err %v", data, name, err)
+		}
+	}
+}
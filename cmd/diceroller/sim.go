@@ -0,0 +1,112 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/vaughany/diceroller"
+)
+
+/*
+ * sim rolls a roll many times and prints a summary and how often each total came up. With --csv, the totals are written as CSV and
+ *   the summary goes to stderr, so the CSV can be piped straight into a spreadsheet. With --chart, they're drawn as a bar chart.
+ */
+func sim(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("sim", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var (
+		rolls  = flags.Float64("n", 100_000, "how many times to roll, e.g. 1e6")
+		asCSV  = flags.Bool("csv", false, "write the totals as CSV")
+		chart  = flags.Bool("chart", false, "draw the totals as a bar chart")
+		inputs []string
+	)
+
+	// The flags can come before or after the roll, so keep parsing after each argument which isn't a flag.
+	for {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		if flags.NArg() == 0 {
+			break
+		}
+
+		inputs = append(inputs, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+
+	if len(inputs) != 1 {
+		return fmt.Errorf("sim takes one roll, e.g. 'diceroller sim 3d6 -n 1e6'")
+	}
+
+	if *rolls != math.Trunc(*rolls) || *rolls < 1 || *rolls > math.MaxInt32 {
+		return fmt.Errorf("-n must be a whole number of rolls from 1 to %d, got %v", math.MaxInt32, *rolls)
+	}
+
+	simulation, err := diceroller.Simulate(inputs[0], int(*rolls))
+	if err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("%s, %d rolls: %d to %d, mean %.2f, standard deviation %.2f\n", simulation.Roll, simulation.Rolls,
+		simulation.Min, simulation.Max, simulation.Mean, simulation.StdDev)
+
+	if *asCSV {
+		fmt.Fprint(stderr, summary)
+		return writeSimulationCSV(stdout, simulation)
+	}
+
+	if *chart {
+		fmt.Fprintf(stdout, "%s\n%s", summary, simulation.Histogram().Render())
+		return nil
+	}
+
+	fmt.Fprintf(stdout, "%s\n%6s %10s %10s\n", summary, "Total", "Count", "Frequency")
+
+	for i, count := range simulation.Counts {
+		total := simulation.Min + i
+		fmt.Fprintf(stdout, "%6d %10d %10s\n", total, count, percent(simulation.Frequency(total)))
+	}
+
+	return nil
+}
+
+/*
+ * writeSimulationCSV writes each total of a simulation, how many times it came up, and how often, as CSV with a header.
+ */
+func writeSimulationCSV(w io.Writer, simulation diceroller.Simulation) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"total", "count", "frequency"})
+
+	for i, count := range simulation.Counts {
+		total := simulation.Min + i
+		_ = writer.Write([]string{strconv.Itoa(total), strconv.Itoa(count), strconv.FormatFloat(simulation.Frequency(total), 'f', -1, 64)})
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
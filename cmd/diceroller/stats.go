@@ -0,0 +1,86 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vaughany/diceroller"
+)
+
+/*
+ * stats prints the chance of rolling each total of a roll, exactly, at least and at most.
+ */
+func stats(args []string, stdout, _ io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("stats takes one roll, e.g. 'diceroller stats 3d6'")
+	}
+
+	dist, err := diceroller.Distribution(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s: %d to %d, mean %.2f, standard deviation %.2f\n\n", dist.Roll, dist.Min, dist.Max, dist.Mean, dist.StdDev)
+	fmt.Fprintf(stdout, "%6s %8s %8s %8s\n", "Total", "Exactly", "At least", "At most")
+
+	for total := dist.Min; total <= dist.Max; total++ {
+		fmt.Fprintf(stdout, "%6d %8s %8s %8s\n", total, percent(dist.Exactly(total)), percent(dist.AtLeast(total)), percent(dist.AtMost(total)))
+	}
+
+	return nil
+}
+
+/*
+ * odds prints the answer to a question about a roll, such as 'at least 18 on 3d6'. The question can be one argument or several.
+ */
+func odds(args []string, stdout, _ io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("odds takes a question, e.g. 'diceroller odds \"at least 18 on 3d6\"'")
+	}
+
+	odds, err := diceroller.Odds(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s (%s)\n", percent(odds), oneIn(odds))
+
+	return nil
+}
+
+/*
+ * percent returns a probability as a percentage, to two decimal places.
+ */
+func percent(probability float64) string {
+	return fmt.Sprintf("%.2f%%", probability*100)
+}
+
+/*
+ * oneIn returns a probability as one in however many, e.g. '1 in 216' for 1/216, or 'never' for 0.
+ */
+func oneIn(probability float64) string {
+	if probability == 0 {
+		return "never"
+	}
+
+	return fmt.Sprintf("1 in %.4g", 1/probability)
+}
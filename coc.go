@@ -0,0 +1,137 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "errors"
+
+// CoCSuccess is how a Call of Cthulhu skill roll turned out.
+type CoCSuccess int
+
+const (
+	CoCFailure         CoCSuccess = iota // Rolled over the skill.
+	CoCFumble                            // Rolled 100, or 96 or more when the skill is under 50.
+	CoCRegularSuccess                    // Rolled the skill or under.
+	CoCHardSuccess                       // Rolled half the skill or under.
+	CoCExtremeSuccess                    // Rolled a fifth of the skill or under.
+	CoCCriticalSuccess                   // Rolled 1.
+)
+
+/*
+ * String returns the success level in words.
+ * e.g. "hard success"
+ */
+func (level CoCSuccess) String() string {
+	switch level {
+	case CoCFumble:
+		return "fumble"
+	case CoCRegularSuccess:
+		return "regular success"
+	case CoCHardSuccess:
+		return "hard success"
+	case CoCExtremeSuccess:
+		return "extreme success"
+	case CoCCriticalSuccess:
+		return "critical success"
+	default:
+		return "failure"
+	}
+}
+
+// CoCRoll holds the details of a Call of Cthulhu skill roll.
+type CoCRoll struct {
+	Roll  DiceRoll   // The d100 roll, with the tens die chosen from Tens.
+	Units int        // The units die, 0 to 9.
+	Tens  []int      // Each tens die rolled, 0 to 90: one, plus one for each bonus or penalty die.
+	Skill int        // The skill value rolled against.
+	Level CoCSuccess // How it turned out.
+}
+
+/*
+ * RollCoC rolls d100 against a skill value per the Call of Cthulhu 7th edition rules. Bonus and penalty dice cancel each other out,
+ *   and each one left over rolls another tens die, taking the best (with bonus dice) or worst (with penalty dice) result.
+ * e.g. RollCoC(60, 1, 0) // {{1d100 100 1 0 [49] 49 ...} 9 [40 90] 60 regular success}
+ */
+func RollCoC(skill, bonus, penalty int) (output CoCRoll, err error) {
+	return defaultRoller.RollCoC(skill, bonus, penalty)
+}
+
+/*
+ * RollCoC is like the package's RollCoC, but rolls with the Roller's random source.
+ * e.g. roller.RollCoC(60, 0, 1)
+ */
+func (roller *Roller) RollCoC(skill, bonus, penalty int) (output CoCRoll, err error) {
+	if bonus < 0 || penalty < 0 {
+		err = errors.New("bonus and penalty dice can't be negative")
+		return
+	}
+
+	var (
+		tens   = DieSpec{Faces: 10}
+		source = roller.source()
+	)
+
+	output.Units = tens.rollFrom(source) - 1
+
+	extra := bonus - penalty
+	output.Tens = make([]int, 1+max(extra, -extra))
+
+	var result int
+	for i := range output.Tens {
+		output.Tens[i] = (tens.rollFrom(source) - 1) * 10
+
+		value := output.Tens[i] + output.Units
+		if value == 0 {
+			value = 100
+		}
+
+		if i == 0 || (extra > 0 && value < result) || (extra < 0 && value > result) {
+			result = value
+		}
+	}
+
+	output.Roll, err = postProcess(DiceRoll{DiscoveredRoll: "1d100", Faces: 100, Rolls: 1, Results: []int{result}, Total: result})
+	if err != nil {
+		return
+	}
+
+	output.Skill = skill
+	output.Level = cocSuccess(output.Roll.Total, skill)
+
+	return
+}
+
+/*
+ * cocSuccess works out the success level of a d100 result against a skill value.
+ */
+func cocSuccess(result, skill int) CoCSuccess {
+	switch {
+	case result == 1:
+		return CoCCriticalSuccess
+	case result == 100, result >= 96 && skill < 50:
+		return CoCFumble
+	case result <= skill/5:
+		return CoCExtremeSuccess
+	case result <= skill/2:
+		return CoCHardSuccess
+	case result <= skill:
+		return CoCRegularSuccess
+	default:
+		return CoCFailure
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type cocSuccessTest struct {
+	result, skill int
+	want          CoCSuccess
+}
+
+var cocSuccessTests = []cocSuccessTest{
+	{1, 10, CoCCriticalSuccess},
+	{12, 60, CoCExtremeSuccess},
+	{13, 60, CoCHardSuccess},
+	{30, 60, CoCHardSuccess},
+	{31, 60, CoCRegularSuccess},
+	{60, 60, CoCRegularSuccess},
+	{61, 60, CoCFailure},
+	{95, 45, CoCFailure},
+	{96, 45, CoCFumble},
+	{96, 50, CoCFailure},
+	{99, 99, CoCRegularSuccess},
+	{100, 99, CoCFumble},
+}
+
+// TestCoCSuccess calls diceroller.cocSuccess with many results and skills, checking for valid return values.
+func TestCoCSuccess(t *testing.T) {
+	for _, test := range cocSuccessTests {
+		if output := cocSuccess(test.result, test.skill); output != test.want {
+			t.Errorf("have %v, wanted %v for %d against %d", output, test.want, test.result, test.skill)
+		}
+	}
+}
+
+type rollCoCTest struct {
+	skill, bonus, penalty int
+	want                  CoCRoll
+}
+
+var rollCoCTests = []rollCoCTest{
+	{60, 1, 0, CoCRoll{DiceRoll{DiscoveredRoll: "1d100", Faces: 100, Rolls: 1, Results: []int{49}, Total: 49, NaturalTotal: 49}, 9, []int{40, 90}, 60, CoCRegularSuccess}},
+	{45, 0, 2, CoCRoll{DiceRoll{DiscoveredRoll: "1d100", Faces: 100, Rolls: 1, Results: []int{95}, Total: 95, NaturalTotal: 95}, 5, []int{90, 0, 30}, 45, CoCFailure}},
+	{50, 1, 1, CoCRoll{DiceRoll{DiscoveredRoll: "1d100", Faces: 100, Rolls: 1, Results: []int{26}, Total: 26, NaturalTotal: 26}, 6, []int{20}, 50, CoCRegularSuccess}},
+	{80, 2, 0, CoCRoll{DiceRoll{DiscoveredRoll: "1d100", Faces: 100, Rolls: 1, Results: []int{11}, Total: 11, NaturalTotal: 11}, 1, []int{10, 90, 40}, 80, CoCExtremeSuccess}},
+}
+
+// TestRollCoC calls diceroller.RollCoC with many skills and bonus and penalty dice, checking for valid return values.
+func TestRollCoC(t *testing.T) {
+	reseed()
+
+	for _, test := range rollCoCTests {
+		output, err := RollCoC(test.skill, test.bonus, test.penalty)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	if _, err := RollCoC(50, -1, 0); err == nil {
+		t.Errorf("have nil error for negative bonus dice, wanted an error")
+	}
+}
+
+// BenchmarkRollCoC benchmarks diceroller.RollCoC.
+func BenchmarkRollCoC(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollCoC(60, 1, 0)
+	}
+}
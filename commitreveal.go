@@ -0,0 +1,191 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// CommitReveal makes provably fair rolls, e.g. for play-by-post or wagered games. It picks a secret seed, and its Commitment (the
+// seed's SHA-256 hash) is published before anything is rolled. Players can add a seed of their own, so the seed can't have been
+// picked for the rolls it gives. Every roll comes from the seeds, and once the seed is revealed, anyone can check it matches the
+// commitment and gives the same rolls, with VerifyReveal. It's safe for concurrent use.
+type CommitReveal struct {
+	mu         sync.Mutex
+	seed       [32]byte   // The secret seed.
+	clientSeed string     // The players' seed, if any.
+	roller     *Roller    // The Roller seeded from both seeds, once something's been rolled.
+	inputs     []string   // Each roll rolled, in order.
+	rolls      []DiceRoll // What each roll came up.
+	revealed   bool       // Whether the seed has been revealed, after which nothing more can be rolled.
+}
+
+// Reveal is everything needed to check a CommitReveal's rolls, published once they're done.
+type Reveal struct {
+	Commitment string     `json:"commitment"`            // The SHA-256 hash of the seed, in hex, as published before rolling.
+	Seed       string     `json:"seed"`                  // The seed, in hex.
+	ClientSeed string     `json:"client_seed,omitempty"` // The players' seed, if any.
+	Inputs     []string   `json:"inputs"`                // Each roll rolled, in order.
+	Rolls      []DiceRoll `json:"rolls"`                 // What each roll came up.
+}
+
+/*
+ * NewCommitReveal picks a secret seed with crypto/rand, ready for its Commitment to be published.
+ * e.g. fair, _ := NewCommitReveal(); post(fair.Commitment())
+ */
+func NewCommitReveal() (*CommitReveal, error) {
+	var output CommitReveal
+
+	if _, err := cryptorand.Read(output.seed[:]); err != nil {
+		return nil, fmt.Errorf("can't pick a seed: %w", err)
+	}
+
+	return &output, nil
+}
+
+/*
+ * Commitment returns the SHA-256 hash of the secret seed, in hex, to be published before anything is rolled.
+ * e.g. fair.Commitment() // "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+ */
+func (fair *CommitReveal) Commitment() string {
+	return commitment(fair.seed[:])
+}
+
+/*
+ * SetClientSeed mixes the players' own seed into the rolls, so the secret seed can't have been picked for the rolls it gives. It has
+ *   to be set after the commitment is published, and before anything is rolled.
+ * e.g. fair.SetClientSeed("alice's lucky socks")
+ */
+func (fair *CommitReveal) SetClientSeed(seed string) error {
+	fair.mu.Lock()
+	defer fair.mu.Unlock()
+
+	if fair.roller != nil || fair.revealed {
+		return errors.New("the client seed has to be set before anything is rolled")
+	}
+
+	fair.clientSeed = seed
+
+	return nil
+}
+
+/*
+ * Roll rolls one or more rolls, as RollDetails does, from the seeds. It can be called many times until the seed is revealed.
+ * e.g. fair.Roll("1d20+5") // [{1d20+5 20 1 5 [14] 19 ...}]
+ */
+func (fair *CommitReveal) Roll(input ...string) ([]DiceRoll, error) {
+	fair.mu.Lock()
+	defer fair.mu.Unlock()
+
+	if fair.revealed {
+		return nil, errors.New("nothing can be rolled once the seed has been revealed")
+	}
+
+	if fair.roller == nil {
+		fair.roller = fairRoller(fair.seed[:], fair.clientSeed)
+	}
+
+	output, err := fair.roller.RollDetails(input...)
+	if err != nil {
+		return nil, err
+	}
+
+	fair.inputs = append(fair.inputs, input...)
+	fair.rolls = append(fair.rolls, output...)
+
+	return output, nil
+}
+
+/*
+ * Reveal reveals the seed and every roll, for players to check with VerifyReveal. Nothing more can be rolled afterwards.
+ */
+func (fair *CommitReveal) Reveal() Reveal {
+	fair.mu.Lock()
+	defer fair.mu.Unlock()
+
+	fair.revealed = true
+
+	return Reveal{
+		Commitment: commitment(fair.seed[:]),
+		Seed:       hex.EncodeToString(fair.seed[:]),
+		ClientSeed: fair.clientSeed,
+		Inputs:     slices.Clone(fair.inputs),
+		Rolls:      slices.Clone(fair.rolls),
+	}
+}
+
+/*
+ * VerifyReveal checks a Reveal: that the seed matches the commitment published before rolling, and that rolling the inputs from the
+ *   seeds gives the same rolls. It returns an error saying what doesn't match, or nil if it all does. Rolls go through the
+ *   post-processors, so they need to be the same as when the rolls were made.
+ * e.g. VerifyReveal(reveal) // nil
+ */
+func VerifyReveal(reveal Reveal) error {
+	seed, err := hex.DecodeString(reveal.Seed)
+	if err != nil || len(seed) != 32 {
+		return fmt.Errorf("the seed %q isn't 32 bytes of hex", reveal.Seed)
+	}
+
+	if commitment(seed) != reveal.Commitment {
+		return fmt.Errorf("the seed doesn't match the commitment %q", reveal.Commitment)
+	}
+
+	if len(reveal.Inputs) != len(reveal.Rolls) {
+		return fmt.Errorf("there are %d inputs but %d rolls", len(reveal.Inputs), len(reveal.Rolls))
+	}
+
+	roller := fairRoller(seed, reveal.ClientSeed)
+
+	for i, input := range reveal.Inputs {
+		output, err := roller.roll(input)
+		if err != nil {
+			return fmt.Errorf("roll %d, %q: %w", i+1, input, err)
+		}
+
+		if !reflect.DeepEqual(output, reveal.Rolls[i]) {
+			return fmt.Errorf("roll %d, %q, came up %v from the seed, not %v", i+1, input, output, reveal.Rolls[i])
+		}
+	}
+
+	return nil
+}
+
+/*
+ * commitment returns the SHA-256 hash of a seed, in hex.
+ */
+func commitment(seed []byte) string {
+	sum := sha256.Sum256(seed)
+
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+ * fairRoller returns a Roller seeded from the SHA-256 hash of the seed and the client seed, so both decide every roll.
+ */
+func fairRoller(seed []byte, clientSeed string) *Roller {
+	return NewRoller(WithSource(rand.NewChaCha8(sha256.Sum256(append(slices.Clone(seed), clientSeed...)))))
+}
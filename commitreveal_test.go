@@ -0,0 +1,109 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestCommitReveal rolls with a diceroller.CommitReveal, checking the reveal verifies, even through JSON, and tampering doesn't.
+func TestCommitReveal(t *testing.T) {
+	fair, err := NewCommitReveal()
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	commitment := fair.Commitment()
+
+	if err := fair.SetClientSeed("alice's lucky socks"); err != nil {
+		t.Errorf("have err %v", err)
+	}
+
+	if _, err := fair.Roll("1d20+5", "4d6kh3s"); err != nil {
+		t.Errorf("have err %v", err)
+	}
+
+	if _, err := fair.Roll("8d6"); err != nil {
+		t.Errorf("have err %v", err)
+	}
+
+	if err := fair.SetClientSeed("too late"); err == nil {
+		t.Error("have no error, wanted one for setting the client seed after rolling")
+	}
+
+	if _, err := fair.Roll("nothing"); err == nil {
+		t.Error("have no error, wanted one for a bad roll")
+	}
+
+	reveal := fair.Reveal()
+	if reveal.Commitment != commitment || len(reveal.Inputs) != 3 || len(reveal.Rolls) != 3 || reveal.Rolls[2].DiscoveredRoll != "8d6" {
+		t.Fatalf("have %+v, wanted the three rolls made, and the commitment %q", reveal, commitment)
+	}
+
+	if _, err := fair.Roll("1d20"); err == nil {
+		t.Error("have no error, wanted one for rolling after revealing")
+	}
+
+	encoded, _ := json.Marshal(reveal)
+
+	var decoded Reveal
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if err := VerifyReveal(decoded); err != nil {
+		t.Errorf("have err %v, wanted the reveal to verify", err)
+	}
+
+	// Any change to what was revealed shouldn't verify.
+	for name, tamper := range map[string]func(*Reveal){
+		"seed":        func(r *Reveal) { r.Seed = r.Commitment },
+		"bad seed":    func(r *Reveal) { r.Seed = "not hex" },
+		"commitment":  func(r *Reveal) { r.Commitment = r.Seed },
+		"client seed": func(r *Reveal) { r.ClientSeed = "bob's lucky socks" },
+		"input":       func(r *Reveal) { r.Inputs[1] = "4d6kh3" },
+		"bad input":   func(r *Reveal) { r.Inputs[0] = "nothing" },
+		"total":       func(r *Reveal) { r.Rolls[0].Total++ },
+		"missing":     func(r *Reveal) { r.Rolls = r.Rolls[:2] },
+	} {
+		var tampered Reveal
+		_ = json.Unmarshal(encoded, &tampered)
+		tamper(&tampered)
+
+		if err := VerifyReveal(tampered); err == nil {
+			t.Errorf("have no error, wanted one for a tampered %s", name)
+		}
+	}
+
+	if other, _ := NewCommitReveal(); other.Commitment() == commitment {
+		t.Error("have the same commitment, wanted a different seed")
+	}
+}
+
+// BenchmarkVerifyReveal benchmarks diceroller.VerifyReveal.
+func BenchmarkVerifyReveal(b *testing.B) {
+	fair, _ := NewCommitReveal()
+	_, _ = fair.Roll("1d20+5", "4d6kh3", "8d6")
+	reveal := fair.Reveal()
+
+	for i := 0; i < b.N; i++ {
+		_ = VerifyReveal(reveal)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "math"
+
+// Comparison compares two rolls, as worked out by CompareExpressions, e.g. for a player choosing between two weapons.
+type Comparison struct {
+	A, B           RollDistribution // The exact distribution of each roll.
+	Better         string           // The roll which is better on average, or empty if their averages are the same.
+	MeanDifference float64          // How much higher A is than B on average, which is negative if B is higher.
+	AWins          float64          // The chance of A's total beating B's, from 0 to 1.
+	BWins          float64          // The chance of B's total beating A's.
+	Ties           float64          // The chance of the totals being the same.
+}
+
+/*
+ * CompareExpressions works out the exact distributions of two rolls in the 'nDn+n' format, without rolling them, and compares them:
+ *   which is better on average, and the chance of each beating the other if both were rolled.
+ * e.g. CompareExpressions("2d6+2", "1d12+3") // {{2d6+2 ...} {1d12+3 ...} 1d12+3 -0.5 0.4167 0.5 0.0833}
+ */
+func CompareExpressions(a, b string) (output Comparison, err error) {
+	if output.A, err = Distribution(a); err != nil {
+		return Comparison{}, err
+	}
+
+	if output.B, err = Distribution(b); err != nil {
+		return Comparison{}, err
+	}
+
+	output.MeanDifference = output.A.Mean - output.B.Mean
+
+	switch {
+	case math.Abs(output.MeanDifference) < 1e-9:
+		output.MeanDifference = 0
+	case output.MeanDifference > 0:
+		output.Better = output.A.Roll
+	default:
+		output.Better = output.B.Roll
+	}
+
+	// For each of A's totals, the chance of B rolling under it, running totals of B's distribution as A's totals go up.
+	var (
+		dist  = output.B.distribution()
+		below float64
+		next  = dist.min
+	)
+
+	for i, p := range output.A.Probabilities {
+		total := output.A.Min + i
+
+		for ; next < total; next++ {
+			below += dist.exactly(next)
+		}
+
+		tie := dist.exactly(total)
+		output.AWins += p * below
+		output.Ties += p * tie
+		output.BWins += p * (1 - below - tie)
+	}
+
+	output.BWins = max(output.BWins, 0)
+
+	return
+}
@@ -0,0 +1,68 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+type compareTest struct {
+	a, b                   string
+	wantBetter             string
+	wantDifference         float64
+	wantA, wantB, wantTies float64
+}
+
+var compareTests = []compareTest{
+	{"2d6+2", "1d12+3", "1d12+3", -0.5, 5.0 / 12, 0.5, 1.0 / 12},
+	{"1d20", "1d20", "", 0, 0.475, 0.475, 0.05},
+	{"1d4+10", "1d4", "1d4+10", 10, 1, 0, 0},
+	{"d20adv", "1d20+3", "d20adv", 0.325, 0.493, 0.458125, 0.048875},
+}
+
+// TestCompareExpressions calls diceroller.CompareExpressions, checking for valid return values.
+func TestCompareExpressions(t *testing.T) {
+	for _, test := range compareTests {
+		output, err := CompareExpressions(test.a, test.b)
+
+		if output.Better != test.wantBetter || math.Abs(output.MeanDifference-test.wantDifference) > 1e-9 || math.Abs(output.AWins-test.wantA) > 1e-9 ||
+			math.Abs(output.BWins-test.wantB) > 1e-9 || math.Abs(output.Ties-test.wantTies) > 1e-9 || err != nil {
+			t.Errorf("have %q %v %v %v %v, wanted %q %v %v %v %v for %q and %q, err %v", output.Better, output.MeanDifference, output.AWins,
+				output.BWins, output.Ties, test.wantBetter, test.wantDifference, test.wantA, test.wantB, test.wantTies, test.a, test.b, err)
+		}
+
+		if output.A.Roll != test.a || output.B.Roll != test.b {
+			t.Errorf("have %q and %q, wanted the distributions of %q and %q", output.A.Roll, output.B.Roll, test.a, test.b)
+		}
+	}
+
+	for _, pair := range [][2]string{{"2d6", "nothing"}, {"2d0", "2d6"}, {"2d6", "99999d99999"}} {
+		if _, err := CompareExpressions(pair[0], pair[1]); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", pair)
+		}
+	}
+}
+
+// BenchmarkCompareExpressions benchmarks diceroller.CompareExpressions.
+func BenchmarkCompareExpressions(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = CompareExpressions("2d6+2", "1d12+3")
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CompiledRoll is a roll which has been parsed and checked once, to be rolled many times without parsing it again, e.g. damage over
+// time, or a Monte Carlo simulation. It's safe for concurrent use.
+type CompiledRoll struct {
+	spec    rollSpec // The parsed roll, with the random source of the Roller which compiled it.
+	history *History // The History of the Roller which compiled it, if it has one.
+}
+
+/*
+ * Compile parses the first roll in the input once, so it can be rolled many times with the package's random source. A bad roll,
+ *   such as '2d0', is an error here, rather than every time it's rolled.
+ * e.g. fireball, _ := Compile("8d6")
+ */
+func Compile(input string) (*CompiledRoll, error) {
+	return defaultRoller.Compile(input)
+}
+
+/*
+ * Compile is like the package's Compile, but the CompiledRoll rolls with the Roller's random source, and is checked against its
+ *   limits.
+ * e.g. fireball, _ := roller.Compile("8d6")
+ */
+func (roller *Roller) Compile(input string) (*CompiledRoll, error) {
+	spec, err := roller.parseRoll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = roller.limits.check(spec); err != nil {
+		return nil, err
+	}
+
+	spec.random = roller.random
+
+	return &CompiledRoll{spec: spec, history: roller.history}, nil
+}
+
+/*
+ * RollN rolls the first roll in the input n times, parsing it only once, and returns the details of every roll, e.g. for a stat
+ *   block, or a simulation which needs more than the totals.
+ * e.g. RollN("4d6kh3", 6) // [{4d6kh3 6 4 0 [6 3 6 4] 16 ...} ...]
+ */
+func RollN(input string, n int) ([]DiceRoll, error) {
+	return defaultRoller.RollN(input, n)
+}
+
+/*
+ * RollN is like the package's RollN, but rolls with the Roller's random source. With WithMaxTotalDice, the limit is on all n rolls.
+ * e.g. roller.RollN("4d6kh3", 6) // [{4d6kh3 6 4 0 [5 4 5 5] 15 ...} ...]
+ */
+func (roller *Roller) RollN(input string, n int) ([]DiceRoll, error) {
+	compiled, err := roller.Compile(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if max := roller.limits.maxTotalDice; max > 0 && n > 0 && compiled.spec.rolls > max/n {
+		return nil, fmt.Errorf("%w: %d rolls of %q, the most dice is %d", ErrLimitExceeded, n, compiled.spec.discovered, max)
+	}
+
+	return compiled.RollN(n)
+}
+
+/*
+ * RollUntil rolls the first roll in the input until stop returns true for a roll, parsing it only once, e.g. to roll until you fail,
+ *   or reroll totals under 70. It rolls at most maxRolls times, so it can't roll forever, and returns every roll, the last being the
+ *   one it stopped on. If it doesn't stop in time, it returns every roll and an error.
+ * e.g. RollUntil("1d20", func(roll DiceRoll) bool { return roll.Total == 20 }, 100) // [{1d20 20 1 0 [19] 19 ...} ... {... [20] 20 ...}]
+ */
+func RollUntil(input string, stop func(DiceRoll) bool, maxRolls int) ([]DiceRoll, error) {
+	return defaultRoller.RollUntil(input, stop, maxRolls)
+}
+
+/*
+ * RollUntil is like the package's RollUntil, but rolls with the Roller's random source.
+ * e.g. roller.RollUntil("1d20", func(roll DiceRoll) bool { return roll.Total == 20 }, 100)
+ */
+func (roller *Roller) RollUntil(input string, stop func(DiceRoll) bool, maxRolls int) ([]DiceRoll, error) {
+	compiled, err := roller.Compile(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return compiled.RollUntil(stop, maxRolls)
+}
+
+/*
+ * Roll rolls the CompiledRoll once, the same as RollDetails would roll the roll it was compiled from. It only errors if a
+ *   post-processor does.
+ * e.g. fireball.Roll() // {8d6 6 8 0 [6 3 6 4 6 1 3 4] 33 ...}
+ */
+func (compiled *CompiledRoll) Roll() (output DiceRoll, err error) {
+	if output, err = postProcess(compiled.spec.roll()); err == nil {
+		compiled.history.add(output, "")
+	}
+
+	return
+}
+
+/*
+ * RollN rolls the CompiledRoll n times, returning every roll, stopping at the first error from a post-processor.
+ * e.g. fireball.RollN(3) // [{8d6 ...} {8d6 ...} {8d6 ...}]
+ */
+func (compiled *CompiledRoll) RollN(n int) (output []DiceRoll, err error) {
+	if n < 1 {
+		return nil, errors.New("rolling n times needs n to be at least one")
+	}
+
+	output = make([]DiceRoll, 0, n)
+
+	for range n {
+		dr, err := compiled.Roll()
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, dr)
+	}
+
+	return output, nil
+}
+
+/*
+ * RollUntil rolls the CompiledRoll until stop returns true for a roll, at most maxRolls times, and returns every roll, the last
+ *   being the one it stopped on. If it doesn't stop in time, it returns every roll and an error.
+ * e.g. fireball.RollUntil(func(roll DiceRoll) bool { return roll.Total >= 40 }, 100) // [{8d6 ...} ... {8d6 ... 41 ...}]
+ */
+func (compiled *CompiledRoll) RollUntil(stop func(DiceRoll) bool, maxRolls int) (output []DiceRoll, err error) {
+	if maxRolls < 1 {
+		return nil, errors.New("rolling until something happens needs at least one roll")
+	}
+
+	for range maxRolls {
+		dr, err := compiled.Roll()
+		if err != nil {
+			return output, err
+		}
+
+		output = append(output, dr)
+
+		if stop(dr) {
+			return output, nil
+		}
+	}
+
+	return output, fmt.Errorf("%q didn't stop in %d rolls", compiled.spec.discovered, maxRolls)
+}
+
+/*
+ * Spec returns what the CompiledRoll rolls, as ParseDetails would.
+ */
+func (compiled *CompiledRoll) Spec() RollSpec {
+	return compiled.spec.export()
+}
+
+/*
+ * String returns the roll the CompiledRoll rolls, as discovered, e.g. '8d6'.
+ */
+func (compiled *CompiledRoll) String() string {
+	return compiled.spec.discovered
+}
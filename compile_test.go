@@ -0,0 +1,169 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestCompile calls diceroller.Compile, checking a CompiledRoll rolls the same as RollDetails, and bad rolls are errors.
+func TestCompile(t *testing.T) {
+	reseed()
+
+	var want []DiceRoll
+	for range 3 {
+		dr, _ := RollDetails("4d6kh3++1+2")
+		want = append(want, dr...)
+	}
+
+	reseed()
+
+	compiled, err := Compile("roll 4d6kh3++1+2")
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	for i := range want {
+		if output, err := compiled.Roll(); !reflect.DeepEqual(output, want[i]) || err != nil {
+			t.Errorf("have %v, err %v, wanted %v", output, err, want[i])
+		}
+	}
+
+	if compiled.String() != "4d6kh3++1+2" {
+		t.Errorf("have %q, wanted %q", compiled.String(), "4d6kh3++1+2")
+	}
+
+	if want := (RollSpec{"4d6kh3++1+2", 4, 6, 2, "kh", 3, "", 1}); compiled.Spec() != want {
+		t.Errorf("have %v, wanted %v", compiled.Spec(), want)
+	}
+
+	for _, input := range []string{"nothing", "2d0", "0d6"} {
+		if _, err := Compile(input); err == nil {
+			t.Errorf("have no error, wanted one for %q", input)
+		}
+	}
+
+	if _, err := NewRoller(WithMaxFaces(6)).Compile("1d8"); err == nil {
+		t.Error("have no error, wanted one for a roll over the limit")
+	}
+
+	// A Roller's CompiledRoll rolls with the Roller's random source.
+	a, _ := NewRoller(WithSeed(1, 2)).Compile("10d10")
+	b, _ := NewRoller(WithSeed(1, 2)).Compile("10d10")
+
+	rollA, _ := a.Roll()
+	rollB, _ := b.Roll()
+
+	if !reflect.DeepEqual(rollA, rollB) {
+		t.Errorf("have %v and %v, wanted the same", rollA, rollB)
+	}
+}
+
+// TestRollN calls diceroller.RollN, checking it rolls the same as rolling the roll n times, and bad rolls or counts are errors.
+func TestRollN(t *testing.T) {
+	reseed()
+
+	var want []DiceRoll
+	for range 6 {
+		dr, _ := RollDetails("4d6kh3")
+		want = append(want, dr...)
+	}
+
+	reseed()
+
+	if output, err := RollN("4d6kh3", 6); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, err %v, wanted %v", output, err, want)
+	}
+
+	for _, n := range []int{0, -1} {
+		if _, err := RollN("4d6kh3", n); err == nil {
+			t.Errorf("have no error, wanted one for %d rolls", n)
+		}
+	}
+
+	if _, err := RollN("2d0", 6); err == nil {
+		t.Error("have no error, wanted one for a bad roll")
+	}
+
+	roller := NewRoller(WithMaxTotalDice(24))
+	if output, err := roller.RollN("4d6kh3", 6); len(output) != 6 || err != nil {
+		t.Errorf("have %d rolls, err %v, wanted 6", len(output), err)
+	}
+
+	if _, err := roller.RollN("4d6kh3", 7); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+}
+
+// BenchmarkRollN benchmarks diceroller.RollN.
+func BenchmarkRollN(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollN("4d6kh3", 6)
+	}
+}
+
+// TestRollUntil calls diceroller.RollUntil, checking it stops on the first roll it's told to, or after so many rolls.
+func TestRollUntil(t *testing.T) {
+	reseed()
+
+	natural20 := func(roll DiceRoll) bool { return roll.Total == 20 }
+
+	output, err := RollUntil("1d20", natural20, 100)
+	if len(output) != 5 || err != nil {
+		t.Fatalf("have %d rolls, err %v, wanted 5", len(output), err)
+	}
+
+	for i, roll := range output {
+		if natural20(roll) != (i == len(output)-1) {
+			t.Errorf("have %v at %d, wanted only the last roll to be a 20", roll, i)
+		}
+	}
+
+	if output, err := RollUntil("1d20", func(DiceRoll) bool { return false }, 10); len(output) != 10 || err == nil {
+		t.Errorf("have %d rolls, err %v, wanted 10 and an error", len(output), err)
+	}
+
+	for _, input := range []string{"2d0", "nothing"} {
+		if _, err := RollUntil(input, natural20, 10); err == nil {
+			t.Errorf("have no error, wanted one for %q", input)
+		}
+	}
+
+	if _, err := NewRoller(WithSeed(1, 2)).RollUntil("1d20", natural20, 0); err == nil {
+		t.Error("have no error, wanted one for no rolls")
+	}
+}
+
+// BenchmarkRollUntil benchmarks diceroller.RollUntil.
+func BenchmarkRollUntil(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollUntil("1d20", func(roll DiceRoll) bool { return roll.Total == 20 }, 100)
+	}
+}
+
+// BenchmarkCompiledRoll benchmarks diceroller.CompiledRoll.Roll.
+func BenchmarkCompiledRoll(b *testing.B) {
+	compiled, _ := Compile("4d6kh3")
+
+	for i := 0; i < b.N; i++ {
+		_, _ = compiled.Roll()
+	}
+}
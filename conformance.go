@@ -0,0 +1,140 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+//go:generate go test -run TestConformance -update
+
+// The version of the conformance suite's format, bumped if its fields change.
+const conformanceVersion = 1
+
+// How the dice in a conformance suite are rolled, for ports to match, written into the suite.
+const conformanceRandom = "Each vector is rolled with a math/rand/v2 PCG (PCG-DXSM) seeded with (seed, seed). A die with n faces " +
+	"comes up IntN(n)+1, using math/rand/v2's IntN. Dice are rolled left to right, before any are kept or sorted. Only dice of 20 " +
+	"faces count towards natural_max and natural_min. Nothing is post-processed."
+
+// ConformanceSuite is a machine-readable set of rolls and exactly what they should come out as, so ports of the package to other
+// languages, and clients which check rolls, can check they match this implementation. It has JSON tags.
+type ConformanceSuite struct {
+	Version int                 `json:"version"`
+	Random  string              `json:"random"` // How the dice are rolled.
+	Vectors []ConformanceVector `json:"vectors"`
+}
+
+// ConformanceVector is one roll in a ConformanceSuite. Seeds are small, as JSON numbers are only exact up to 2^53 in many languages.
+type ConformanceVector struct {
+	Input string           `json:"input"`
+	Seed  uint64           `json:"seed"`
+	Error bool             `json:"error,omitempty"` // Whether the input should be rejected. Error messages aren't part of the suite.
+	Roll  *ConformanceRoll `json:"roll,omitempty"`  // What the roll should come out as, if it's valid.
+}
+
+// ConformanceRoll is what a roll in a ConformanceVector should come out as: DiceRoll's fields, except sets.
+type ConformanceRoll struct {
+	Discovered   string `json:"discovered"`
+	Faces        int    `json:"faces"`
+	Rolls        int    `json:"rolls"`
+	Modifier     int    `json:"modifier"`
+	DieModifier  int    `json:"die_modifier"`
+	Results      []int  `json:"results"`
+	Unsorted     []int  `json:"unsorted,omitempty"`
+	Dropped      []int  `json:"dropped,omitempty"` // In ascending order.
+	Total        int    `json:"total"`
+	NaturalTotal int    `json:"natural_total"`
+	NaturalMax   int    `json:"natural_max"`
+	NaturalMin   int    `json:"natural_min"`
+}
+
+/*
+ * NewConformanceSuite rolls each input, the first with seed 1, the next with seed 2 and so on, and returns the suite of what they
+ *   came out as. Save it with encoding/json for other languages to check against.
+ * e.g. NewConformanceSuite("2d6", "4d6kh3") // {1 ... [{2d6 1 false 0xc000...} {4d6kh3 2 false 0xc000...}]}
+ */
+func NewConformanceSuite(inputs ...string) ConformanceSuite {
+	output := ConformanceSuite{Version: conformanceVersion, Random: conformanceRandom, Vectors: make([]ConformanceVector, len(inputs))}
+
+	for i, input := range inputs {
+		output.Vectors[i] = newConformanceVector(input, uint64(i+1))
+	}
+
+	return output
+}
+
+/*
+ * Verify rolls every vector in the suite again, and returns an error for each one which doesn't come out as the suite says.
+ * e.g. suite.Verify() // [] if this implementation matches
+ */
+func (suite ConformanceSuite) Verify() (output []error) {
+	if suite.Version != conformanceVersion {
+		return []error{fmt.Errorf("conformance suite version %d isn't supported: wanted %d", suite.Version, conformanceVersion)}
+	}
+
+	for i, vector := range suite.Vectors {
+		if now := newConformanceVector(vector.Input, vector.Seed); !reflect.DeepEqual(now, vector) {
+			output = append(output, fmt.Errorf("vector %d, %q (seed %d): have %s, wanted %s", i, vector.Input, vector.Seed, now.describe(),
+				vector.describe()))
+		}
+	}
+
+	return
+}
+
+/*
+ * newConformanceVector rolls one input with the given seed.
+ */
+func newConformanceVector(input string, seed uint64) ConformanceVector {
+	dr, err := rollSeeded(input, seed)
+	if err != nil {
+		return ConformanceVector{Input: input, Seed: seed, Error: true}
+	}
+
+	dropped := slices.Clone(dr.Dropped)
+	slices.Sort(dropped)
+
+	return ConformanceVector{Input: input, Seed: seed, Roll: &ConformanceRoll{
+		Discovered:   dr.DiscoveredRoll,
+		Faces:        dr.Faces,
+		Rolls:        dr.Rolls,
+		Modifier:     dr.Modifier,
+		DieModifier:  dr.DieModifier,
+		Results:      dr.Results,
+		Unsorted:     dr.Unsorted,
+		Dropped:      dropped,
+		Total:        dr.Total,
+		NaturalTotal: dr.NaturalTotal,
+		NaturalMax:   dr.NaturalMax,
+		NaturalMin:   dr.NaturalMin,
+	}}
+}
+
+/*
+ * describe returns what the vector came out as, for error messages.
+ */
+func (vector ConformanceVector) describe() string {
+	if vector.Roll == nil {
+		return fmt.Sprintf("error %t", vector.Error)
+	}
+
+	return fmt.Sprintf("%+v", *vector.Roll)
+}
@@ -0,0 +1,105 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The rolls in the published conformance suite, in conformance/vectors.json: every part of the notation, edge cases, and input
+// which should be rejected.
+var conformanceInputs = []string{
+	"1d6", "2d6", "3D6+2", "1d20-1", "d20", "1d1", "0d6", "10d10", "1d100", "20d6",
+	"4d6kh3", "4d6kl1", "5d8km3+1", "3d20km1", "4d6kh9", "2d8KL1+1",
+	"d20adv", "d20adv+5", "2d20dis", "3d6ADV", "1d20Dis-2",
+	"4d6s", "4d6sd+1", "4d6kh3s", "3d8sd--1-2", "4d6++1", "2d6--1+2", "3d6kh2++2",
+	"Roll 2d6+3 to hit", "2d6/2", "2d6+foo", " 2d6 + 3 ", "1d6 and 1d8",
+	"2d0", "d6", "nothing", "",
+}
+
+// TestConformance checks this implementation matches the published conformance suite. Run 'go generate' (or this test with
+// -update) to write the suite again after an intended change.
+func TestConformance(t *testing.T) {
+	path := filepath.Join("conformance", "vectors.json")
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(NewConformanceSuite(conformanceInputs...), "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("have err %v, wanted the conformance suite (run go generate to create it)", err)
+	}
+
+	var suite ConformanceSuite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if len(suite.Vectors) != len(conformanceInputs) {
+		t.Errorf("have %d vectors, wanted %d", len(suite.Vectors), len(conformanceInputs))
+	}
+
+	for _, err := range suite.Verify() {
+		t.Error(err)
+	}
+}
+
+// TestConformanceVerify calls diceroller.ConformanceSuite.Verify with vectors which don't match, checking each is reported.
+func TestConformanceVerify(t *testing.T) {
+	suite := NewConformanceSuite("2d6", "4d6kh3", "nothing")
+	if errs := suite.Verify(); len(errs) != 0 {
+		t.Errorf("have %v, wanted no errors", errs)
+	}
+
+	if suite.Vectors[0].Seed != 1 || suite.Vectors[0].Roll == nil || !suite.Vectors[2].Error || suite.Vectors[2].Roll != nil {
+		t.Errorf("have %+v, wanted seed 1 for the first, and an error for the last", suite.Vectors)
+	}
+
+	suite.Vectors[0].Roll.Total++
+	suite.Vectors[2].Error = false
+
+	if errs := suite.Verify(); len(errs) != 2 {
+		t.Errorf("have %v, wanted 2 errors", errs)
+	}
+
+	suite.Version = 99
+	if errs := suite.Verify(); len(errs) != 1 {
+		t.Errorf("have %v, wanted 1 error for the version", errs)
+	}
+}
+
+// BenchmarkConformanceVerify benchmarks diceroller.ConformanceSuite.Verify.
+func BenchmarkConformanceVerify(b *testing.B) {
+	suite := NewConformanceSuite(conformanceInputs...)
+
+	for i := 0; i < b.N; i++ {
+		_ = suite.Verify()
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "context"
+
+/*
+ * RollContext is like RollDetails, but stops early if the context is cancelled or its deadline passes, e.g. for a server rolling a
+ *   lot of rolls for one request, returning the context's error. It checks before each roll, so a roll which has started finishes.
+ * e.g. RollContext(ctx, "2d6", "1d20") // [{2d6 6 2 0 [6 3] 9 ...} {1d20 20 1 0 [19] 19 ...}]
+ */
+func RollContext(ctx context.Context, input ...string) ([]DiceRoll, error) {
+	return defaultRoller.RollContext(ctx, input...)
+}
+
+/*
+ * RollContext is like the package's RollContext, but rolls with the Roller's random source.
+ * e.g. roller.RollContext(ctx, "2d6", "1d20") // [{2d6 6 2 0 [5 2] 7 ...} {1d20 20 1 0 [3] 3 ...}]
+ */
+func (roller *Roller) RollContext(ctx context.Context, input ...string) (output []DiceRoll, err error) {
+	if err = roller.limits.checkTotal(input, roller.countDice); err != nil {
+		return
+	}
+
+	var dr DiceRoll
+
+	for _, in := range input {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		dr, err = roller.roll(in)
+		if err != nil {
+			return
+		}
+
+		output = append(output, dr)
+	}
+
+	return
+}
@@ -0,0 +1,63 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestRollContext calls diceroller.RollContext, checking it rolls the same as RollDetails, and stops when cancelled.
+func TestRollContext(t *testing.T) {
+	reseed()
+
+	want, _ := RollDetails("2d6", "1d20", "4d6kh3")
+
+	reseed()
+
+	if output, err := RollContext(context.Background(), "2d6", "1d20", "4d6kh3"); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, err %v, wanted %v", output, err, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if output, err := RollContext(ctx, "2d6"); output != nil || !errors.Is(err, context.Canceled) {
+		t.Errorf("have %v, err %v, wanted nothing and %v", output, err, context.Canceled)
+	}
+
+	if _, err := RollContext(context.Background(), "2d6", "nothing"); !errors.Is(err, ErrNoRollFound) {
+		t.Errorf("have err %v, wanted %v", err, ErrNoRollFound)
+	}
+
+	if _, err := NewRoller(WithMaxTotalDice(2)).RollContext(context.Background(), "2d6", "1d6"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+}
+
+// BenchmarkRollContext benchmarks diceroller.RollContext.
+func BenchmarkRollContext(b *testing.B) {
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = RollContext(ctx, "4d6kh3", "1d20")
+	}
+}
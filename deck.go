@@ -0,0 +1,215 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrDeckEmpty is returned when drawing more from a Deck than it has left.
+	ErrDeckEmpty = errors.New("not enough cards left in the deck")
+
+	// ErrCardNotDrawn is returned when discarding a card which wasn't drawn from the deck.
+	ErrCardNotDrawn = errors.New("card wasn't drawn from the deck")
+)
+
+// The suits of a standard deck, highest first (as Savage Worlds breaks initiative ties), and the symbol for each.
+var (
+	cardSuits       = []string{"spades", "hearts", "diamonds", "clubs"}
+	cardSuitSymbols = map[string]string{"spades": "♠", "hearts": "♥", "diamonds": "♦", "clubs": "♣"}
+)
+
+// Card is one card in a Deck. Custom decks can use any name, rank and suit.
+type Card struct {
+	Name string // What the card is called, e.g. 'A♠' or 'Red Joker'.
+	Rank int    // How high the card is. In a standard deck, 2 to 10, then 11 to 14 for jack, queen, king and ace, and 15 for jokers.
+	Suit string // The card's suit, e.g. 'spades', or empty for jokers.
+}
+
+/*
+ * String returns the card's name.
+ */
+func (card Card) String() string {
+	return card.Name
+}
+
+// Deck is a deck of cards which can be shuffled, drawn from and discarded to, using the same random source as the dice (or a
+// Roller's, with Roller.NewDeck), as Savage Worlds and Deadlands use for initiative. It's safe for concurrent use.
+type Deck struct {
+	mu       sync.Mutex
+	random   RandSource   // Where the shuffles get their random numbers from.
+	cards    []Card       // Every card in the deck, in the order given.
+	pile     []Card       // The cards left to draw, the top card last.
+	discards []Card       // The cards discarded, the latest last.
+	drawn    map[Card]int // How many of each card have been drawn and not discarded.
+}
+
+/*
+ * StandardCards returns the 52 cards of a standard deck, spades first and from 2 to ace in each suit, and the two jokers if asked.
+ */
+func StandardCards(jokers bool) (output []Card) {
+	names := map[int]string{11: "J", 12: "Q", 13: "K", 14: "A"}
+
+	for _, suit := range cardSuits {
+		for rank := 2; rank <= 14; rank++ {
+			name, ok := names[rank]
+			if !ok {
+				name = strconv.Itoa(rank)
+			}
+
+			output = append(output, Card{Name: name + cardSuitSymbols[suit], Rank: rank, Suit: suit})
+		}
+	}
+
+	if jokers {
+		output = append(output, Card{Name: "Red Joker", Rank: 15}, Card{Name: "Black Joker", Rank: 15})
+	}
+
+	return
+}
+
+/*
+ * NewDeck returns a shuffled Deck of the given cards, which can repeat.
+ * e.g. NewDeck(StandardCards(true)...) // a 54-card deck with jokers
+ */
+func NewDeck(cards ...Card) (*Deck, error) {
+	return defaultRoller.NewDeck(cards...)
+}
+
+/*
+ * NewDeck is like the package's NewDeck, but the deck is shuffled with the Roller's random source, e.g. a seeded one.
+ * e.g. NewRoller(WithSeed(42, 1024)).NewDeck(StandardCards(true)...)
+ */
+func (roller *Roller) NewDeck(cards ...Card) (*Deck, error) {
+	if len(cards) == 0 {
+		return nil, errors.New("a deck needs at least one card")
+	}
+
+	deck := &Deck{cards: slices.Clone(cards), random: roller.source()}
+	deck.Reshuffle()
+
+	return deck, nil
+}
+
+/*
+ * Draw takes cards from the top of the deck, or returns ErrDeckEmpty, and draws nothing, if there aren't enough left.
+ */
+func (deck *Deck) Draw(count int) ([]Card, error) {
+	deck.mu.Lock()
+	defer deck.mu.Unlock()
+
+	switch {
+	case count < 0:
+		return nil, fmt.Errorf("can't draw %d cards", count)
+	case count > len(deck.pile):
+		return nil, fmt.Errorf("%w: drawing %d with %d left", ErrDeckEmpty, count, len(deck.pile))
+	}
+
+	output := make([]Card, 0, count)
+	for range count {
+		card := deck.pile[len(deck.pile)-1]
+		deck.pile = deck.pile[:len(deck.pile)-1]
+		deck.drawn[card]++
+
+		output = append(output, card)
+	}
+
+	return output, nil
+}
+
+/*
+ * Discard puts drawn cards on the discard pile, or returns ErrCardNotDrawn, and discards nothing, if any of them weren't drawn.
+ */
+func (deck *Deck) Discard(cards ...Card) error {
+	deck.mu.Lock()
+	defer deck.mu.Unlock()
+
+	counts := map[Card]int{}
+	for _, card := range cards {
+		if counts[card]++; counts[card] > deck.drawn[card] {
+			return fmt.Errorf("%w: %s", ErrCardNotDrawn, card)
+		}
+	}
+
+	for _, card := range cards {
+		deck.drawn[card]--
+		deck.discards = append(deck.discards, card)
+	}
+
+	return nil
+}
+
+/*
+ * Shuffle shuffles the cards left to draw, leaving the drawn and discarded cards where they are.
+ */
+func (deck *Deck) Shuffle() {
+	deck.mu.Lock()
+	defer deck.mu.Unlock()
+
+	deck.shuffle()
+}
+
+/*
+ * Reshuffle puts every card back in the deck, drawn or discarded, and shuffles it, as Savage Worlds does after a joker comes up.
+ */
+func (deck *Deck) Reshuffle() {
+	deck.mu.Lock()
+	defer deck.mu.Unlock()
+
+	deck.pile = slices.Clone(deck.cards)
+	deck.discards = nil
+	deck.drawn = map[Card]int{}
+
+	deck.shuffle()
+}
+
+/*
+ * Remaining returns how many cards are left to draw.
+ */
+func (deck *Deck) Remaining() int {
+	deck.mu.Lock()
+	defer deck.mu.Unlock()
+
+	return len(deck.pile)
+}
+
+/*
+ * Discards returns the discard pile, the latest discard last.
+ */
+func (deck *Deck) Discards() []Card {
+	deck.mu.Lock()
+	defer deck.mu.Unlock()
+
+	return slices.Clone(deck.discards)
+}
+
+/*
+ * shuffle shuffles the cards left to draw, with a Fisher-Yates shuffle, as rand.Shuffle does. The caller must hold the lock.
+ */
+func (deck *Deck) shuffle() {
+	for i := len(deck.pile) - 1; i > 0; i-- {
+		j := deck.random.IntN(i + 1)
+		deck.pile[i], deck.pile[j] = deck.pile[j], deck.pile[i]
+	}
+}
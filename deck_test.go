@@ -0,0 +1,152 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestStandardCards calls diceroller.StandardCards, checking for a full deck with and without jokers.
+func TestStandardCards(t *testing.T) {
+	cards := StandardCards(false)
+	if len(cards) != 52 || cards[0] != (Card{"2♠", 2, "spades"}) || cards[51] != (Card{"A♣", 14, "clubs"}) {
+		t.Errorf("have %d cards from %v to %v, wanted 52 from 2♠ to A♣", len(cards), cards[0], cards[len(cards)-1])
+	}
+
+	seen := map[Card]bool{}
+	for _, card := range StandardCards(true) {
+		if seen[card] {
+			t.Errorf("have %v twice, wanted every card once", card)
+		}
+
+		seen[card] = true
+	}
+
+	if len(seen) != 54 || !seen[Card{"Red Joker", 15, ""}] || !seen[Card{"Black Joker", 15, ""}] {
+		t.Errorf("have %d cards, wanted 54 including two jokers", len(seen))
+	}
+}
+
+// TestRollerDeck calls diceroller.Roller.NewDeck, checking the deck is shuffled with the Roller's source, not the package's.
+func TestRollerDeck(t *testing.T) {
+	for range 2 {
+		random.IntN(6)
+
+		deck, err := NewRoller(WithSeed(42, 1024)).NewDeck(StandardCards(true)...)
+		if err != nil {
+			t.Fatalf("have err %v", err)
+		}
+
+		hand, err := deck.Draw(5)
+		if want := []string{"K♣", "2♦", "9♣", "A♥", "J♣"}; !reflect.DeepEqual(cardNames(hand), want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", hand, want, err)
+		}
+	}
+
+	if _, err := NewRoller().NewDeck(); err == nil {
+		t.Errorf("have no error, wanted one making a deck with no cards")
+	}
+}
+
+// TestDeck calls diceroller.Deck's methods, checking cards are drawn, discarded and shuffled back in properly.
+func TestDeck(t *testing.T) {
+	reseed()
+
+	deck, err := NewDeck(StandardCards(true)...)
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	hand, err := deck.Draw(5)
+	if want := []string{"K♣", "2♦", "9♣", "A♥", "J♣"}; !reflect.DeepEqual(cardNames(hand), want) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", hand, want, err)
+	}
+
+	if deck.Remaining() != 49 {
+		t.Errorf("have %d left, wanted 49", deck.Remaining())
+	}
+
+	if err := deck.Discard(hand[0], hand[0]); !errors.Is(err, ErrCardNotDrawn) || len(deck.Discards()) != 0 {
+		t.Errorf("have err %v and %v discarded, wanted ErrCardNotDrawn and nothing discarded", err, deck.Discards())
+	}
+
+	if err := deck.Discard(hand[1], hand[0]); err != nil || !reflect.DeepEqual(deck.Discards(), []Card{hand[1], hand[0]}) {
+		t.Errorf("have err %v and %v discarded, wanted %v", err, deck.Discards(), []Card{hand[1], hand[0]})
+	}
+
+	if err := deck.Discard(hand[0]); !errors.Is(err, ErrCardNotDrawn) {
+		t.Errorf("have err %v discarding a card twice, wanted ErrCardNotDrawn", err)
+	}
+
+	deck.Shuffle()
+
+	if deck.Remaining() != 49 || len(deck.Discards()) != 2 {
+		t.Errorf("have %d left and %d discarded after shuffling, wanted 49 and 2", deck.Remaining(), len(deck.Discards()))
+	}
+
+	if output, err := deck.Draw(50); !errors.Is(err, ErrDeckEmpty) || output != nil || deck.Remaining() != 49 {
+		t.Errorf("have %v, err %v, wanted ErrDeckEmpty and nothing drawn", output, err)
+	}
+
+	if output, err := deck.Draw(-1); err == nil {
+		t.Errorf("have %v, nil error drawing -1 cards, wanted an error", output)
+	}
+
+	rest, _ := deck.Draw(49)
+	if deck.Remaining() != 0 || len(rest) != 49 {
+		t.Errorf("have %d left after drawing %d, wanted 0 after 49", deck.Remaining(), len(rest))
+	}
+
+	deck.Reshuffle()
+
+	if deck.Remaining() != 54 || len(deck.Discards()) != 0 {
+		t.Errorf("have %d left and %d discarded after reshuffling, wanted 54 and 0", deck.Remaining(), len(deck.Discards()))
+	}
+
+	if err := deck.Discard(hand[2]); !errors.Is(err, ErrCardNotDrawn) {
+		t.Errorf("have err %v discarding a card from before reshuffling, wanted ErrCardNotDrawn", err)
+	}
+
+	if output, err := NewDeck(); err == nil {
+		t.Errorf("have %v, nil error for an empty deck, wanted an error", output)
+	}
+}
+
+/*
+ * cardNames returns the name of each card.
+ */
+func cardNames(cards []Card) (output []string) {
+	for _, card := range cards {
+		output = append(output, card.String())
+	}
+
+	return
+}
+
+// BenchmarkDeck benchmarks diceroller.Deck.Reshuffle and Draw.
+func BenchmarkDeck(b *testing.B) {
+	deck, _ := NewDeck(StandardCards(true)...)
+
+	for i := 0; i < b.N; i++ {
+		deck.Reshuffle()
+		_, _ = deck.Draw(10)
+	}
+}
@@ -19,35 +19,113 @@
 package diceroller
 
 import (
+	"errors"
 	"fmt"
-	"math"
 	"math/rand/v2"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+var (
+	// ErrNoRollFound is returned when there's no dice roll in the input at all, e.g. 'hello'.
+	ErrNoRollFound = errors.New("no dice roll found")
+
+	// ErrZeroFaces is returned for a die with no faces, e.g. '2d0'.
+	ErrZeroFaces = errors.New("a die needs at least one face")
+
+	// ErrZeroDice is returned for a roll of no dice, e.g. '0d6'.
+	ErrZeroDice = errors.New("a roll needs at least one die")
+
+	// ErrNotJustRoll is returned in strict mode when there's more to the input than one roll, e.g. '2d6/2'.
+	ErrNotJustRoll = errors.New("not just a dice roll")
+
+	// ErrBadSignature is returned when a SignedRoll's signature doesn't match the roll, so it's been changed, or signed with another key.
+	ErrBadSignature = errors.New("bad signature")
+
+	// ErrLimitExceeded is returned when a roll is over a Roller's limits, e.g. one made with WithMaxDice.
+	ErrLimitExceeded = errors.New("roll over the limit")
+
+	// ErrOverflow is returned when a total could be too big for an int, e.g. '99999d99999' on 32-bit platforms.
+	ErrOverflow = errors.New("total overflows an int")
+
+	// ErrTooComplex is returned when a roll has too many possible totals to work out their chances exactly, e.g. '99999d99999'.
+	ErrTooComplex = errors.New("too complex to work out exactly")
+
+	// ErrTooLarge is returned when a number in a roll has more digits than the notation allows, e.g. '2d123456'.
+	ErrTooLarge = errors.New("number too large")
+)
+
 type DiceRoll struct {
-	DiscoveredRoll string // The 'nDn+n'-format string we've discovered and are processing.
-	Faces          int    // How many faces our dice has: 4, 6, 8, 10, 12 and 20 are common, but we can handle up to 99,999.
-	Rolls          int    // How many times we're going to roll the above dice.
-	Modifier       int    // A '+n' or '-n' modifier to add to the total, or 0.
-	Results        []int  // Each roll, for the curious.
-	Total          int    // Total of all rolls.
+	DiscoveredRoll string     // The 'nDn+n'-format string we've discovered and are processing.
+	Faces          int        // How many faces our dice has: 4, 6, 8, 10, 12 and 20 are common, but we can handle up to 99,999.
+	Rolls          int        // How many times we're going to roll the above dice.
+	Modifier       int        // A '+n' or '-n' modifier to add to the total, or 0.
+	Results        []int      // Each roll, for the curious.
+	Total          int        // Total of all rolls, with every modifier.
+	NaturalTotal   int        // Total of the dice which count towards the total, as they came up: without any modifiers.
+	Unsorted       []int      // If the results were sorted with 's' or 'sd', the results in the order they were rolled.
+	Dropped        []int      // Indexes into Results of any dice dropped by 'kh', 'kl' or 'km', which don't count towards the total.
+	DieModifier    int        // A '++n' or '--n' modifier to add to every die which counts towards the total, or 0.
+	Sets           []MatchSet // If rolled with RollSets, the matching dice, as used by One Roll Engine games.
+	NaturalMax     int        // For critical dice (d20s, unless changed with SetCriticalDice), how many kept dice came up on their highest face.
+	NaturalMin     int        // For critical dice, how many kept dice came up 1.
+}
+
+// rollSpec is a dice roll which has been parsed, but not yet rolled.
+type rollSpec struct {
+	discovered  string     // The 'nDn+n'-format string we've discovered.
+	rolls       int        // How many dice to roll.
+	die         DieSpec    // The die being rolled.
+	modifier    int        // A '+n' or '-n' modifier to add to the total, or 0.
+	keep        string     // 'kh', 'kl' or 'km' to keep the highest, lowest or middle dice, or empty to keep them all.
+	keepCount   int        // How many dice to keep, if keep is set.
+	sort        string     // 's' or 'sd' to sort the results ascending or descending, or empty to leave them alone.
+	dieModifier int        // A '++n' or '--n' modifier to add to every die which counts towards the total, or 0.
+	reroll      int        // Dice which come up this or lower are rerolled once, keeping the new roll, or 0 to never reroll.
+	criticalAt  int        // Kept dice at or over this count towards NaturalMax, whatever the die, as Roll20's 'cs>n' does, or 0.
+	fumbleAt    int        // Kept dice at or under this count towards NaturalMin, whatever the die, as Roll20's 'cf<n' does, or 0.
+	explode     int        // Dice which come up this or higher are rolled again, as Foundry's 'x' does, or 0 to never explode.
+	minimum     int        // Dice which come up under this count as this, as Foundry's 'min' does, or 0.
+	maximum     int        // Dice which come up over this count as this, as Foundry's 'max' does, or 0.
+	random      RandSource // Where the dice get their random numbers from, or nil for the package's random source.
+}
+
+// DieSpec describes a single die: how many faces it has and, optionally, how likely each face is to come up.
+type DieSpec struct {
+	Faces   int   // How many faces the die has, numbered 1 to Faces.
+	Weights []int // Optional relative weight of each face, lowest face first. If empty, every face is equally likely.
 }
 
 var (
-	// This is the regex used to locate the e.g. 1d6, 2D8+2 rolls. It allows 5-digit numbers (bit daft but whatever).
-	diceRollRegex = regexp.MustCompile(`(\d{1,5})[dD](\d{1,5})([\+-]\d{1,5})?`)
+	// This is the regex used to locate the e.g. 1d6, 2D8+2, 4d6s, 4d6kh3, 4d6++1, d20adv rolls. It allows 5-digit numbers (bit daft but whatever).
+	// The parts of the roll are in named groups so the regex can grow without renumbering everything.
+	diceRollRegex = regexp.MustCompile(
+		`(?:(?P<advrolls>\d{1,5})?[dD](?P<advfaces>\d{1,5})(?P<advantage>(?i:adv|dis))` + // Advantage or disadvantage, e.g. d20adv, where the number of dice is optional...
+			`|(?P<rolls>\d{1,5})[dD](?P<faces>\d{1,5})(?P<keep>(?i:k[hlm])\d{1,5})?)` + // ...or the usual dice, e.g. 4d6, optionally keeping some, e.g. 4d6kh3.
+			`(?P<sort>(?i:sd?))?` + // Sorting, e.g. 4d6s or 4d6sd.
+			`(?P<diemodifier>\+\+\d{1,5}|--\d{1,5})?` + // A per-die modifier, e.g. 4d6++1.
+			`(?P<modifier>[\+-]\d{1,5})?`, // A modifier, e.g. 2d6+2.
+	)
+
+	// How many extra times an exploding die can be rolled.
+	maxExplosions = 100
+
+	// The dice sizes which get NaturalMax and NaturalMin filled in.
+	criticalDice   = []int{20}
+	criticalDiceMu sync.RWMutex
 
 	// Pairs of strings: replace spaces, tabs and line endings with nothing.
 	inputReplacer = strings.NewReplacer(" ", "", "\t", "", "\n", "")
 
-	// Deterministic random source.
-	// random = rand.New(rand.NewPCG(42, 1024))
-	// Random random source.
-	random = rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	// The package's random source, seeded from the time unless SetSeed is called. It's locked, so the package's functions can be
+	// called from many goroutines at once.
+	randomSource = &lockedSource{source: rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano()))}
+	random       = rand.New(randomSource)
 )
 
 /*
@@ -55,9 +133,7 @@ var (
  * e.g. RollOne("2d6") // 7
  */
 func RollOne(input string) (int, error) {
-	roll, err := roll(input)
-
-	return roll.Total, err
+	return defaultRoller.RollOne(input)
 }
 
 /*
@@ -65,18 +141,7 @@ func RollOne(input string) (int, error) {
  * e.g. Roll("2d6", "2d8") // []int{7, 12}
  */
 func Roll(input ...string) (output []int, err error) {
-	var dr DiceRoll
-
-	for _, in := range input {
-		dr, err = roll(in)
-		if err != nil {
-			return
-		}
-
-		output = append(output, dr.Total)
-	}
-
-	return
+	return defaultRoller.Roll(input...)
 }
 
 /*
@@ -84,18 +149,7 @@ func Roll(input ...string) (output []int, err error) {
  * e.g. RollTotal("2d6", "2d8") // 19
  */
 func RollTotal(input ...string) (output int, err error) {
-	var dr DiceRoll
-
-	for _, in := range input {
-		dr, err = roll(in)
-		if err != nil {
-			return
-		}
-
-		output += dr.Total
-	}
-
-	return
+	return defaultRoller.RollTotal(input...)
 }
 
 /*
@@ -104,18 +158,82 @@ func RollTotal(input ...string) (output int, err error) {
  *                       // []diceroller.DiceRoll{diceroller.DiceRoll{DiscoveredRoll:"2d6", Rolls:[]int{5, 2}, Modifier:0, Total:7}}
  */
 func RollDetails(input ...string) (output []DiceRoll, err error) {
-	var dr DiceRoll
+	return defaultRoller.RollDetails(input...)
+}
 
-	for _, in := range input {
-		dr, err = roll(in)
-		if err != nil {
-			return
-		}
+/*
+ * RollOneWith is like RollOne, but rolls with the given options for this roll only, e.g. to replay one roll with a known seed,
+ *   without touching the package's random source.
+ * e.g. RollOneWith("2d6", WithSeed(42, 1024)) // 9, every time
+ */
+func RollOneWith(input string, options ...RollerOption) (int, error) {
+	return defaultRoller.RollOneWith(input, options...)
+}
+
+/*
+ * RollDetailsWith is like RollDetails for one roll, but rolls with the given options for this roll only.
+ * e.g. RollDetailsWith("2d6", WithSeed(42, 1024)) // {2d6 6 2 0 [6 3] 9 ...}, every time
+ */
+func RollDetailsWith(input string, options ...RollerOption) (DiceRoll, error) {
+	return defaultRoller.RollDetailsWith(input, options...)
+}
+
+/*
+ * RollDie rolls the die described by a DieSpec the given number of times, adds the modifier, and returns a DiceRoll struct with the details.
+ * e.g. die, _ := NewWeightedDie(1, 1, 1, 1, 1, 5)
+ *      RollDie(die, 2, 0) // {2d6 6 2 0 [6 6] 12}
+ */
+func RollDie(die DieSpec, rolls, modifier int) (output DiceRoll, err error) {
+	return defaultRoller.RollDie(die, rolls, modifier)
+}
+
+/*
+ * newRollSpec returns a rollSpec for rolling a die a number of times and adding a modifier, as if it had been parsed from 'nDn+n'.
+ */
+func newRollSpec(rolls int, die DieSpec, modifier int) rollSpec {
+	discovered := fmt.Sprintf("%dd%d", rolls, die.Faces)
+	if modifier != 0 {
+		discovered += fmt.Sprintf("%+d", modifier)
+	}
 
-		output = append(output, dr)
+	return rollSpec{
+		discovered: discovered,
+		rolls:      rolls,
+		die:        die,
+		modifier:   modifier,
 	}
+}
 
-	return
+/*
+ * NewWeightedDie takes in the relative weight of each face, lowest face first, and returns a DieSpec for a loaded die.
+ * e.g. NewWeightedDie(1, 1, 1, 1, 1, 5) // A d6 which rolls a six half of the time.
+ */
+func NewWeightedDie(weights ...int) (DieSpec, error) {
+	die := DieSpec{Faces: len(weights), Weights: weights}
+
+	return die, die.validate()
+}
+
+/*
+ * SetSeed reseeds the package's random source, so the same seed always gives the same rolls from then on, e.g. for tests or replays.
+ *   It's the same as a Roller made with WithSeed(seed, seed). It's safe to call while rolling, but rolls from other goroutines
+ *   will take numbers from the sequence too, so use a Roller of its own to reproduce a sequence exactly.
+ * e.g. SetSeed(42)
+ */
+func SetSeed(seed uint64) {
+	randomSource.set(rand.NewPCG(seed, seed))
+}
+
+/*
+ * SetCriticalDice sets which dice sizes get NaturalMax and NaturalMin filled in when rolled, e.g. to spot natural 20s and natural 1s.
+ * The default is d20s only. Call it with no sizes to turn it off.
+ * e.g. SetCriticalDice(20, 100)
+ */
+func SetCriticalDice(faces ...int) {
+	criticalDiceMu.Lock()
+	defer criticalDiceMu.Unlock()
+
+	criticalDice = slices.Clone(faces)
 }
 
 /*
@@ -190,93 +308,583 @@ func PrettifyHTMLFull(input []DiceRoll) (output []string) {
 }
 
 /*
- * roll takes one string in the 'nDn+n' format and rolls that size/face dice that many times, returning a DiceRoll struct with the details.
+ * roll takes one string in the 'nDn+n' format and rolls that size/face dice that many times with the default Roller, returning a
+ *   DiceRoll struct with the details.
  */
 func roll(input string) (output DiceRoll, err error) {
+	return defaultRoller.roll(input)
+}
+
+/*
+ * parseRoll takes one string in the 'nDn+n' format and breaks it down into a rollSpec, without rolling anything. It checks the roll
+ *   can be rolled: at least one die, with at least one face.
+ */
+func parseRoll(input string) (spec rollSpec, err error) {
+	if spec, err = findRoll(input); err != nil {
+		return
+	}
+
+	err = spec.validate()
+
+	return
+}
+
+/*
+ * findRoll finds the first roll in a string and breaks it down into a rollSpec.
+ */
+func findRoll(input string) (spec rollSpec, err error) {
 	// Split the string up into it's component parts.
-	result := diceRollRegex.FindStringSubmatch(input)
+	matches := matchRolls(input, 1)
+	if len(matches) == 0 {
+		err = fmt.Errorf("%w in %q", ErrNoRollFound, input)
+		return
+	}
+
+	match := matches[0]
+
+	// The regex only takes up to five digits of each number, so a digit either side means a number was cut short, e.g. the '6' of
+	//   '2d123456'. A roll ending in a letter, such as '4d6s', can be followed by anything.
+	if (match[0] > 0 && isDigit(input[match[0]-1])) || (match[1] < len(input) && isDigit(input[match[1]-1]) && isDigit(input[match[1]])) {
+		err = fmt.Errorf("%w in %q: numbers in a roll can have at most 5 digits", ErrTooLarge, input)
+		return
+	}
+
+	result := make([]string, len(match)/2)
+	for i := range result {
+		if match[2*i] >= 0 {
+			result[i] = input[match[2*i]:match[2*i+1]]
+		}
+	}
 
 	// We return the 'discovered' roll so the user knows what we saw.
 	// This is important as if we try to process e.g. '2d6/2' (a typo: instead of '2d6+2'),
 	//   we'll *actually* be processing '2d6', with no modifier, and the user might not be expecting this.
-	output.DiscoveredRoll = result[0]
+	spec.discovered = result[0]
+
+	// Advantage and disadvantage are shorthand for rolling twice as many dice and keeping the highest or lowest half.
+	if advantage := strings.ToLower(submatch(result, "advantage")); len(advantage) > 0 {
+		return parseAdvantage(spec, result, advantage)
+	}
 
 	// Converting strings to ints.
-	output.Rolls, err = strconv.Atoi(result[1])
+	spec.rolls, err = strconv.Atoi(submatch(result, "rolls"))
 	if err != nil {
 		return
 	}
 
-	output.Faces, err = strconv.Atoi(result[2])
+	spec.die.Faces, err = strconv.Atoi(submatch(result, "faces"))
 	if err != nil {
 		return
 	}
 
-	// If the modifier's *length* is greater than 0, not if the modifier is greater than zero.
-	if len(result[3]) > 0 {
-		output.Modifier, err = strconv.Atoi(result[3])
+	if keep := strings.ToLower(submatch(result, "keep")); len(keep) > 0 {
+		spec.keep = keep[:2]
+
+		spec.keepCount, err = strconv.Atoi(keep[2:])
 		if err != nil {
 			return
 		}
 	}
 
+	return parseModifiers(spec, result)
+}
+
+/*
+ * parseWholeRoll is like parseRoll, but the whole of the input (ignoring white space) has to be one valid roll, with no text around it.
+ */
+func parseWholeRoll(input string) (spec rollSpec, err error) {
+	input = inputReplacer.Replace(input)
+
+	spec, err = parseRoll(input)
+	if err != nil {
+		return
+	}
+
+	if spec.discovered != input {
+		err = fmt.Errorf("%q is %w", input, ErrNotJustRoll)
+	}
+
+	return
+}
+
+/*
+ * validate checks a rollSpec can be rolled: there's at least one die, the die is valid, and the total can't overflow.
+ */
+func (spec rollSpec) validate() error {
+	if spec.rolls < 1 {
+		return fmt.Errorf("%w: %q rolls %d dice", ErrZeroDice, spec.discovered, spec.rolls)
+	}
+
+	if err := spec.die.validate(); err != nil {
+		return err
+	}
+
+	return spec.checkTotal()
+}
+
+/*
+ * isDigit returns whether a byte is an ASCII digit.
+ */
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+/*
+ * isLetter returns whether a byte is an ASCII letter.
+ */
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+/*
+ * parseAdvantage fills in a rollSpec for advantage ('adv') or disadvantage ('dis'): rolling twice as many dice, and keeping the highest or lowest half.
+ */
+func parseAdvantage(spec rollSpec, result []string, advantage string) (rollSpec, error) {
+	var err error
+
+	// The number of dice is optional, e.g. 'd20adv'.
+	spec.keepCount = 1
+	if rolls := submatch(result, "advrolls"); len(rolls) > 0 {
+		spec.keepCount, err = strconv.Atoi(rolls)
+		if err != nil {
+			return spec, err
+		}
+	}
+
+	spec.die.Faces, err = strconv.Atoi(submatch(result, "advfaces"))
+	if err != nil {
+		return spec, err
+	}
+
+	spec.rolls = spec.keepCount * 2
+
+	spec.keep = "kh"
+	if advantage == "dis" {
+		spec.keep = "kl"
+	}
+
+	return parseModifiers(spec, result)
+}
+
+/*
+ * parseModifiers fills in the parts of a rollSpec which come after the dice: sorting, and per-die and total modifiers.
+ */
+func parseModifiers(spec rollSpec, result []string) (rollSpec, error) {
+	var err error
+
+	spec.sort = strings.ToLower(submatch(result, "sort"))
+
+	// Per-die modifiers are written with a doubled sign, so drop one of them before converting.
+	if dieModifier := submatch(result, "diemodifier"); len(dieModifier) > 0 {
+		spec.dieModifier, err = strconv.Atoi(dieModifier[1:])
+		if err != nil {
+			return spec, err
+		}
+	}
+
+	// If the modifier's *length* is greater than 0, not if the modifier is greater than zero.
+	if modifier := submatch(result, "modifier"); len(modifier) > 0 {
+		spec.modifier, err = strconv.Atoi(modifier)
+		if err != nil {
+			return spec, err
+		}
+	}
+
+	return spec, nil
+}
+
+/*
+ * roll rolls the dice described by a rollSpec, returning a DiceRoll struct with the details.
+ */
+func (spec rollSpec) roll() (output DiceRoll) {
+	output.DiscoveredRoll = spec.discovered
+	output.Faces = spec.die.Faces
+	output.Rolls = spec.rolls
+	output.Modifier = spec.modifier
+	output.DieModifier = spec.dieModifier
+
+	source := spec.random
+	if source == nil {
+		source = random
+	}
+
+	rollDice(&output, spec.die, spec.reroll, source)
+
+	if spec.explode > 0 {
+		explodeResultsFrom(&output, spec.die, spec.explode, source)
+	}
+
+	if spec.minimum > 0 || spec.maximum > 0 {
+		clampResults(&output, spec.minimum, spec.maximum)
+	}
+
+	if spec.keep != "" {
+		keepDice(&output, spec.keep, spec.keepCount)
+	}
+
+	output.Total += output.DieModifier * (len(output.Results) - len(output.Dropped))
+
+	countNaturals(&output)
+
+	if spec.criticalAt > 0 || spec.fumbleAt > 0 {
+		countThresholds(&output, spec.criticalAt, spec.fumbleAt)
+	}
+
+	sumNatural(&output)
+
+	if spec.sort != "" {
+		sortResults(&output, spec.sort == "sd")
+	}
+
+	return
+}
+
+/*
+ * submatch returns the part of a regex match captured by the named group, or an empty string if that group didn't match.
+ */
+func submatch(result []string, name string) string {
+	return result[diceRollRegex.SubexpIndex(name)]
+}
+
+/*
+ * sortResults sorts a DiceRoll's results ascending or descending, keeping a copy of the order they were rolled in.
+ * Any dropped dice are tracked to their new positions.
+ */
+func sortResults(output *DiceRoll, descending bool) {
+	var (
+		order    = sortOrder(output.Results, descending)
+		position = make([]int, len(output.Results))
+	)
+
+	output.Unsorted = slices.Clone(output.Results)
+
+	for i, from := range order {
+		output.Results[i] = output.Unsorted[from]
+		position[from] = i
+	}
+
+	for i, from := range output.Dropped {
+		output.Dropped[i] = position[from]
+	}
+
+	slices.Sort(output.Dropped)
+}
+
+/*
+ * sortOrder returns the indexes of the values in the order a stable sort would put them, so ties stay in the order they were rolled.
+ */
+func sortOrder(values []int, descending bool) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		if descending {
+			return values[order[a]] > values[order[b]]
+		}
+
+		return values[order[a]] < values[order[b]]
+	})
+
+	return order
+}
+
+/*
+ * countNaturals counts how many kept dice came up on their highest and lowest faces, if the die is a critical die.
+ */
+func countNaturals(output *DiceRoll) {
+	criticalDiceMu.RLock()
+	critical := slices.Contains(criticalDice, output.Faces)
+	criticalDiceMu.RUnlock()
+
+	if !critical {
+		return
+	}
+
+	for _, v := range keptResults(*output) {
+		switch v {
+		case output.Faces:
+			output.NaturalMax++
+		case 1:
+			output.NaturalMin++
+		}
+	}
+}
+
+/*
+ * sumNatural fills in a DiceRoll's NaturalTotal: the dice which count towards the total, without any modifiers.
+ */
+func sumNatural(output *DiceRoll) {
+	output.NaturalTotal = 0
+
+	for _, v := range keptResults(*output) {
+		output.NaturalTotal += v
+	}
+}
+
+/*
+ * rollDice rolls the given die as many times as the DiceRoll asks for, using the given random source, filling in the results and total.
+ *   Any die which comes up 'reroll' or lower is rerolled once, keeping the new roll.
+ */
+func rollDice(output *DiceRoll, die DieSpec, reroll int, source RandSource) {
 	// Pre-allocate the Rolls slice.
 	output.Results = make([]int, output.Rolls)
 
 	// Simulate a number of dice being rolled.
 	for times := 0; times < output.Rolls; times++ {
 		// Roll one dice.
-		rolled := random.IntN(output.Faces) + 1
+		rolled := die.rollFrom(source)
+		if rolled <= reroll {
+			rolled = die.rollFrom(source)
+		}
 
 		output.Results[times] = rolled
 		output.Total += rolled
 	}
 
 	output.Total += output.Modifier
+}
 
-	return
+/*
+ * explodeFrom rolls the die once, and again every time it comes up 'on' or higher, using the given random source, returning every
+ *   roll. It stops after maxExplosions extra rolls, so a die which always explodes can't roll forever.
+ */
+func (die DieSpec) explodeFrom(on int, source RandSource) (output []int) {
+	for {
+		rolled := die.rollFrom(source)
+		output = append(output, rolled)
+
+		if rolled < on || len(output) > maxExplosions {
+			return
+		}
+	}
+}
+
+/*
+ * explodeResultsFrom rolls each die in a DiceRoll which came up 'on' or higher again, as with explodeFrom, using the given random
+ *   source, putting the extra rolls straight after it in the results and adding them to the total.
+ */
+func explodeResultsFrom(output *DiceRoll, die DieSpec, on int, source RandSource) {
+	var results []int
+
+	for _, result := range output.Results {
+		results = append(results, result)
+
+		// The die has been rolled once already, so it can only be rolled maxExplosions more times.
+		if result >= on {
+			extra := die.explodeFrom(on, source)
+			extra = extra[:min(len(extra), maxExplosions)]
+			results = append(results, extra...)
+
+			for _, rolled := range extra {
+				output.Total += rolled
+			}
+		}
+	}
+
+	output.Results = results
+}
+
+/*
+ * roll rolls the die once, using the package's random source.
+ */
+func (die DieSpec) roll() int {
+	return die.rollFrom(random)
+}
+
+/*
+ * rollFrom rolls the die once, using the given random source. Weighted dice pick a point along the combined weight of all faces and
+ *   walk the faces until it's reached.
+ */
+func (die DieSpec) rollFrom(source RandSource) int {
+	if len(die.Weights) == 0 {
+		return source.IntN(die.Faces) + 1
+	}
+
+	var total int
+	for _, w := range die.Weights {
+		total += w
+	}
+
+	point := source.IntN(total)
+	for i, w := range die.Weights {
+		if point < w {
+			return i + 1
+		}
+
+		point -= w
+	}
+
+	// Unreachable as long as validate() has been called.
+	return die.Faces
+}
+
+/*
+ * validate checks that a DieSpec can actually be rolled.
+ */
+func (die DieSpec) validate() error {
+	if die.Faces < 1 {
+		return ErrZeroFaces
+	}
+
+	if len(die.Weights) == 0 {
+		return nil
+	}
+
+	if len(die.Weights) != die.Faces {
+		return fmt.Errorf("a %d-faced die needs %d weights, got %d", die.Faces, die.Faces, len(die.Weights))
+	}
+
+	var total int
+	for _, w := range die.Weights {
+		if w < 0 {
+			return errors.New("face weights cannot be negative")
+		}
+
+		total += w
+	}
+
+	if total == 0 {
+		return errors.New("at least one face needs a weight above zero")
+	}
+
+	return nil
 }
 
 /*
  * parse takes in one string and uses a regex to find dice rolls and returns any and all as a slice of strings.
  */
-func parse(input string) []string {
-	return diceRollRegex.FindAllString(inputReplacer.Replace(input), -1)
+func parse(input string) (output []string) {
+	input = stripSpace(input)
+
+	for _, match := range matchRolls(input, -1) {
+		output = append(output, input[match[0]:match[1]])
+	}
+
+	return
 }
 
 /*
- * prettify is the function that builds the string from the available data.
+ * stripSpace removes white space from the input, as inputReplacer does, but leaves one space between two letters, so words stay
+ *   apart: '2d6 swords' is '2d6swords', but '4d6s and' stays as it is, and is sorted.
  */
-func prettify(input DiceRoll, full bool) (output string) {
+func stripSpace(input string) string {
+	output, _ := stripSpaceOffsets(input)
+
+	return output
+}
+
+/*
+ * stripSpaceOffsets is stripSpace, but also returns where in the input each byte of the output came from, plus one for the end of the
+ *   input. A space left between two words comes from the start of the second.
+ */
+func stripSpaceOffsets(input string) (string, []int) {
 	var (
-		totalsStr = make([]string, len(input.Results))
-		total     int
+		output  strings.Builder
+		offsets []int
+		spaced  bool
 	)
 
-	if full {
-		output += strings.ToLower(input.DiscoveredRoll) + ": "
+	for i := 0; i < len(input); i++ {
+		switch b := input[i]; {
+		case b == ' ' || b == '\t' || b == '\n':
+			spaced = true
+		default:
+			if spaced && isLetter(b) && output.Len() > 0 && isLetter(output.String()[output.Len()-1]) {
+				output.WriteByte(' ')
+				offsets = append(offsets, i)
+			}
+
+			output.WriteByte(b)
+			offsets = append(offsets, i)
+			spaced = false
+		}
 	}
 
-	for i, v := range input.Results {
-		totalsStr[i] = strconv.Itoa(v)
-		total += v
+	return output.String(), append(offsets, len(input))
+}
+
+/*
+ * matchRolls finds up to n rolls in the input (all of them if n is negative), as diceRollRegex.FindAllStringSubmatchIndex does,
+ *   except that a letter suffix followed by another letter is the start of a word, not part of the roll: '2d6swords' is 2d6, not
+ *   sorted, and '1d20disadvantage' is 1d20.
+ */
+func matchRolls(input string, n int) (output [][]int) {
+	for offset := 0; offset < len(input) && (n < 0 || len(output) < n); {
+		match := findRollFrom(input, offset)
+		if match == nil {
+			break
+		}
+
+		// Take the suffix off and look again, up to where it started, for the same roll without it, e.g. 2d6 from '2d6swords'.
+		for match != nil {
+			suffix := wordSuffix(input, match)
+			if suffix < 0 {
+				break
+			}
+
+			match = findRollFrom(input[:suffix], offset)
+		}
+
+		if match == nil {
+			offset++
+			continue
+		}
+
+		output = append(output, match)
+		offset = match[1]
 	}
 
-	output += strings.Join(totalsStr, " + ")
+	return
+}
 
-	switch {
-	case input.Modifier > 0:
-		output += fmt.Sprintf(" (+%d)", input.Modifier)
-	case input.Modifier < 0:
-		output += fmt.Sprintf(" (-%.0f)", math.Abs(float64(input.Modifier)))
+/*
+ * findRollFrom returns diceRollRegex's first match in the input at or after the offset, with indexes into the whole input.
+ */
+func findRollFrom(input string, offset int) []int {
+	match := diceRollRegex.FindStringSubmatchIndex(input[offset:])
+
+	for i := range match {
+		if match[i] >= 0 {
+			match[i] += offset
+		}
 	}
 
-	// Rolling 1Dn with no modifier looks weird when output as e.g. `1d6: 1 = 1.` so we handle that here.
-	if len(totalsStr) > 1 || input.Modifier != 0 {
-		output += fmt.Sprintf(" = %d", total+input.Modifier)
+	return match
+}
+
+/*
+ * wordSuffix returns where a match's letter suffix, sorting or advantage, starts if another letter follows it, or -1 if not.
+ */
+func wordSuffix(input string, match []int) int {
+	for _, name := range []string{"sort", "advantage"} {
+		start, end := match[2*diceRollRegex.SubexpIndex(name)], match[2*diceRollRegex.SubexpIndex(name)+1]
+		if start >= 0 && end < len(input) && isLetter(input[end]) {
+			return start
+		}
 	}
 
-	return
+	return -1
+}
+
+/*
+ * prettify is the function that builds the string from the available data, as the default Formatter does.
+ */
+func prettify(input DiceRoll, full bool) (output string) {
+	return Formatter{ShowRoll: full}.Format(input)
+}
+
+/*
+ * naturalStr describes how many dice came up naturally on a face.
+ * e.g. "nat 20", or "nat 1 x2"
+ */
+func naturalStr(face, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("nat %d", face)
+	}
+
+	return fmt.Sprintf("nat %d x%d", face, count)
 }
 
 /*
@@ -19,6 +19,7 @@
 package diceroller
 
 import (
+	"errors"
 	"math/rand/v2"
 	"os"
 	"reflect"
@@ -26,11 +27,16 @@ import (
 )
 
 func TestMain(m *testing.M) {
-	random = rand.New(rand.NewPCG(42, 1024))
+	reseed()
 
 	os.Exit(m.Run())
 }
 
+// reseed resets the random source, so tests which roll dice don't depend on what ran before them.
+func reseed() {
+	randomSource.set(rand.NewPCG(42, 1024))
+}
+
 type rollOneTest struct {
 	got  string
 	want int
@@ -43,6 +49,9 @@ var rollOneTests = []rollOneTest{
 
 // TestRollOne calls diceroller.RollOne with one valid dice roll string (e.g. '2d6'), checking for valid return values.
 func TestRollOne(t *testing.T) {
+	// The roll tests which follow carry on from this one's random source, so start it afresh.
+	reseed()
+
 	for _, test := range rollOneTests {
 		output, err := RollOne(test.got)
 
@@ -119,7 +128,7 @@ type rollDetailsTest struct {
 }
 
 var rollDetailsTests = []rollDetailsTest{
-	{[]string{"2d6", "4d4+4"}, []DiceRoll{{"2d6", 6, 2, 0, []int{1, 2}, 3}, {"4d4+4", 4, 4, 4, []int{3, 2, 3, 4}, 16}}},
+	{[]string{"2d6", "4d4+4"}, []DiceRoll{{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3, NaturalTotal: 3}, {DiscoveredRoll: "4d4+4", Faces: 4, Rolls: 4, Modifier: 4, Results: []int{3, 2, 3, 4}, Total: 16, NaturalTotal: 12}}},
 }
 
 // TestRollDetails calls diceroller.RollDetails with one or more valid dice roll string (e.g. '2d6'), checking for valid return values.
@@ -140,6 +149,158 @@ func BenchmarkRollDetails(b *testing.B) {
 	}
 }
 
+var rollDetailsSortTests = []rollDetailsTest{
+	{[]string{"4d6s", "4d6sd+1"}, []DiceRoll{
+		{DiscoveredRoll: "4d6s", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{3, 4, 6, 6}, Total: 19, NaturalTotal: 19, Unsorted: []int{6, 3, 6, 4}},
+		{DiscoveredRoll: "4d6sd+1", Faces: 6, Rolls: 4, Modifier: 1, Results: []int{6, 4, 3, 1}, Total: 15, NaturalTotal: 14, Unsorted: []int{6, 1, 3, 4}},
+	}},
+}
+
+// TestRollDetailsSort calls diceroller.RollDetails with sorted dice roll strings (e.g. '4d6s'), checking for valid return values.
+func TestRollDetailsSort(t *testing.T) {
+	reseed()
+
+	for _, test := range rollDetailsSortTests {
+		output, err := RollDetails(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+var rollDetailsDieModifierTests = []rollDetailsTest{
+	{[]string{"4d6++1", "2d6--1+2", "3d6kh2++2"}, []DiceRoll{
+		{DiscoveredRoll: "4d6++1", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{6, 3, 6, 4}, Total: 23, NaturalTotal: 19, DieModifier: 1},
+		{DiscoveredRoll: "2d6--1+2", Faces: 6, Rolls: 2, Modifier: 2, Results: []int{6, 1}, Total: 7, NaturalTotal: 7, DieModifier: -1},
+		{DiscoveredRoll: "3d6kh2++2", Faces: 6, Rolls: 3, Modifier: 0, Results: []int{3, 4, 2}, Total: 11, NaturalTotal: 7, Dropped: []int{2}, DieModifier: 2},
+	}},
+}
+
+// TestRollDetailsDieModifier calls diceroller.RollDetails with per-die modifiers (e.g. '4d6++1'), checking for valid return values.
+func TestRollDetailsDieModifier(t *testing.T) {
+	reseed()
+
+	for _, test := range rollDetailsDieModifierTests {
+		output, err := RollDetails(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+var rollDetailsNaturalsTests = []rollDetailsTest{
+	{[]string{"10d20", "3d6", "2d20kl1"}, []DiceRoll{
+		{DiscoveredRoll: "10d20", Faces: 20, Rolls: 10, Modifier: 0, Results: []int{19, 10, 19, 11, 20, 1, 8, 13, 6, 4}, Total: 111, NaturalTotal: 111, NaturalMax: 1, NaturalMin: 1},
+		{DiscoveredRoll: "3d6", Faces: 6, Rolls: 3, Modifier: 0, Results: []int{1, 6, 3}, Total: 10, NaturalTotal: 10},
+		{DiscoveredRoll: "2d20kl1", Faces: 20, Rolls: 2, Modifier: 0, Results: []int{19, 14}, Total: 14, NaturalTotal: 14, Dropped: []int{0}},
+	}},
+}
+
+// TestRollDetailsNaturals calls diceroller.RollDetails with rolls of critical and non-critical dice, checking NaturalMax and NaturalMin.
+func TestRollDetailsNaturals(t *testing.T) {
+	reseed()
+
+	for _, test := range rollDetailsNaturalsTests {
+		output, err := RollDetails(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+type rollErrorTest struct {
+	got  string
+	want error
+}
+
+var rollErrorTests = []rollErrorTest{
+	{"", ErrNoRollFound},
+	{"hello", ErrNoRollFound},
+	{"d6", ErrNoRollFound},
+	{"2d0", ErrZeroFaces},
+	{"0d6", ErrZeroDice},
+	{"0d20adv", ErrZeroDice},
+	{"123456d6", ErrTooLarge},
+	{"2d123456", ErrTooLarge},
+	{"2d6+123456", ErrTooLarge},
+	{"4d6kh123456", ErrTooLarge},
+}
+
+// TestRollErrors calls the public functions with bad rolls, checking each returns the right error rather than panicking.
+func TestRollErrors(t *testing.T) {
+	for _, test := range rollErrorTests {
+		for name, call := range map[string]func(string) error{
+			"RollOne":      func(input string) error { _, err := RollOne(input); return err },
+			"RollDetails":  func(input string) error { _, err := RollDetails(input); return err },
+			"Roller":       func(input string) error { _, err := NewRoller(WithSeed(1, 2)).RollTotal(input); return err },
+			"Distribution": func(input string) error { _, err := Distribution(input); return err },
+			"Simulate":     func(input string) error { _, err := Simulate(input, 10); return err },
+		} {
+			if err := call(test.got); !errors.Is(err, test.want) {
+				t.Errorf("have err %v, wanted %v from %s(%q)", err, test.want, name, test.got)
+			}
+		}
+	}
+
+	for _, input := range []string{"99999d6", "2d99999+99999", "roll 4d6s1", "12d6 and 6d12"} {
+		if _, err := RollOne(input); err != nil {
+			t.Errorf("have err %v, wanted none for %q", err, input)
+		}
+	}
+
+	if _, err := RollDie(DieSpec{Faces: 6}, 0, 0); !errors.Is(err, ErrZeroDice) {
+		t.Errorf("have err %v, wanted ErrZeroDice", err)
+	}
+
+	if _, err := RollDie(DieSpec{}, 1, 0); !errors.Is(err, ErrZeroFaces) {
+		t.Errorf("have err %v, wanted ErrZeroFaces", err)
+	}
+}
+
+// TestSetSeed calls diceroller.SetSeed, checking the same seed gives the same rolls, and the same as a Roller with that seed.
+func TestSetSeed(t *testing.T) {
+	defer reseed()
+
+	SetSeed(7)
+	want, _ := RollDetails("4d6kh3", "1d20+5", "10d10")
+
+	SetSeed(7)
+	if output, err := RollDetails("4d6kh3", "1d20+5", "10d10"); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", output, want, err)
+	}
+
+	if output, err := NewRoller(WithSeed(7, 7)).RollDetails("4d6kh3", "1d20+5", "10d10"); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, wanted the same as a Roller seeded with 7: %v, err %v", output, want, err)
+	}
+
+	SetSeed(8)
+	if output, _ := RollDetails("4d6kh3", "1d20+5", "10d10"); reflect.DeepEqual(output, want) {
+		t.Errorf("have %v, wanted a different seed to give different rolls", output)
+	}
+}
+
+// TestSetCriticalDice calls diceroller.SetCriticalDice, checking that only the given dice sizes get NaturalMax and NaturalMin.
+func TestSetCriticalDice(t *testing.T) {
+	defer SetCriticalDice(20)
+
+	SetCriticalDice(6)
+
+	output, err := RollDie(DieSpec{Faces: 6, Weights: []int{1, 0, 0, 0, 0, 1}}, 20, 0)
+	if output.NaturalMax+output.NaturalMin != 20 || err != nil {
+		t.Errorf("have %d and %d naturals, wanted 20 between them, err %v", output.NaturalMax, output.NaturalMin, err)
+	}
+
+	SetCriticalDice()
+
+	output, err = RollDie(DieSpec{Faces: 20, Weights: append(make([]int, 19), 1)}, 1, 0)
+	if output.NaturalMax != 0 || err != nil {
+		t.Errorf("have %d natural 20s, wanted 0, err %v", output.NaturalMax, err)
+	}
+}
+
 type parseTest struct {
 	got  string
 	want []string
@@ -174,6 +335,17 @@ d
 +
 2 `, []string{"2d6+2"}},
 
+	{"roll 4d6s and 4 d 6 SD", []string{"4d6s", "4d6SD"}},
+	{"roll 4d6s+2", []string{"4d6s+2"}},
+	{"roll 4d6kh3, 3 d 20 KM 1 and 2d20kl1+5", []string{"4d6kh3", "3d20KM1", "2d20kl1+5"}},
+	{"roll 4d6++1, 2 d 6 -- 1 + 2 and 4d6kh3++1", []string{"4d6++1", "2d6--1+2", "4d6kh3++1"}},
+	{"attack d20adv+5, then d20 DIS and 2d6adv", []string{"d20adv+5", "d20DIS", "2d6adv"}},
+	{"roll a d6 and d 20", nil},
+
+	// Prose after a roll which starts with a suffix's letters isn't part of the roll.
+	{"roll 2d6 swords and 1d20 disadvantage", []string{"2d6", "1d20"}},
+	{"2d6swords, 4d6sdx, d20advantage and 3d6s", []string{"2d6", "4d6", "3d6s"}},
+
 	{"So 1d6 +2 of something and 2d8-3 harmless something else and 3D12+0 whatever of 8d10+20 nope.", []string{"1d6+2", "2d8-3", "3D12+0", "8d10+20"}},
 }
 
@@ -201,8 +373,12 @@ type prettifyTest struct {
 }
 
 var prettifyTests = []prettifyTest{
-	{[]DiceRoll{{"2d6", 6, 2, 0, []int{1, 2}, 3}, {"4d4+4", 4, 4, 4, []int{3, 2, 3, 4}, 16}}, []string{"1 + 2 = 3", "3 + 2 + 3 + 4 (+4) = 16"}},
-	{[]DiceRoll{{"1d4-1", 1, 4, -1, []int{2}, 1}}, []string{"2 (-1) = 1"}},
+	{[]DiceRoll{{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}, {DiscoveredRoll: "4d4+4", Faces: 4, Rolls: 4, Modifier: 4, Results: []int{3, 2, 3, 4}, Total: 16}}, []string{"1 + 2 = 3", "3 + 2 + 3 + 4 (+4) = 16"}},
+	{[]DiceRoll{{DiscoveredRoll: "1d4-1", Faces: 1, Rolls: 4, Modifier: -1, Results: []int{2}, Total: 1}}, []string{"2 (-1) = 1"}},
+	{[]DiceRoll{{DiscoveredRoll: "2d6++1+2", Faces: 6, Rolls: 2, Modifier: 2, Results: []int{3, 5}, Total: 12, DieModifier: 1}}, []string{"3 + 5 (+1 each) (+2) = 12"}},
+	{[]DiceRoll{{DiscoveredRoll: "1d6--1", Faces: 6, Rolls: 1, Modifier: 0, Results: []int{3}, Total: 2, DieModifier: -1}}, []string{"3 (-1 each) = 2"}},
+	{[]DiceRoll{{DiscoveredRoll: "1d20+5", Faces: 20, Rolls: 1, Modifier: 5, Results: []int{20}, Total: 25, NaturalMax: 1}}, []string{"20 (+5) = 25 (nat 20)"}},
+	{[]DiceRoll{{DiscoveredRoll: "3d20", Faces: 20, Rolls: 3, Modifier: 0, Results: []int{1, 20, 1}, Total: 22, NaturalMax: 1, NaturalMin: 2}}, []string{"1 + 20 + 1 = 22 (nat 20, nat 1 x2)"}},
 }
 
 // TestPrettify calls diceroller.Prettify with one or more valid DiceRoll structs, checking for valid return values.
@@ -224,7 +400,7 @@ func BenchmarkPrettify(b *testing.B) {
 }
 
 var prettifyFullTests = []prettifyTest{
-	{[]DiceRoll{{"2d6", 6, 2, 0, []int{1, 2}, 3}, {"4d4+4", 4, 4, 4, []int{3, 2, 3, 4}, 16}}, []string{"2d6: 1 + 2 = 3", "4d4+4: 3 + 2 + 3 + 4 (+4) = 16"}},
+	{[]DiceRoll{{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}, {DiscoveredRoll: "4d4+4", Faces: 4, Rolls: 4, Modifier: 4, Results: []int{3, 2, 3, 4}, Total: 16}}, []string{"2d6: 1 + 2 = 3", "4d4+4: 3 + 2 + 3 + 4 (+4) = 16"}},
 }
 
 // TestPrettifyFull calls diceroller.PrettifyWide with one or more valid DiceRoll structs, checking for valid return values.
@@ -251,7 +427,7 @@ type prettifyOneTest struct {
 }
 
 var prettifyOneTests = []prettifyOneTest{
-	{DiceRoll{"2d6", 6, 2, 0, []int{1, 2}, 3}, "1 + 2 = 3"},
+	{DiceRoll{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}, "1 + 2 = 3"},
 }
 
 // TestPrettifyOne calls diceroller.PrettifyOne with one valid DiceRoll structs, checking for valid return values.
@@ -273,7 +449,7 @@ func BenchmarkPrettifyOne(b *testing.B) {
 }
 
 var prettifyOneFullTests = []prettifyOneTest{
-	{DiceRoll{"2d6", 6, 2, 0, []int{1, 2}, 3}, "2d6: 1 + 2 = 3"},
+	{DiceRoll{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}, "2d6: 1 + 2 = 3"},
 }
 
 // TestPrettifyOneFull calls diceroller.PrettifyOneFull with one valid DiceRoll structs, checking for valid return values.
@@ -300,9 +476,9 @@ type prettifyHTMLTest struct {
 }
 
 var prettifyHTMLTests = []prettifyHTMLTest{
-	{[]DiceRoll{{"1d6", 6, 1, 0, []int{4}, 4}}, []string{"<strong>4</strong>"}},
-	{[]DiceRoll{{"2d6", 6, 2, 0, []int{1, 2}, 3}}, []string{"<strong>1 + 2 = 3</strong>"}},
-	{[]DiceRoll{{"3d6+4", 6, 3, 4, []int{1, 2, 3}, 4}}, []string{"<strong>1 + 2 + 3 (+4) = 10</strong>"}},
+	{[]DiceRoll{{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Modifier: 0, Results: []int{4}, Total: 4}}, []string{"<strong>4</strong>"}},
+	{[]DiceRoll{{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}}, []string{"<strong>1 + 2 = 3</strong>"}},
+	{[]DiceRoll{{DiscoveredRoll: "3d6+4", Faces: 6, Rolls: 3, Modifier: 4, Results: []int{1, 2, 3}, Total: 4}}, []string{"<strong>1 + 2 + 3 (+4) = 10</strong>"}},
 }
 
 // TestPrettifyHTML calls diceroller.PrettifyHTML with one valid DiceRoll struct, checking for valid return values.
@@ -324,11 +500,11 @@ func BenchmarkPrettifyHTML(b *testing.B) {
 }
 
 var prettifyHTMLFullTests = []prettifyHTMLTest{
-	{[]DiceRoll{{"1d6", 6, 1, 0, []int{4}, 4}}, []string{"<strong>1d6:</strong> <em>4</em>"}},
-	{[]DiceRoll{{"2d6", 6, 2, 0, []int{1, 2}, 3}}, []string{"<strong>2d6:</strong> <em>1 + 2 = 3</em>"}},
-	{[]DiceRoll{{"3d6+4", 6, 3, 4, []int{1, 2, 3}, 4}}, []string{"<strong>3d6+4:</strong> <em>1 + 2 + 3 (+4) = 10</em>"}},
+	{[]DiceRoll{{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Modifier: 0, Results: []int{4}, Total: 4}}, []string{"<strong>1d6:</strong> <em>4</em>"}},
+	{[]DiceRoll{{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}}, []string{"<strong>2d6:</strong> <em>1 + 2 = 3</em>"}},
+	{[]DiceRoll{{DiscoveredRoll: "3d6+4", Faces: 6, Rolls: 3, Modifier: 4, Results: []int{1, 2, 3}, Total: 4}}, []string{"<strong>3d6+4:</strong> <em>1 + 2 + 3 (+4) = 10</em>"}},
 	{
-		[]DiceRoll{{"1d6", 6, 1, 0, []int{4}, 4}, {"2d6", 6, 2, 0, []int{1, 2}, 3}, {"3d6+4", 6, 3, 4, []int{1, 2, 3}, 4}},
+		[]DiceRoll{{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Modifier: 0, Results: []int{4}, Total: 4}, {DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{1, 2}, Total: 3}, {DiscoveredRoll: "3d6+4", Faces: 6, Rolls: 3, Modifier: 4, Results: []int{1, 2, 3}, Total: 4}},
 		[]string{"<strong>1d6:</strong> <em>4</em>", "<strong>2d6:</strong> <em>1 + 2 = 3</em>", "<strong>3d6+4:</strong> <em>1 + 2 + 3 (+4) = 10</em>"},
 	},
 }
@@ -350,3 +526,77 @@ func BenchmarkPrettifyFullHTML(b *testing.B) {
 		PrettifyHTMLFull(prettifyHTMLFullTests[0].got)
 	}
 }
+
+type rollDieTest struct {
+	die      DieSpec
+	rolls    int
+	modifier int
+	want     DiceRoll
+}
+
+var rollDieTests = []rollDieTest{
+	{DieSpec{Faces: 6}, 2, 0, DiceRoll{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{6, 3}, Total: 9, NaturalTotal: 9}},
+	{DieSpec{Faces: 6, Weights: []int{0, 0, 0, 0, 0, 1}}, 3, 1, DiceRoll{DiscoveredRoll: "3d6+1", Faces: 6, Rolls: 3, Modifier: 1, Results: []int{6, 6, 6}, Total: 19, NaturalTotal: 18}},
+	{DieSpec{Faces: 3, Weights: []int{1, 0, 1}}, 4, -1, DiceRoll{DiscoveredRoll: "4d3-1", Faces: 3, Rolls: 4, Modifier: -1, Results: []int{1, 3, 1, 3}, Total: 7, NaturalTotal: 8}},
+}
+
+// TestRollDie calls diceroller.RollDie with uniform and weighted dice, checking for valid return values.
+func TestRollDie(t *testing.T) {
+	reseed()
+
+	for _, test := range rollDieTests {
+		output, err := RollDie(test.die, test.rolls, test.modifier)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+var rollDieErrorTests = []DieSpec{
+	{},
+	{Faces: 6, Weights: []int{1, 1}},
+	{Faces: 2, Weights: []int{1, -1}},
+	{Faces: 2, Weights: []int{0, 0}},
+}
+
+// TestRollDieErrors calls diceroller.RollDie with dice which can't be rolled, checking for errors.
+func TestRollDieErrors(t *testing.T) {
+	for _, test := range rollDieErrorTests {
+		if _, err := RollDie(test, 1, 0); err == nil {
+			t.Errorf("have nil error for %v, wanted an error", test)
+		}
+	}
+}
+
+// BenchmarkRollDie benchmarks diceroller.RollDie.
+func BenchmarkRollDie(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollDie(rollDieTests[2].die, rollDieTests[2].rolls, rollDieTests[2].modifier)
+	}
+}
+
+type newWeightedDieTest struct {
+	got  []int
+	want DieSpec
+}
+
+var newWeightedDieTests = []newWeightedDieTest{
+	{[]int{1, 1, 1, 1, 1, 5}, DieSpec{6, []int{1, 1, 1, 1, 1, 5}}},
+	{[]int{2, 1}, DieSpec{2, []int{2, 1}}},
+}
+
+// TestNewWeightedDie calls diceroller.NewWeightedDie with face weights, checking for valid return values.
+func TestNewWeightedDie(t *testing.T) {
+	for _, test := range newWeightedDieTests {
+		output, err := NewWeightedDie(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	if _, err := NewWeightedDie(); err == nil {
+		t.Errorf("have nil error for no weights, wanted an error")
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package dicetest provides a diceroller.Roller whose dice come up as you say, for unit testing game logic which depends on
+// particular rolls, without hunting for a seed which happens to roll them.
+package dicetest
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/vaughany/diceroller"
+)
+
+// Source is a diceroller.RandSource which gives fixed results, in order, one per die. It panics if it runs out, or if a result
+// doesn't fit the die being rolled, as either means the test is wrong. It's safe for concurrent use.
+type Source struct {
+	mu      sync.Mutex
+	results []int // The results still to come, next first.
+}
+
+/*
+ * NewSource returns a Source which gives the results, in order.
+ * e.g. NewSource(6, 1, 3)
+ */
+func NewSource(results ...int) *Source {
+	source := &Source{}
+	source.Push(results...)
+
+	return source
+}
+
+/*
+ * Fixed returns a Roller whose dice come up as the results, in order, e.g. to test what happens on a natural 20.
+ * e.g. Fixed(6, 1, 3).RollDetails("3d6") // {3d6 6 3 0 [6 1 3] 10 ...}
+ */
+func Fixed(results ...int) *diceroller.Roller {
+	return diceroller.NewRoller(diceroller.WithRandSource(NewSource(results...)))
+}
+
+/*
+ * IntN returns the next result, less one, as the Roller adds one to make a die's face.
+ */
+func (source *Source) IntN(n int) int {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	if len(source.results) == 0 {
+		panic(fmt.Sprintf("dicetest: no fixed results left for a d%d", n))
+	}
+
+	result := source.results[0]
+	if result < 1 || result > n {
+		panic(fmt.Sprintf("dicetest: fixed result %d can't be rolled on a d%d", result, n))
+	}
+
+	source.results = source.results[1:]
+
+	return result - 1
+}
+
+/*
+ * Push adds more results, to come after the ones already there.
+ */
+func (source *Source) Push(results ...int) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	source.results = append(source.results, results...)
+}
+
+/*
+ * Remaining returns how many results haven't been rolled yet, e.g. to check a test rolled every die it meant to.
+ */
+func (source *Source) Remaining() int {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	return len(source.results)
+}
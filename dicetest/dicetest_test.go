@@ -0,0 +1,75 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package dicetest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vaughany/diceroller"
+)
+
+// TestFixed calls dicetest.Fixed, checking the dice come up as given, in order, across rolls.
+func TestFixed(t *testing.T) {
+	roller := Fixed(6, 1, 3, 20, 4, 2, 5, 5, 3)
+
+	output, err := roller.RollDetails("3d6+1", "d20adv", "4d6kh3")
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if !reflect.DeepEqual(output[0].Results, []int{6, 1, 3}) || output[0].Total != 11 {
+		t.Errorf("have %v, wanted [6 1 3] for 11", output[0])
+	}
+
+	if !reflect.DeepEqual(output[1].Results, []int{20, 4}) || output[1].Total != 20 || output[1].NaturalMax != 1 {
+		t.Errorf("have %v, wanted [20 4] for a natural 20", output[1])
+	}
+
+	if output[2].Total != 13 || !reflect.DeepEqual(output[2].Dropped, []int{0}) {
+		t.Errorf("have %v, wanted the 2 dropped for 13", output[2])
+	}
+}
+
+// TestSource calls dicetest.Source's methods, checking results can be added and counted, and that misuse panics.
+func TestSource(t *testing.T) {
+	source := NewSource(2)
+	source.Push(3, 4)
+
+	roller := diceroller.NewRoller(diceroller.WithRandSource(source))
+	if total, err := roller.RollOne("2d4"); total != 5 || err != nil || source.Remaining() != 1 {
+		t.Errorf("have %d with %d left, wanted 5 with 1 left, err %v", total, source.Remaining(), err)
+	}
+
+	for name, roll := range map[string]func(){
+		"too high": func() { _, _ = roller.RollOne("1d3") },
+		"run out":  func() { _, _ = Fixed().RollOne("1d6") },
+		"zero":     func() { _, _ = Fixed(0).RollOne("1d6") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("have no panic, wanted one for %s", name)
+				}
+			}()
+
+			roll()
+		}()
+	}
+}
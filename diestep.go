@@ -0,0 +1,119 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The dice on the step ladder, from the bottom. Above the top die, the modifier goes up; below the bottom die, it goes down.
+var dieSteps = []int{4, 6, 8, 10, 12}
+
+// DieStep is a place on the step-dice ladder, d4, d6, d8, d10, d12, d12+1, d12+2 and so on, as used by Savage Worlds and Earthdawn.
+// The zero value is a d4, and steps below it are d4-1, d4-2 and so on. It's a plain value, so it's easy to store.
+type DieStep int
+
+/*
+ * NewDieStep returns the DieStep for a die and modifier, e.g. (8, 0) for a d8, or (12, 2) for a d12+2.
+ */
+func NewDieStep(faces, modifier int) (DieStep, error) {
+	index := slices.Index(dieSteps, faces)
+
+	switch {
+	case index == -1:
+		return 0, fmt.Errorf("a d%d isn't on the step ladder", faces)
+	case modifier > 0 && index != len(dieSteps)-1, modifier < 0 && index != 0:
+		return 0, fmt.Errorf("d%d%+d isn't on the step ladder", faces, modifier)
+	}
+
+	return DieStep(index + modifier), nil
+}
+
+/*
+ * ParseDieStep returns the DieStep for a die written as e.g. 'd8', '1d8' or 'd12+2'.
+ */
+func ParseDieStep(input string) (DieStep, error) {
+	if strings.HasPrefix(input, "d") || strings.HasPrefix(input, "D") {
+		input = "1" + input
+	}
+
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return 0, err
+	}
+
+	if spec.rolls != 1 || spec.keep != "" || spec.sort != "" || spec.dieModifier != 0 {
+		return 0, fmt.Errorf("%q isn't a single die on the step ladder", input)
+	}
+
+	return NewDieStep(spec.die.Faces, spec.modifier)
+}
+
+/*
+ * Up returns the step a number of steps up the ladder, e.g. d8 up 1 is d10, and d12 up 2 is d12+2.
+ */
+func (step DieStep) Up(steps int) DieStep {
+	return step + DieStep(steps)
+}
+
+/*
+ * Down returns the step a number of steps down the ladder, e.g. d8 down 1 is d6, and d4 down 1 is d4-1.
+ */
+func (step DieStep) Down(steps int) DieStep {
+	return step - DieStep(steps)
+}
+
+/*
+ * Faces returns how many faces the step's die has.
+ */
+func (step DieStep) Faces() int {
+	return dieSteps[min(max(int(step), 0), len(dieSteps)-1)]
+}
+
+/*
+ * Modifier returns the step's modifier: positive above a d12, negative below a d4, and zero otherwise.
+ */
+func (step DieStep) Modifier() int {
+	switch top := DieStep(len(dieSteps) - 1); {
+	case step > top:
+		return int(step - top)
+	case step < 0:
+		return int(step)
+	default:
+		return 0
+	}
+}
+
+/*
+ * Roll returns the step as a roll, ready for the Roll functions.
+ * e.g. DieStep(5).Roll() // "1d12+1"
+ */
+func (step DieStep) Roll() string {
+	return newRollSpec(1, DieSpec{Faces: step.Faces()}, step.Modifier()).discovered
+}
+
+/*
+ * String returns the step as a die.
+ * e.g. "d12+1"
+ */
+func (step DieStep) String() string {
+	return strings.TrimPrefix(step.Roll(), "1")
+}
@@ -0,0 +1,71 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "testing"
+
+type dieStepTest struct {
+	step  DieStep
+	want  string
+	faces int
+	mod   int
+}
+
+var dieStepTests = []dieStepTest{
+	{-2, "d4-2", 4, -2},
+	{-1, "d4-1", 4, -1},
+	{0, "d4", 4, 0},
+	{1, "d6", 6, 0},
+	{2, "d8", 8, 0},
+	{3, "d10", 10, 0},
+	{4, "d12", 12, 0},
+	{5, "d12+1", 12, 1},
+	{7, "d12+3", 12, 3},
+}
+
+// TestDieStep calls diceroller.DieStep's methods with many steps, checking for valid return values, and that they parse back.
+func TestDieStep(t *testing.T) {
+	for _, test := range dieStepTests {
+		if output := test.step.String(); output != test.want || test.step.Faces() != test.faces || test.step.Modifier() != test.mod {
+			t.Errorf("have %q (d%d%+d), wanted %q for step %d", output, test.step.Faces(), test.step.Modifier(), test.want, test.step)
+		}
+
+		if output := test.step.Roll(); output != "1"+test.want {
+			t.Errorf("have %q, wanted %q", output, "1"+test.want)
+		}
+
+		if parsed, err := ParseDieStep(test.want); parsed != test.step || err != nil {
+			t.Errorf("have %d, wanted %d, err %v for %q", parsed, test.step, err, test.want)
+		}
+	}
+
+	d8, _ := NewDieStep(8, 0)
+	if up, down := d8.Up(3), d8.Down(3); up.String() != "d12+1" || down.String() != "d4-1" {
+		t.Errorf("have %v and %v, wanted d12+1 and d4-1", up, down)
+	}
+}
+
+// TestDieStepErrors calls diceroller.NewDieStep and diceroller.ParseDieStep with dice which aren't on the ladder, checking for errors.
+func TestDieStepErrors(t *testing.T) {
+	for _, input := range []string{"d20", "d8+1", "d6-1", "2d6", "d6s", "d8kh1", "eight"} {
+		if _, err := ParseDieStep(input); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+}
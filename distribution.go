@@ -0,0 +1,351 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// The most multiply-and-add steps we're prepared to do when working out a distribution exactly, so e.g. 99999d99999 fails rather than hangs.
+	maxDistributionWork = 500_000_000
+
+	// The most distinct sets of dice we're prepared to look at when working out a distribution with dropped dice.
+	maxDistributionSets = 2_000_000
+)
+
+// RollDistribution holds the exact probability of every possible total of a roll, as worked out by Distribution.
+type RollDistribution struct {
+	Roll          string    // The roll, as discovered, e.g. '3d6'.
+	Min           int       // The lowest possible total.
+	Max           int       // The highest possible total.
+	Mean          float64   // The average total.
+	StdDev        float64   // The standard deviation of the totals.
+	Probabilities []float64 // The probability of each total, from 0 to 1, starting with Min.
+}
+
+// distribution is the exact probability of every possible total of a roll.
+type distribution struct {
+	min   int       // The lowest possible total.
+	probs []float64 // The probability of each total, starting with min.
+}
+
+/*
+ * distribution works out the exact probability of every possible total of a rollSpec, by convolving the die with itself once per dice rolled.
+ */
+func (spec rollSpec) distribution() (output distribution, err error) {
+	if err = spec.die.validate(); err != nil {
+		return
+	}
+
+	// Dropping dice means the order of the dice matters, so we can't simply add them up.
+	if spec.drops() {
+		return spec.keptDistribution()
+	}
+
+	if work := float64(spec.rolls) * float64(spec.rolls) * float64(spec.die.Faces) * float64(spec.die.Faces) / 2; work > maxDistributionWork {
+		err = fmt.Errorf("%w: %s has too many possible totals", ErrTooComplex, spec.discovered)
+		return
+	}
+
+	die := dieDistribution(spec.die)
+
+	// Rolling no dice always totals zero.
+	output = distribution{probs: []float64{1}}
+	for times := 0; times < spec.rolls; times++ {
+		output = output.convolve(die)
+	}
+
+	output.min += spec.modifier + spec.dieModifier*spec.rolls
+
+	return output.trim(), nil
+}
+
+/*
+ * keptDistribution works out the distribution of a rollSpec which drops dice, by looking at every distinct set of dice which could be rolled
+ *   (ignoring the order they come up in), and how likely each set is.
+ */
+func (spec rollSpec) keptDistribution() (output distribution, err error) {
+	var (
+		faces  = spec.die.Faces
+		probs  = dieDistribution(spec.die).probs
+		counts = make([]int, faces)
+		values = make([]int, 0, spec.rolls)
+	)
+
+	// There are (faces+rolls-1) choose rolls distinct sets of dice.
+	if sets := math.Exp(lgamma(faces+spec.rolls) - lgamma(spec.rolls+1) - lgamma(faces)); sets > maxDistributionSets {
+		err = fmt.Errorf("%w: %s has too many possible sets of dice", ErrTooComplex, spec.discovered)
+		return
+	}
+
+	output.probs = make([]float64, spec.rolls*faces+1)
+
+	// Share out the remaining dice between this face and the faces above it.
+	var share func(face, remaining int)
+	share = func(face, remaining int) {
+		if face == faces-1 {
+			counts[face] = remaining
+			output.addSet(spec, counts, probs, values)
+
+			return
+		}
+
+		for count := remaining; count >= 0; count-- {
+			counts[face] = count
+			share(face+1, remaining-count)
+		}
+	}
+
+	share(0, spec.rolls)
+
+	output.min += spec.modifier + spec.dieModifier*spec.keepCount
+
+	return output.trim(), nil
+}
+
+/*
+ * addSet adds the probability of rolling one set of dice (how many of each face came up) to the total the set makes once dice are dropped.
+ */
+func (d *distribution) addSet(spec rollSpec, counts []int, probs []float64, values []int) {
+	// The multinomial probability of the set, worked out in logs to save overflowing on big pools.
+	logProb := lgamma(spec.rolls + 1)
+
+	values = values[:0]
+	for face, count := range counts {
+		if count == 0 {
+			continue
+		}
+
+		if probs[face] == 0 {
+			return
+		}
+
+		logProb += float64(count)*math.Log(probs[face]) - lgamma(count+1)
+
+		for times := 0; times < count; times++ {
+			values = append(values, face+1)
+		}
+	}
+
+	var total int
+	for _, v := range values {
+		total += v
+	}
+
+	for _, i := range dropIndexes(values, spec.keep, spec.keepCount) {
+		total -= values[i]
+	}
+
+	d.probs[total] += math.Exp(logProb)
+}
+
+/*
+ * lgamma returns the natural log of (n-1)!, ignoring the sign as it's always positive for the numbers we use.
+ */
+func lgamma(n int) float64 {
+	output, _ := math.Lgamma(float64(n))
+
+	return output
+}
+
+/*
+ * trim removes impossible totals from either end of a distribution.
+ */
+func (d distribution) trim() distribution {
+	for len(d.probs) > 1 && d.probs[0] == 0 {
+		d.probs = d.probs[1:]
+		d.min++
+	}
+
+	for len(d.probs) > 1 && d.probs[len(d.probs)-1] == 0 {
+		d.probs = d.probs[:len(d.probs)-1]
+	}
+
+	return d
+}
+
+/*
+ * dieDistribution returns the probability of each face of one die coming up.
+ */
+func dieDistribution(die DieSpec) (output distribution) {
+	output.min = 1
+	output.probs = make([]float64, die.Faces)
+
+	if len(die.Weights) == 0 {
+		for i := range output.probs {
+			output.probs[i] = 1 / float64(die.Faces)
+		}
+
+		return
+	}
+
+	var total int
+	for _, w := range die.Weights {
+		total += w
+	}
+
+	for i, w := range die.Weights {
+		output.probs[i] = float64(w) / float64(total)
+	}
+
+	return
+}
+
+/*
+ * convolve returns the distribution of adding a total from d to a total from other.
+ */
+func (d distribution) convolve(other distribution) (output distribution) {
+	output.min = d.min + other.min
+	output.probs = make([]float64, len(d.probs)+len(other.probs)-1)
+
+	for i, p := range d.probs {
+		for j, q := range other.probs {
+			output.probs[i+j] += p * q
+		}
+	}
+
+	return
+}
+
+/*
+ * max returns the highest possible total.
+ */
+func (d distribution) max() int {
+	return d.min + len(d.probs) - 1
+}
+
+/*
+ * exactly returns the probability of rolling exactly the given total.
+ */
+func (d distribution) exactly(total int) float64 {
+	if total < d.min || total > d.max() {
+		return 0
+	}
+
+	return d.probs[total-d.min]
+}
+
+/*
+ * atLeast returns the probability of rolling the given total or higher.
+ */
+func (d distribution) atLeast(total int) (output float64) {
+	for i, p := range d.probs {
+		if d.min+i >= total {
+			output += p
+		}
+	}
+
+	return
+}
+
+/*
+ * atMost returns the probability of rolling the given total or lower.
+ */
+func (d distribution) atMost(total int) (output float64) {
+	for i, p := range d.probs {
+		if d.min+i <= total {
+			output += p
+		}
+	}
+
+	return
+}
+
+/*
+ * mean returns the average total.
+ */
+func (d distribution) mean() (output float64) {
+	for i, p := range d.probs {
+		output += float64(d.min+i) * p
+	}
+
+	return
+}
+
+/*
+ * stddev returns the standard deviation of the totals.
+ */
+func (d distribution) stddev() float64 {
+	var (
+		mean     = d.mean()
+		variance float64
+	)
+
+	for i, p := range d.probs {
+		diff := float64(d.min+i) - mean
+		variance += diff * diff * p
+	}
+
+	return math.Sqrt(variance)
+}
+
+/*
+ * Distribution works out the exact probability of every possible total of one roll in the 'nDn+n' format, without rolling it.
+ * e.g. Distribution("2d6") // {2d6 2 12 7 2.41 [0.027 0.055 0.083 ...]}
+ */
+func Distribution(input string) (output RollDistribution, err error) {
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return
+	}
+
+	dist, err := spec.distribution()
+	if err != nil {
+		return
+	}
+
+	return RollDistribution{
+		Roll:          spec.discovered,
+		Min:           dist.min,
+		Max:           dist.max(),
+		Mean:          dist.mean(),
+		StdDev:        dist.stddev(),
+		Probabilities: dist.probs,
+	}, nil
+}
+
+/*
+ * Exactly returns the probability of rolling exactly the given total.
+ */
+func (output RollDistribution) Exactly(total int) float64 {
+	return output.distribution().exactly(total)
+}
+
+/*
+ * AtLeast returns the probability of rolling the given total or higher.
+ */
+func (output RollDistribution) AtLeast(total int) float64 {
+	return output.distribution().atLeast(total)
+}
+
+/*
+ * AtMost returns the probability of rolling the given total or lower.
+ */
+func (output RollDistribution) AtMost(total int) float64 {
+	return output.distribution().atMost(total)
+}
+
+/*
+ * distribution returns the RollDistribution as a distribution, so it can be queried.
+ */
+func (output RollDistribution) distribution() distribution {
+	return distribution{min: output.Min, probs: output.Probabilities}
+}
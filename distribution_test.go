@@ -0,0 +1,76 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+type distributionTest struct {
+	got               string
+	wantRoll          string
+	wantMin, wantMax  int
+	wantMean, wantStd float64
+}
+
+var distributionTests = []distributionTest{
+	{"1d6", "1d6", 1, 6, 3.5, 1.707825127659933},
+	{"2d6+3", "2d6+3", 5, 15, 10, 2.41522945769824},
+	{"4d6kh3", "4d6kh3", 3, 18, 12.244598765432098, 2.8468444453115005},
+	{"d20adv", "d20adv", 1, 20, 13.825, 4.711090638058241},
+}
+
+// TestDistribution calls diceroller.Distribution with many rolls, checking for valid return values.
+func TestDistribution(t *testing.T) {
+	for _, test := range distributionTests {
+		output, err := Distribution(test.got)
+
+		if output.Roll != test.wantRoll || output.Min != test.wantMin || output.Max != test.wantMax ||
+			math.Abs(output.Mean-test.wantMean) > 1e-9 || math.Abs(output.StdDev-test.wantStd) > 1e-9 || err != nil {
+			t.Errorf("have %v %d %d %v %v, wanted %v %d %d %v %v, err %v", output.Roll, output.Min, output.Max, output.Mean, output.StdDev,
+				test.wantRoll, test.wantMin, test.wantMax, test.wantMean, test.wantStd, err)
+		}
+
+		if len(output.Probabilities) != output.Max-output.Min+1 {
+			t.Errorf("have %d probabilities, wanted %d for %q", len(output.Probabilities), output.Max-output.Min+1, test.got)
+		}
+	}
+
+	output, _ := Distribution("2d6")
+	for total, want := range map[int][3]float64{7: {6.0 / 36, 21.0 / 36, 21.0 / 36}, 2: {1.0 / 36, 1, 1.0 / 36}, 13: {0, 0, 1}} {
+		if have := [3]float64{output.Exactly(total), output.AtLeast(total), output.AtMost(total)}; math.Abs(have[0]-want[0]) > 1e-9 ||
+			math.Abs(have[1]-want[1]) > 1e-9 || math.Abs(have[2]-want[2]) > 1e-9 {
+			t.Errorf("have %v, wanted %v for %d on 2d6", have, want, total)
+		}
+	}
+
+	for _, input := range []string{"", "2d6 please", "2d0", "99999d99999"} {
+		if _, err := Distribution(input); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+}
+
+// BenchmarkDistribution benchmarks diceroller.Distribution.
+func BenchmarkDistribution(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Distribution("4d6kh3")
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The message catalogs Explain uses, one JSON file per language, named for its code, e.g. 'fr.json'.
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var (
+	// The messages in each language, by language code and then message key. English is the fallback for missing messages.
+	explainCatalogs = loadExplainCatalogs()
+
+	// ErrUnsupportedLanguage is returned when explaining a roll in a language there's no message catalog for.
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+)
+
+/*
+ * loadExplainCatalogs reads the embedded message catalogs. They're part of the package, so a bad one is a bug, and panics.
+ */
+func loadExplainCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := map[string]map[string]string{}
+
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var messages map[string]string
+		if err = json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("locales/%s: %v", file.Name(), err))
+		}
+
+		catalogs[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+
+	return catalogs
+}
+
+/*
+ * Languages returns the codes of the languages Explain can use, in alphabetical order.
+ * e.g. Languages() // ["de", "en", "es", "fr"]
+ */
+func Languages() (output []string) {
+	for language := range explainCatalogs {
+		output = append(output, language)
+	}
+
+	sort.Strings(output)
+
+	return
+}
+
+/*
+ * Explain describes one roll in the 'nDn+n' format in plain English, for players learning the notation.
+ * e.g. Explain("4d6kh3+1") // "Roll 4 6-sided dice, keep the highest 3 and add 1 to the total."
+ */
+func Explain(input string) (string, error) {
+	return ExplainIn("en", input)
+}
+
+/*
+ * ExplainIn describes one roll in the 'nDn+n' format in plain words in a language, by its code, e.g. 'fr', 'de' or 'es'. A region
+ *   is ignored, so 'fr-CA' is French. A language with no message catalog returns ErrUnsupportedLanguage.
+ * e.g. ExplainIn("fr", "2d6+3") // "Lancez 2 dés à 6 faces et ajoutez 3 au total."
+ */
+func ExplainIn(language, input string) (string, error) {
+	language, _, _ = strings.Cut(strings.ToLower(language), "-")
+	language, _, _ = strings.Cut(language, "_")
+
+	messages, ok := explainCatalogs[language]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedLanguage, language)
+	}
+
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return "", err
+	}
+
+	message := func(key string, args ...any) string {
+		format, ok := messages[key]
+		if !ok {
+			format = explainCatalogs["en"][key]
+		}
+
+		// Messages such as 'keep the highest' leave their number out, which Sprintf would complain about.
+		if !strings.Contains(format, "%") {
+			return format
+		}
+
+		return fmt.Sprintf(format, args...)
+	}
+
+	plural := func(key string, count int) string {
+		if count == 1 {
+			return key + "_one"
+		}
+
+		return key + "_other"
+	}
+
+	parts := []string{message(plural("dice", spec.rolls), spec.rolls, spec.die.Faces)}
+
+	if spec.keep != "" && spec.keepCount < spec.rolls {
+		keep := map[string]string{"kh": "keep_highest", "kl": "keep_lowest", "km": "keep_middle"}[spec.keep]
+		parts = append(parts, message(plural(keep, spec.keepCount), spec.keepCount))
+	}
+
+	switch {
+	case spec.dieModifier > 0:
+		parts = append(parts, message("die_add", spec.dieModifier))
+	case spec.dieModifier < 0:
+		parts = append(parts, message("die_subtract", -spec.dieModifier))
+	}
+
+	switch {
+	case spec.modifier > 0:
+		parts = append(parts, message("add", spec.modifier))
+	case spec.modifier < 0:
+		parts = append(parts, message("subtract", -spec.modifier))
+	}
+
+	switch spec.sort {
+	case "s":
+		parts = append(parts, message("sort_ascending"))
+	case "sd":
+		parts = append(parts, message("sort_descending"))
+	}
+
+	return capitalise(joinExplanation(parts, message("and"))) + ".", nil
+}
+
+/*
+ * joinExplanation joins the parts of an explanation with commas, and the word for 'and' before the last one.
+ * e.g. joinExplanation([]string{"a", "b", "c"}, "and") // "a, b and c"
+ */
+func joinExplanation(parts []string, and string) string {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return strings.Join(parts[:len(parts)-1], ", ") + " " + and + " " + parts[len(parts)-1]
+}
+
+/*
+ * capitalise returns a string with its first letter in upper case.
+ */
+func capitalise(input string) string {
+	first, size := utf8.DecodeRuneInString(input)
+
+	return string(unicode.ToUpper(first)) + input[size:]
+}
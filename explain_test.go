@@ -0,0 +1,104 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type explainTest struct {
+	language string
+	got      string
+	want     string
+}
+
+var explainTests = []explainTest{
+	{"en", "1d20", "Roll a 20-sided die."},
+	{"en", "4d6kh3+1", "Roll 4 6-sided dice, keep the highest 3 and add 1 to the total."},
+	{"en", "d20adv-2", "Roll 2 20-sided dice, keep the highest and take 2 off the total."},
+	{"en", "3d6km1sd--1", "Roll 3 6-sided dice, keep the middle one, take 1 off each kept die and sort the results from highest to lowest."},
+	{"en", "2d6kh5", "Roll 2 6-sided dice."},
+	{"fr", "2d6+3", "Lancez 2 dés à 6 faces et ajoutez 3 au total."},
+	{"fr-CA", "4d6kl1s++1", "Lancez 4 dés à 6 faces, gardez le plus bas, ajoutez 1 à chaque dé gardé et triez les résultats du plus petit au plus grand."},
+	{"de", "1d8", "Wirf einen 8-seitigen Würfel."},
+	{"DE_at", "4d6kh3-1", "Wirf 4 6-seitige Würfel, behalte die höchsten 3 und ziehe 1 von der Summe ab."},
+	{"es", "d20dis", "Tira 2 dados de 20 caras y quédate con el más bajo."},
+	{"es", "5d10kl2sd", "Tira 5 dados de 10 caras, quédate con los 2 más bajos y ordena los resultados de mayor a menor."},
+}
+
+// TestExplainIn calls diceroller.ExplainIn with many languages and rolls, checking for valid return values.
+func TestExplainIn(t *testing.T) {
+	for _, test := range explainTests {
+		output, err := ExplainIn(test.language, test.got)
+
+		if output != test.want || err != nil {
+			t.Errorf("have %q, wanted %q for %q in %q, err %v", output, test.want, test.got, test.language, err)
+		}
+	}
+
+	if output, err := Explain("2d6+3"); output != "Roll 2 6-sided dice and add 3 to the total." || err != nil {
+		t.Errorf("have %q, err %v", output, err)
+	}
+
+	if output, err := ExplainIn("tlh", "2d6"); !errors.Is(err, ErrUnsupportedLanguage) || output != "" {
+		t.Errorf("have %q, err %v, wanted ErrUnsupportedLanguage", output, err)
+	}
+
+	for _, input := range []string{"", "2d6 please", "2d0"} {
+		if output, err := ExplainIn("en", input); err == nil {
+			t.Errorf("have %q, nil error for %q, wanted an error", output, input)
+		}
+	}
+}
+
+// TestExplainCatalogs checks every message catalog has the same messages as the English one, with the same numbers in them.
+func TestExplainCatalogs(t *testing.T) {
+	if languages := Languages(); !reflect.DeepEqual(languages, []string{"de", "en", "es", "fr"}) {
+		t.Errorf("have %v, wanted %v", languages, []string{"de", "en", "es", "fr"})
+	}
+
+	for language, messages := range explainCatalogs {
+		for key, english := range explainCatalogs["en"] {
+			message, ok := messages[key]
+			if !ok {
+				t.Errorf("have no %q message in %q, wanted one", key, language)
+			}
+
+			for _, verb := range []string{"%[1]d", "%[2]d"} {
+				if strings.Contains(message, verb) != strings.Contains(english, verb) {
+					t.Errorf("have %q for %q in %q, wanted it to use %s as %q does", message, key, language, verb, english)
+				}
+			}
+		}
+
+		if len(messages) != len(explainCatalogs["en"]) {
+			t.Errorf("have %d messages in %q, wanted %d", len(messages), language, len(explainCatalogs["en"]))
+		}
+	}
+}
+
+// BenchmarkExplainIn benchmarks diceroller.ExplainIn.
+func BenchmarkExplainIn(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ExplainIn("fr", "4d6kh3+1")
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"slices"
+)
+
+// Below this p-value, a die's results are unlikely enough from a fair die to be suspicious: fair dice do it 1 time in 100.
+const suspiciousPValue = 0.01
+
+// FairnessReport is how well the dice in a roll history match fair dice, one die size at a time, as worked out by Analyze.
+type FairnessReport struct {
+	Dice []DieFairness // Each size of die rolled, fewest faces first.
+}
+
+// DieFairness is how well the results of one size of die match a fair die. The chi-square test needs around five rolls per face
+// before it means much, e.g. 100 rolls of a d20.
+type DieFairness struct {
+	Faces         int     // How many faces the die has.
+	Rolls         int     // How many times a die of this size was rolled, counting dropped dice.
+	Counts        []int   // How many times each face came up, lowest face first.
+	ChiSquare     float64 // Pearson's chi-square statistic: how far the counts are from even.
+	PValue        float64 // The chance of fair dice being at least this far from even, from 0 to 1.
+	Suspicious    bool    // Whether the PValue is under 0.01, which fair dice only manage 1 time in 100.
+	LongestStreak int     // The most times in a row the same face came up.
+	StreakFace    int     // The face of the longest streak, the first to reach it if there's a tie.
+}
+
+// dieTally is a DieFairness being counted up, with the streak the last result is part of.
+type dieTally struct {
+	DieFairness
+	streakFace int // The face of the current streak.
+	streak     int // How many times in a row it's come up.
+}
+
+/*
+ * Analyze checks the dice in a roll history against fair dice, with a chi-square test and the longest streak of the same face for
+ *   each size of die, so a GM can answer "are these dice cursed?", or a bot can check its random numbers. Dice are taken in the
+ *   order they were rolled, even if they were sorted, and dice of one face are skipped, as they can't be unfair.
+ * e.g. Analyze(history) // {[{6 600 [92 98 ...] 5.9 0.32 false 4 4} {20 200 [8 13 ...] 21.4 0.32 false 2 9}]}
+ */
+func Analyze(history []Record) (output FairnessReport) {
+	dice := map[int]*dieTally{}
+
+	for _, record := range history {
+		faces := record.Roll.Faces
+		if faces < 2 {
+			continue
+		}
+
+		die, ok := dice[faces]
+		if !ok {
+			die = &dieTally{DieFairness: DieFairness{Faces: faces, Counts: make([]int, faces)}}
+			dice[faces] = die
+		}
+
+		results := record.Roll.Results
+		if record.Roll.Unsorted != nil {
+			results = record.Roll.Unsorted
+		}
+
+		for _, result := range results {
+			// Anything off the die, e.g. from a post-processor, can't be compared with a fair one.
+			if result >= 1 && result <= faces {
+				die.add(result)
+			}
+		}
+	}
+
+	for _, die := range dice {
+		if die.Rolls > 0 {
+			output.Dice = append(output.Dice, die.fairness())
+		}
+	}
+
+	slices.SortFunc(output.Dice, func(a, b DieFairness) int { return a.Faces - b.Faces })
+
+	return
+}
+
+/*
+ * add counts one result, and keeps track of the streak it's part of.
+ */
+func (die *dieTally) add(result int) {
+	die.Counts[result-1]++
+	die.Rolls++
+
+	if die.streakFace == result {
+		die.streak++
+	} else {
+		die.streakFace, die.streak = result, 1
+	}
+
+	if die.streak > die.LongestStreak {
+		die.LongestStreak, die.StreakFace = die.streak, result
+	}
+}
+
+/*
+ * fairness works out the chi-square test for the counts so far.
+ */
+func (die *dieTally) fairness() DieFairness {
+	output := die.DieFairness
+	expected := float64(output.Rolls) / float64(output.Faces)
+
+	for _, count := range output.Counts {
+		diff := float64(count) - expected
+		output.ChiSquare += diff * diff / expected
+	}
+
+	output.PValue = chiSquarePValue(output.ChiSquare, output.Faces-1)
+	output.Suspicious = output.PValue < suspiciousPValue
+
+	return output
+}
+
+/*
+ * chiSquarePValue returns the chance of a chi-square statistic at least this big with the given degrees of freedom, which is the
+ *   regularised upper incomplete gamma function Q(k/2, x/2).
+ */
+func chiSquarePValue(chiSquare float64, freedom int) float64 {
+	if chiSquare <= 0 {
+		return 1
+	}
+
+	return upperGamma(float64(freedom)/2, chiSquare/2)
+}
+
+/*
+ * upperGamma returns the regularised upper incomplete gamma function Q(a, x), with a series for small x and a continued fraction
+ *   (Lentz's method) for large x, each of which converges quickly on its side.
+ */
+func upperGamma(a, x float64) float64 {
+	const (
+		epsilon    = 1e-14
+		iterations = 1000
+		tiny       = 1e-300
+	)
+
+	lnGammaA, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - lnGammaA)
+
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < iterations && math.Abs(term) > math.Abs(sum)*epsilon; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+		}
+
+		return max(0, 1-sum*prefix)
+	}
+
+	b := x + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+
+	for n := 1; n < iterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+
+	return prefix * h
+}
@@ -0,0 +1,99 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestChiSquarePValue calls diceroller.chiSquarePValue with the critical values from chi-square tables, checking for valid return values.
+func TestChiSquarePValue(t *testing.T) {
+	for _, test := range []struct {
+		chiSquare float64
+		freedom   int
+		want      float64
+	}{
+		{3.8415, 1, 0.05}, {2, 2, math.Exp(-1)}, {11.0705, 5, 0.05}, {15.0863, 5, 0.01}, {30.1435, 19, 0.05}, {0, 5, 1}, {1000, 5, 0},
+	} {
+		if output := chiSquarePValue(test.chiSquare, test.freedom); math.Abs(output-test.want) > 1e-5 {
+			t.Errorf("have %v, wanted %v for %v with %d degrees of freedom", output, test.want, test.chiSquare, test.freedom)
+		}
+	}
+}
+
+// TestAnalyze calls diceroller.Analyze with fair and loaded dice, checking the counts, tests and streaks.
+func TestAnalyze(t *testing.T) {
+	reseed()
+
+	var history []Record
+	for range 200 {
+		rolls, _ := RollDetails("1d20", "3d6s")
+		for _, roll := range rolls {
+			history = append(history, Record{Roll: roll})
+		}
+	}
+
+	output := Analyze(history)
+	if len(output.Dice) != 2 || output.Dice[0].Faces != 6 || output.Dice[1].Faces != 20 {
+		t.Fatalf("have %+v, wanted a d6 and a d20", output)
+	}
+
+	want := DieFairness{Faces: 6, Rolls: 600, Counts: []int{92, 98, 119, 90, 95, 106}, ChiSquare: 5.9, PValue: 0.3160713091850996,
+		LongestStreak: 4, StreakFace: 4}
+	if d6 := output.Dice[0]; !reflect.DeepEqual(d6.Counts, want.Counts) || d6.Rolls != want.Rolls || math.Abs(d6.ChiSquare-want.ChiSquare) > 1e-9 ||
+		math.Abs(d6.PValue-want.PValue) > 1e-9 || d6.Suspicious || d6.LongestStreak != want.LongestStreak || d6.StreakFace != want.StreakFace {
+		t.Errorf("have %+v, wanted %+v", d6, want)
+	}
+
+	loaded := []Record{
+		{Roll: DiceRoll{Faces: 6, Results: []int{6, 6, 6, 6, 6, 6, 6, 6}}},
+		{Roll: DiceRoll{Faces: 6, Results: []int{1, 2, 3, 6, 6, 6, 6}, Unsorted: []int{6, 6, 1, 6, 2, 3, 6}}},
+		{Roll: DiceRoll{Faces: 6, Results: []int{0, 7, 99}}},
+		{Roll: DiceRoll{Faces: 1, Results: []int{1, 1, 1}}},
+	}
+
+	output = Analyze(loaded)
+	if len(output.Dice) != 1 {
+		t.Fatalf("have %+v, wanted only the d6", output)
+	}
+
+	// The streak of 6s carries on into the second roll, as rolled rather than as sorted.
+	if d6 := output.Dice[0]; d6.Rolls != 15 || !reflect.DeepEqual(d6.Counts, []int{1, 1, 1, 0, 0, 12}) || !d6.Suspicious || d6.LongestStreak != 10 || d6.StreakFace != 6 {
+		t.Errorf("have %+v, wanted 15 suspicious rolls, with a streak of ten 6s", d6)
+	}
+
+	if output := Analyze(nil); output.Dice != nil {
+		t.Errorf("have %+v, wanted nothing", output)
+	}
+}
+
+// BenchmarkAnalyze benchmarks diceroller.Analyze.
+func BenchmarkAnalyze(b *testing.B) {
+	history := make([]Record, 100)
+	for i := range history {
+		roll, _ := RollDetails("4d6", "1d20")
+		history[i].Roll = roll[i%2]
+	}
+
+	for i := 0; i < b.N; i++ {
+		_ = Analyze(history)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Formatter displays DiceRolls nicely, as Prettify does, but with options for how. The zero Formatter displays rolls the same as
+// PrettifyOne, e.g. '1 + ~~2~~ + 3 (+1) = 5 (nat 1)'.
+type Formatter struct {
+	Separator      string                  // What goes between the dice. Empty means ' + '.
+	ShowRoll       bool                    // Start with the discovered roll, as PrettifyOneFull does, e.g. '4d4: '.
+	AlwaysTotal    bool                    // Show the total even for one die with no modifiers, e.g. '17 = 17'.
+	Sort           bool                    // Show the dice lowest first, rather than in the order they were rolled.
+	SortDescending bool                    // Show the dice highest first. It overrides Sort.
+	Critical       func(die string) string // Highlights each die which came up on its highest face, e.g. wrapping it in '**'. Nil means none.
+	Fumble         func(die string) string // Highlights each die which came up a 1. Nil means none.
+}
+
+/*
+ * Format displays one DiceRoll nicely, the Formatter's way. Dropped dice are struck through after any highlighting, and sorting
+ *   shows the dice in a different order without changing the DiceRoll.
+ * e.g. Formatter{Separator: ", ", ShowRoll: true, SortDescending: true}.Format(roll) // "4d6kh3: 6, 6, 4, ~~3~~ = 16"
+ */
+func (formatter Formatter) Format(input DiceRoll) (output string) {
+	var (
+		separator = formatter.Separator
+		totalsStr = make([]string, len(input.Results))
+		total     int
+	)
+
+	if separator == "" {
+		separator = " + "
+	}
+
+	if formatter.Sort || formatter.SortDescending {
+		input.Results, input.Dropped = slices.Clone(input.Results), slices.Clone(input.Dropped)
+		sortResults(&input, formatter.SortDescending)
+	}
+
+	if formatter.ShowRoll {
+		output += strings.ToLower(input.DiscoveredRoll) + ": "
+	}
+
+	for i, v := range input.Results {
+		totalsStr[i] = formatter.highlight(strconv.Itoa(v), v, input.Faces)
+
+		// Dropped dice are shown struck through, and don't count.
+		if slices.Contains(input.Dropped, i) {
+			totalsStr[i] = "~~" + totalsStr[i] + "~~"
+			continue
+		}
+
+		total += v
+	}
+
+	output += strings.Join(totalsStr, separator)
+
+	switch {
+	case input.DieModifier > 0:
+		output += fmt.Sprintf(" (+%d each)", input.DieModifier)
+	case input.DieModifier < 0:
+		output += fmt.Sprintf(" (-%.0f each)", math.Abs(float64(input.DieModifier)))
+	}
+
+	// Each die which counts gets the per-die modifier.
+	total += input.DieModifier * (len(input.Results) - len(input.Dropped))
+
+	switch {
+	case input.Modifier > 0:
+		output += fmt.Sprintf(" (+%d)", input.Modifier)
+	case input.Modifier < 0:
+		output += fmt.Sprintf(" (-%.0f)", math.Abs(float64(input.Modifier)))
+	}
+
+	// Rolling 1Dn with no modifier looks weird when output as e.g. `1d6: 1 = 1.` so we handle that here, unless told not to.
+	if formatter.AlwaysTotal || len(totalsStr) > 1 || input.Modifier != 0 || input.DieModifier != 0 {
+		output += fmt.Sprintf(" = %d", total+input.Modifier)
+	}
+
+	// Call out natural 20s and natural 1s (or whatever the critical dice are).
+	var naturals []string
+	if input.NaturalMax > 0 {
+		naturals = append(naturals, thresholdStr(input, input.Faces, input.NaturalMax, "crit"))
+	}
+
+	if input.NaturalMin > 0 {
+		naturals = append(naturals, thresholdStr(input, 1, input.NaturalMin, "fumble"))
+	}
+
+	if len(naturals) > 0 {
+		output += " (" + strings.Join(naturals, ", ") + ")"
+	}
+
+	return
+}
+
+/*
+ * highlight applies the Formatter's Critical or Fumble to a die which came up on its highest face or a 1. Rolls without faces,
+ *   such as groups of rolls, aren't highlighted.
+ */
+func (formatter Formatter) highlight(die string, result, faces int) string {
+	switch {
+	case faces == 0:
+		return die
+	case result == faces && formatter.Critical != nil:
+		return formatter.Critical(die)
+	case result == 1 && formatter.Fumble != nil:
+		return formatter.Fumble(die)
+	}
+
+	return die
+}
+
+/*
+ * thresholdStr describes how many dice came up naturally on a face, as naturalStr does, unless some of them came up on other
+ *   faces, as Roll20's 'cs>19' and 'cf<2' count, when they're called what they are instead.
+ * e.g. "nat 20", or "crit x2" for a 19 and a 20
+ */
+func thresholdStr(input DiceRoll, face, count int, name string) string {
+	var natural int
+	for _, v := range keptResults(input) {
+		if v == face {
+			natural++
+		}
+	}
+
+	if natural == count {
+		return naturalStr(face, count)
+	}
+
+	if count == 1 {
+		return name
+	}
+
+	return fmt.Sprintf("%s x%d", name, count)
+}
@@ -0,0 +1,81 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+var formatterTests = map[string]struct {
+	formatter Formatter
+	want      []string
+}{
+	"zero":      {Formatter{}, []string{"6 + ~~3~~ + 6 + 4 = 16", "20 (nat 20)", "1 + 3 + 4 (+2) = 10"}},
+	"full":      {Formatter{ShowRoll: true}, []string{"4d6kh3: 6 + ~~3~~ + 6 + 4 = 16", "1d20: 20 (nat 20)", "3d6+2: 1 + 3 + 4 (+2) = 10"}},
+	"separator": {Formatter{Separator: ", ", ShowRoll: true, SortDescending: true}, []string{"4d6kh3: 6, 6, 4, ~~3~~ = 16", "1d20: 20 (nat 20)", "3d6+2: 4, 3, 1 (+2) = 10"}},
+	"sorted":    {Formatter{AlwaysTotal: true, Sort: true}, []string{"~~3~~ + 4 + 6 + 6 = 16", "20 = 20 (nat 20)", "1 + 3 + 4 (+2) = 10"}},
+	"criticals": {Formatter{Critical: func(die string) string { return "**" + die + "**" }, Fumble: func(die string) string { return "_" + die + "_" }},
+		[]string{"**6** + ~~3~~ + **6** + 4 = 16", "**20** (nat 20)", "_1_ + 3 + 4 (+2) = 10"}},
+}
+
+// TestFormatter calls diceroller.Formatter.Format with each option, checking for valid return values, that the zero Formatter
+// displays rolls the same as PrettifyOne, and that sorting leaves the DiceRoll alone.
+func TestFormatter(t *testing.T) {
+	rolls, _ := NewRoller(WithSeed(42, 1024)).RollDetails("4d6kh3", "1d20", "3d6+2")
+	original := slices.Clone(rolls[0].Results)
+
+	for name, test := range formatterTests {
+		output := make([]string, len(rolls))
+		for i, roll := range rolls {
+			output[i] = test.formatter.Format(roll)
+		}
+
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %q, wanted %q from %s", output, test.want, name)
+		}
+	}
+
+	if output := Prettify(rolls); !reflect.DeepEqual(output, formatterTests["zero"].want) {
+		t.Errorf("have %q from Prettify, wanted the zero Formatter's %q", output, formatterTests["zero"].want)
+	}
+
+	if !reflect.DeepEqual(rolls[0].Results, original) || !reflect.DeepEqual(rolls[0].Dropped, []int{1}) {
+		t.Errorf("have %v, dropped %v, wanted the roll unsorted", rolls[0].Results, rolls[0].Dropped)
+	}
+
+	// Rolls without faces, such as groups, aren't highlighted.
+	group := DiceRoll{Results: []int{1, 12}, Total: 13}
+	if output := formatterTests["criticals"].formatter.Format(group); output != "1 + 12 = 13" {
+		t.Errorf("have %q, wanted nothing highlighted", output)
+	}
+}
+
+// BenchmarkFormatter benchmarks diceroller.Formatter.Format.
+func BenchmarkFormatter(b *testing.B) {
+	reseed()
+
+	roll, _ := RollDetails("4d6kh3")
+	formatter := Formatter{Separator: ", ", ShowRoll: true, SortDescending: true, Critical: func(die string) string { return "**" + die + "**" }}
+
+	for i := 0; i < b.N; i++ {
+		_ = formatter.Format(roll[0])
+	}
+}
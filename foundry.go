@@ -0,0 +1,293 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// The regex used to check a Foundry VTT formula, once its data has been filled in, e.g. d20, 2d20kh, 4d6dl1, 1d20r1, 8d6x,
+	//   1d20min10+5. It has to be one roll, with options in any order, and any number of modifiers.
+	foundryRegex = regexp.MustCompile(
+		`^(?P<rolls>\d{1,5})?[dD](?P<faces>\d{1,5})` + // The dice, e.g. 2d20 or d20.
+			`(?P<options>(?i:min\d{1,5}|max\d{1,5}|[kd][hl]?\d{0,5}|rr?(?:<=|>=|<|>|=)?\d{0,5}|xo?(?:<=|>=|<|>|=)?\d{0,5})*)` + // Keeping, dropping, rerolling, exploding, minimums and maximums.
+			`(?P<modifiers>(?:[\+-]\d{1,9})*)$`, // Any number of modifiers, e.g. +3-1.
+	)
+
+	// The regex used to split a Foundry roll's options up, e.g. 'khr1' into 'kh' and 'r1'.
+	foundryOptionRegex = regexp.MustCompile(`(?i)(min|max|[kd][hl]?|rr?|xo?)(<=|>=|<|>|=)?(\d{0,5})`)
+
+	// A data path in a Foundry formula, e.g. '@abilities.str.mod', optionally in brackets, as for a number of dice, e.g. '(@level)d6'.
+	foundryDataRegex = regexp.MustCompile(`([+-]?)\(?@([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z0-9_]+)*)\)?`)
+)
+
+/*
+ * WithFoundry makes a Roller understand Foundry VTT's dice formulas instead of its own notation, so formulas from Foundry modules can
+ *   be rolled as they are: data paths ('@abilities.str.mod', or '(@level)d6' for a number of dice) looked up in data, nested maps as
+ *   Foundry's roll data is, optional numbers of dice ('d20'), keeping and dropping ('kh', 'kl2', 'dl', 'dh1'), rerolling once ('r1',
+ *   'r<3'), exploding dice ('x', 'x>=5'), minimums and maximums for each die ('min10', 'max5'), and any number of modifiers. A
+ *   formula has to be one roll; anything else, such as a second roll, is ErrNotJustRoll, and notation which isn't supported, such as
+ *   rerolling more than once ('rr'), is an error, rather than being rolled wrongly. A path not in data is ErrVariableNotFound.
+ * e.g. NewRoller(WithFoundry(map[string]any{"abilities": map[string]any{"str": map[string]any{"mod": 3}}})).RollOne("1d20 + @abilities.str.mod") // 17
+ */
+func WithFoundry(data map[string]any) RollerOption {
+	return func(roller *Roller) {
+		roller.notation = &notation{roll: func(roller *Roller, input string) (DiceRoll, error) {
+			spec, err := roller.parseFoundry(input, data)
+			if err != nil {
+				return DiceRoll{}, err
+			}
+
+			return roller.rollParsed(spec)
+		}, dice: func(roller *Roller, input string) (int, error) {
+			spec, err := roller.parseFoundry(input, data)
+
+			return spec.rolls, err
+		}}
+	}
+}
+
+/*
+ * parseFoundry fills in a Foundry formula's data and breaks it down into a rollSpec, taking the Roller's other letters for the 'd' in
+ *   the filled-in formula, checking it can be rolled.
+ */
+func (roller *Roller) parseFoundry(input string, data map[string]any) (rollSpec, error) {
+	formula, err := foundryFormula(input, data)
+	if err != nil {
+		return rollSpec{}, err
+	}
+
+	return roller.parseWith(formula, parseFoundryFormula)
+}
+
+/*
+ * foundryFormula fills in a Foundry formula's data.
+ */
+func foundryFormula(input string, data map[string]any) (formula string, err error) {
+	input = inputReplacer.Replace(input)
+
+	formula = foundryDataRegex.ReplaceAllStringFunc(input, func(match string) string {
+		result := foundryDataRegex.FindStringSubmatch(match)
+
+		value, valueErr := foundryValue(data, result[2])
+		if valueErr != nil {
+			if err == nil {
+				err = valueErr
+			}
+
+			return match
+		}
+
+		if result[1] == "" {
+			return strconv.Itoa(value)
+		}
+
+		if result[1] == "-" {
+			value = -value
+		}
+
+		return fmt.Sprintf("%+d", value)
+	})
+
+	return
+}
+
+/*
+ * parseFoundryFormula breaks a Foundry formula, with its data filled in, down into a rollSpec, checking it can be rolled.
+ */
+func parseFoundryFormula(formula string) (spec rollSpec, err error) {
+	result := foundryRegex.FindStringSubmatch(formula)
+	if result == nil {
+		if roll20Regex.MatchString(formula) {
+			err = fmt.Errorf("%q is %w", formula, ErrNotJustRoll)
+		} else {
+			err = fmt.Errorf("%w in %q", ErrNoRollFound, formula)
+		}
+
+		return
+	}
+
+	// The number of dice is optional, and the modifiers are added up into one.
+	spec.rolls = 1
+	if rolls := result[foundryRegex.SubexpIndex("rolls")]; len(rolls) > 0 {
+		if spec.rolls, err = strconv.Atoi(rolls); err != nil {
+			return
+		}
+	}
+
+	if spec.die.Faces, err = strconv.Atoi(result[foundryRegex.SubexpIndex("faces")]); err != nil {
+		return
+	}
+
+	options := result[foundryRegex.SubexpIndex("options")]
+	for _, option := range foundryOptionRegex.FindAllStringSubmatch(options, -1) {
+		if spec, err = parseFoundryOption(spec, formula, strings.ToLower(option[1]), option[2], option[3]); err != nil {
+			return
+		}
+	}
+
+	if modifiers := result[foundryRegex.SubexpIndex("modifiers")]; len(modifiers) > 0 {
+		if spec.modifier, err = sumModifiers(modifiers); err != nil {
+			return
+		}
+	}
+
+	spec.discovered = formula[:len(formula)-len(result[foundryRegex.SubexpIndex("modifiers")])]
+	if spec.modifier != 0 {
+		spec.discovered += fmt.Sprintf("%+d", spec.modifier)
+	}
+
+	err = spec.validate()
+
+	return
+}
+
+/*
+ * parseFoundryOption fills in the part of a rollSpec one Foundry option asks for, e.g. 'kh' or 'r<3'. Unlike Roll20, Foundry's '<'
+ *   and '>' don't include the number, so 'r<3' rerolls 1s and 2s, and a number on its own means just that face.
+ */
+func parseFoundryOption(spec rollSpec, formula, option, compare, number string) (rollSpec, error) {
+	n := 0
+	if number != "" {
+		var err error
+		if n, err = strconv.Atoi(number); err != nil {
+			return spec, err
+		}
+	}
+
+	switch option {
+	case "min":
+		spec.minimum = n
+	case "max":
+		spec.maximum = n
+	case "rr":
+		return spec, fmt.Errorf("%q rerolls more than once, but only rerolling once, e.g. 'r<3', is supported", formula)
+	case "xo":
+		return spec, fmt.Errorf("%q explodes once, but only exploding every time, e.g. 'x', is supported", formula)
+	case "r":
+		// Rerolling once on a number, or under one: 'r' alone rerolls 1s.
+		switch {
+		case number == "" && compare == "":
+			spec.reroll = 1
+		case compare == "<":
+			spec.reroll = n - 1
+		case compare == "<=" || ((compare == "" || compare == "=") && n == 1):
+			spec.reroll = n
+		default:
+			return spec, fmt.Errorf("%q can only reroll dice under a number, e.g. 'r<3'", formula)
+		}
+	case "x":
+		// Exploding on a number, or over one: 'x' alone explodes on the highest face.
+		switch {
+		case number == "" && compare == "":
+			spec.explode = spec.die.Faces
+		case compare == ">":
+			spec.explode = n + 1
+		case compare == ">=" || ((compare == "" || compare == "=") && n == spec.die.Faces):
+			spec.explode = n
+		default:
+			return spec, fmt.Errorf("%q can only explode dice over a number, e.g. 'x>=5'", formula)
+		}
+
+		if spec.explode <= 1 {
+			return spec, fmt.Errorf("%q would explode on every roll", formula)
+		}
+	default:
+		if spec.keep != "" {
+			return spec, fmt.Errorf("%q can only keep or drop dice once", formula)
+		}
+
+		// Keeping or dropping dice without a number keeps or drops one.
+		if number == "" {
+			n = 1
+		}
+
+		spec.keep, spec.keepCount = roll20Keep(option, n, spec.rolls)
+	}
+
+	return spec, nil
+}
+
+/*
+ * foundryValue looks up a data path such as 'abilities.str.mod' in Foundry roll data, nested maps keyed by each part of the path. The
+ *   value has to be a whole number, or a string of one.
+ */
+func foundryValue(data map[string]any, path string) (int, error) {
+	var value any = data
+
+	for _, part := range strings.Split(path, ".") {
+		values, ok := value.(map[string]any)
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrVariableNotFound, "@"+path)
+		}
+
+		if value, ok = values[part]; !ok {
+			return 0, fmt.Errorf("%w: %q", ErrVariableNotFound, "@"+path)
+		}
+	}
+
+	switch value := value.(type) {
+	case int:
+		return value, nil
+	case int64:
+		if value >= math.MinInt && value <= math.MaxInt {
+			return int(value), nil
+		}
+	case float64:
+		if value == math.Trunc(value) && value >= math.MinInt32 && value <= math.MaxInt32 {
+			return int(value), nil
+		}
+	case json.Number:
+		if n, err := strconv.Atoi(value.String()); err == nil {
+			return n, nil
+		}
+	case string:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%q is %v, which isn't a whole number", "@"+path, value)
+}
+
+/*
+ * clampResults raises dice under minimum to it, and lowers dice over maximum to it, as Foundry's 'min' and 'max' do, keeping the
+ *   total in step. Either can be 0 to leave it alone.
+ */
+func clampResults(output *DiceRoll, minimum, maximum int) {
+	for i, v := range output.Results {
+		clamped := v
+		if minimum > 0 {
+			clamped = max(clamped, minimum)
+		}
+
+		if maximum > 0 {
+			clamped = min(clamped, maximum)
+		}
+
+		output.Results[i] = clamped
+		output.Total += clamped - v
+	}
+}
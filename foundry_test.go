@@ -0,0 +1,117 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type foundryTest struct {
+	input          string
+	wantDiscovered string
+	wantResults    []int
+	wantDropped    []int
+	wantTotal      int
+}
+
+// Foundry roll data, as nested maps, with numbers as Foundry's JSON would have them.
+var foundryData = map[string]any{
+	"abilities": map[string]any{
+		"str": map[string]any{"mod": 3},
+		"dex": map[string]any{"mod": -1},
+	},
+	"prof":  2.0,
+	"level": "5",
+	"bonus": json.Number("4"),
+	"half":  1.5,
+}
+
+// With WithSeed(42, 1024), d6s come up 6, 3, 6, 4, 6, 1, 3, 4 and d20s come up 19, 10, 19.
+var foundryTests = []foundryTest{
+	{"1d20 + @abilities.str.mod + @prof", "1d20+5", []int{19}, nil, 24},
+	{"1d20+@abilities.dex.mod", "1d20-1", []int{19}, nil, 18},
+	{"(@level)d6", "5d6", []int{6, 3, 6, 4, 6}, nil, 25},
+	{"d20-@bonus", "d20-4", []int{19}, nil, 15},
+	{"4D6KH3+2-1", "4D6KH3+1", []int{6, 3, 6, 4}, []int{1}, 17},
+	{"1d20+0", "1d20", []int{19}, nil, 19},
+	{"2d20kh", "2d20kh", []int{19, 10}, []int{1}, 19},
+	{"2d20kl+1", "2d20kl+1", []int{19, 10}, []int{0}, 11},
+	{"4d6dl", "4d6dl", []int{6, 3, 6, 4}, []int{1}, 16},
+	{"4d6dh1", "4d6dh1", []int{6, 3, 6, 4}, []int{2}, 13},
+	{"2d6r<3", "2d6r<3", []int{6, 3}, nil, 9},
+	{"8d6x", "8d6x", []int{6, 2, 3, 6, 2, 4, 6, 1, 1, 3, 4}, nil, 38},
+	{"4d6x>=5", "4d6x>=5", []int{6, 6, 1, 3, 6, 3, 4}, nil, 29},
+	{"4d6max3", "4d6max3", []int{3, 3, 3, 3}, nil, 12},
+	{"1d20min20", "1d20min20", []int{20}, nil, 20},
+}
+
+// TestFoundry rolls Foundry VTT formulas with a diceroller.Roller made with WithFoundry, checking for valid return values.
+func TestFoundry(t *testing.T) {
+	for _, test := range foundryTests {
+		output, err := NewRoller(WithSeed(42, 1024), WithFoundry(foundryData)).RollDetails(test.input)
+		if err != nil {
+			t.Fatalf("have err %v for %q", err, test.input)
+		}
+
+		roll := output[0]
+		if roll.DiscoveredRoll != test.wantDiscovered || !reflect.DeepEqual(roll.Results, test.wantResults) ||
+			!reflect.DeepEqual(roll.Dropped, test.wantDropped) || roll.Total != test.wantTotal {
+			t.Errorf("have %+v, wanted %q %v dropping %v totalling %d", roll, test.wantDiscovered, test.wantResults, test.wantDropped,
+				test.wantTotal)
+		}
+	}
+}
+
+// TestFoundryErrors rolls bad and unsupported Foundry VTT formulas, checking for errors.
+func TestFoundryErrors(t *testing.T) {
+	roller := NewRoller(WithFoundry(foundryData), WithMaxDice(10))
+
+	for input, want := range map[string]error{
+		"nothing":       ErrNoRollFound,
+		"0d6":           ErrZeroDice,
+		"2d0":           ErrZeroFaces,
+		"11d6":          ErrLimitExceeded,
+		"1d8+1d6":       ErrNotJustRoll,
+		"1d20[fire]":    ErrNotJustRoll,
+		"1d20+@missing": ErrVariableNotFound,
+		"1d20+@prof.x":  ErrVariableNotFound,
+	} {
+		if _, err := roller.RollOne(input); !errors.Is(err, want) {
+			t.Errorf("have err %v for %q, wanted %v", err, input, want)
+		}
+	}
+
+	for _, input := range []string{"1d20+@abilities.str", "1d20+@half", "2d6rr1", "2d6xo", "2d6r3", "2d6r>3", "2d6x3", "2d6x<3", "2d6x>=1", "4d6khdl"} {
+		if _, err := roller.RollOne(input); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+}
+
+// BenchmarkFoundry benchmarks rolling Foundry VTT formulas.
+func BenchmarkFoundry(b *testing.B) {
+	roller := NewRoller(WithFoundry(foundryData))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollOne("2d20kh + @abilities.str.mod + @prof")
+	}
+}
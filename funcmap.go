@@ -0,0 +1,51 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"html"
+	"html/template"
+)
+
+/*
+ * FuncMap returns functions for text/template and html/template which roll with the package's default Roller, so a page can have
+ *   '{{roll "2d6+3"}}' in it: roll gives the total, rollDetails the DiceRoll, prettify and prettifyFull the DiceRoll displayed
+ *   nicely, and prettifyHTML the same as PrettifyHTMLFull, as HTML. A bad roll stops the template with an error. The map can be
+ *   given to either package's Funcs, and html/template escapes everything but prettifyHTML, which escapes the roll itself.
+ * e.g. template.New("page").Funcs(FuncMap()).Parse(`You hit for {{roll "2d6+3"}}, {{rollDetails "4d6kh3" | prettifyHTML}}`)
+ */
+func FuncMap() map[string]any {
+	return defaultRoller.FuncMap()
+}
+
+/*
+ * FuncMap is like the package's FuncMap, but rolls with the Roller, e.g. one made with WithMaxTotalDice for templates users write.
+ * e.g. template.New("page").Funcs(roller.FuncMap())
+ */
+func (roller *Roller) FuncMap() map[string]any {
+	return map[string]any{
+		"roll":         roller.RollOne,
+		"rollDetails":  roller.roll,
+		"prettify":     PrettifyOne,
+		"prettifyFull": PrettifyOneFull,
+		"prettifyHTML": func(roll DiceRoll) template.HTML {
+			return template.HTML(addHTML([]string{html.EscapeString(PrettifyOneFull(roll))})[0])
+		},
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// TestFuncMap uses diceroller.FuncMap in text/template and html/template, checking for valid output, that rolls are escaped in
+// HTML, and that a bad roll is an error.
+func TestFuncMap(t *testing.T) {
+	const page = `{{roll "2d6+3"}} {{rollDetails "4d6kh3" | prettify}} {{rollDetails "1d20" | prettifyFull}}`
+
+	var output strings.Builder
+	text := template.Must(template.New("text").Funcs(NewRoller(WithSeed(42, 1024)).FuncMap()).Parse(page))
+	if err := text.Execute(&output, nil); output.String() != "12 6 + 4 + 6 + ~~1~~ = 16 1d20: 8" || err != nil {
+		t.Errorf("have %q, %v from text/template", output.String(), err)
+	}
+
+	output.Reset()
+	html := htmltemplate.Must(htmltemplate.New("html").Funcs(NewRoller(WithSeed(42, 1024)).FuncMap()).Parse(
+		`<p>{{roll "2d6+3"}} {{rollDetails "4d6kh3" | prettifyHTML}} {{.}}</p>`))
+	want := "<p>12 <strong>4d6kh3:</strong> <em>6 + 4 + 6 + ~~1~~ = 16</em> &lt;b&gt;</p>"
+	if err := html.Execute(&output, "<b>"); output.String() != want || err != nil {
+		t.Errorf("have %q, %v, wanted %q from html/template", output.String(), err, want)
+	}
+
+	output.Reset()
+	html = htmltemplate.Must(htmltemplate.New("html").Funcs(FuncMap()).Parse(`{{prettifyHTML .}}`))
+	if err := html.Execute(&output, DiceRoll{DiscoveredRoll: "<b>", Results: []int{2}, Total: 2}); strings.Contains(output.String(), "<b>") || err != nil {
+		t.Errorf("have %q, %v, wanted the roll escaped", output.String(), err)
+	}
+
+	text = template.Must(template.New("text").Funcs(FuncMap()).Parse(`{{roll .}}`))
+	if err := text.Execute(&output, "bad"); err == nil || !strings.Contains(err.Error(), ErrNoRollFound.Error()) {
+		t.Errorf("have err %v, wanted %v", err, ErrNoRollFound)
+	}
+}
+
+// BenchmarkFuncMap benchmarks diceroller.FuncMap in text/template.
+func BenchmarkFuncMap(b *testing.B) {
+	reseed()
+
+	text := template.Must(template.New("text").Funcs(FuncMap()).Parse(`{{rollDetails "4d6kh3" | prettifyFull}}`))
+
+	for i := 0; i < b.N; i++ {
+		_ = text.Execute(&strings.Builder{}, nil)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Run 'go test -run TestGolden -update' to rewrite the golden files after an intended formatting change. It also records the
+// snapshot in testdata/snapshot.json again.
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden, and the snapshot")
+
+// A canonical set of rolls, covering every part of the notation, for the formatters to render.
+var goldenRolls = []DiceRoll{
+	{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Results: []int{4}, Total: 4, NaturalTotal: 4},
+	{DiscoveredRoll: "3D6+2", Faces: 6, Rolls: 3, Modifier: 2, Results: []int{6, 2, 5}, Total: 15, NaturalTotal: 13},
+	{DiscoveredRoll: "1d20-1", Faces: 20, Rolls: 1, Modifier: -1, Results: []int{1}, Total: 0, NaturalTotal: 1, NaturalMin: 1},
+	{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Results: []int{3, 1, 6, 4}, Total: 13, NaturalTotal: 13, Dropped: []int{1}},
+	{DiscoveredRoll: "5d8km3+1", Faces: 8, Rolls: 5, Modifier: 1, Results: []int{8, 2, 5, 7, 1}, Total: 15, NaturalTotal: 14, Dropped: []int{4, 0}},
+	{DiscoveredRoll: "d20adv+5", Faces: 20, Rolls: 2, Modifier: 5, Results: []int{20, 11}, Total: 25, NaturalTotal: 20, Dropped: []int{1}, NaturalMax: 1},
+	{DiscoveredRoll: "2d20dis", Faces: 20, Rolls: 4, Results: []int{1, 1, 17, 20}, Total: 2, NaturalTotal: 2, Dropped: []int{2, 3}, NaturalMin: 2},
+	{DiscoveredRoll: "4d6s++1", Faces: 6, Rolls: 4, Results: []int{1, 3, 3, 6}, Total: 17, NaturalTotal: 13, Unsorted: []int{3, 6, 1, 3}, DieModifier: 1},
+	{DiscoveredRoll: "3d8sd--1-2", Faces: 8, Rolls: 3, Modifier: -2, Results: []int{7, 4, 2}, Total: 8, NaturalTotal: 13, Unsorted: []int{4, 2, 7}, DieModifier: -1},
+	{DiscoveredRoll: "6d10", Faces: 10, Rolls: 6, Results: []int{7, 3, 7, 3, 7, 9}, Total: 36, NaturalTotal: 36, Sets: []MatchSet{{3, 7}, {2, 3}}},
+}
+
+// The formatters, by name. Each one gets a golden file of its rendering of goldenRolls.
+var goldenFormatters = map[string]func([]DiceRoll) []string{
+	"plain":     Prettify,
+	"full":      PrettifyFull,
+	"html":      PrettifyHTML,
+	"html-full": PrettifyHTMLFull,
+	"sets": func(input []DiceRoll) (output []string) {
+		for _, dr := range input {
+			if len(dr.Sets) > 0 {
+				output = append(output, dr.DiscoveredRoll+": "+PrettifySets(dr.Sets))
+			}
+		}
+
+		return
+	},
+}
+
+// TestGolden renders the canonical rolls with every formatter, checking each matches its golden file in testdata/golden.
+func TestGolden(t *testing.T) {
+	for name, formatter := range goldenFormatters {
+		t.Run(name, func(t *testing.T) {
+			output := strings.Join(formatter(goldenRolls), "\n") + "\n"
+			path := filepath.Join("testdata", "golden", name+".golden")
+
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("have err %v, wanted the golden file (run with -update to create it)", err)
+			}
+
+			if output != string(want) {
+				t.Errorf("have:\n%s\nwanted:\n%s", output, want)
+			}
+		})
+	}
+}
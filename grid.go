@@ -0,0 +1,133 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SuccessGrid holds the chance of a roll plus each of a range of bonuses meeting or beating each of a range of DCs.
+type SuccessGrid struct {
+	Roll    string      // The 'nDn+n'-format string we've discovered and are working out the chances of.
+	Bonuses []int       // The bonuses added to the roll, one per row.
+	DCs     []int       // The DCs the roll is made against, one per column.
+	Chances [][]float64 // The chance of success, from 0 to 1, for each bonus (row) and DC (column).
+}
+
+/*
+ * NewSuccessGrid takes in one string in the correct 'nDn+n' format, and the range of bonuses and DCs to cover, and works out the chance
+ *   of the roll plus each bonus meeting or beating each DC. The string has to be just the roll, or it's ErrNotJustRoll.
+ * e.g. NewSuccessGrid("2d20kh1", 0, 5, 10, 20)
+ */
+func NewSuccessGrid(input string, minBonus, maxBonus, minDC, maxDC int) (output SuccessGrid, err error) {
+	if minBonus > maxBonus || minDC > maxDC {
+		err = fmt.Errorf("bonus range %d to %d or DC range %d to %d is back to front", minBonus, maxBonus, minDC, maxDC)
+		return
+	}
+
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return
+	}
+
+	dist, err := spec.distribution()
+	if err != nil {
+		return
+	}
+
+	output.Roll = spec.discovered
+
+	for dc := minDC; dc <= maxDC; dc++ {
+		output.DCs = append(output.DCs, dc)
+	}
+
+	for bonus := minBonus; bonus <= maxBonus; bonus++ {
+		row := make([]float64, len(output.DCs))
+		for i, dc := range output.DCs {
+			row[i] = dist.atLeast(dc - bonus)
+		}
+
+		output.Bonuses = append(output.Bonuses, bonus)
+		output.Chances = append(output.Chances, row)
+	}
+
+	return
+}
+
+/*
+ * CSV returns the grid as comma-separated values, with the DCs across the top, the bonuses down the side, and chances from 0 to 1.
+ * e.g. "bonus,10,11\n0,0.5500,0.5000\n1,0.6000,0.5500\n"
+ */
+func (grid SuccessGrid) CSV() string {
+	var (
+		builder strings.Builder
+		writer  = csv.NewWriter(&builder)
+		record  = make([]string, len(grid.DCs)+1)
+	)
+
+	record[0] = "bonus"
+	for i, dc := range grid.DCs {
+		record[i+1] = strconv.Itoa(dc)
+	}
+
+	// Writing to a strings.Builder can't fail, so the errors are safe to ignore.
+	_ = writer.Write(record)
+
+	for row, bonus := range grid.Bonuses {
+		record[0] = strconv.Itoa(bonus)
+		for i, chance := range grid.Chances[row] {
+			record[i+1] = strconv.FormatFloat(chance, 'f', 4, 64)
+		}
+
+		_ = writer.Write(record)
+	}
+
+	writer.Flush()
+
+	return builder.String()
+}
+
+/*
+ * Table returns the grid as a plain-text table, with the DCs across the top, the bonuses down the side, and chances as percentages.
+ * e.g. "  DC   10   11\n  +0  55%  50%\n  +1  60%  55%\n"
+ */
+func (grid SuccessGrid) Table() string {
+	var builder strings.Builder
+
+	builder.WriteString("  DC")
+	for _, dc := range grid.DCs {
+		fmt.Fprintf(&builder, " %4d", dc)
+	}
+
+	builder.WriteString("\n")
+
+	for row, bonus := range grid.Bonuses {
+		fmt.Fprintf(&builder, "%+4d", bonus)
+		for _, chance := range grid.Chances[row] {
+			fmt.Fprintf(&builder, " %3.0f%%", chance*100)
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,105 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type successGridTest struct {
+	got                              string
+	minBonus, maxBonus, minDC, maxDC int
+	want                             SuccessGrid
+	wantCSV, wantTable               string
+}
+
+var successGridTests = []successGridTest{
+	{
+		"1d20", 0, 1, 10, 11,
+		SuccessGrid{"1d20", []int{0, 1}, []int{10, 11}, [][]float64{{0.55, 0.5}, {0.6, 0.55}}},
+		"bonus,10,11\n0,0.5500,0.5000\n1,0.6000,0.5500\n",
+		"  DC   10   11\n  +0  55%  50%\n  +1  60%  55%\n",
+	},
+	{
+		"1d4", -1, -1, 1, 5,
+		SuccessGrid{"1d4", []int{-1}, []int{1, 2, 3, 4, 5}, [][]float64{{0.75, 0.5, 0.25, 0, 0}}},
+		"bonus,1,2,3,4,5\n-1,0.7500,0.5000,0.2500,0.0000,0.0000\n",
+		"  DC    1    2    3    4    5\n  -1  75%  50%  25%   0%   0%\n",
+	},
+}
+
+// TestNewSuccessGrid calls diceroller.NewSuccessGrid and its CSV and Table methods, checking for valid return values.
+func TestNewSuccessGrid(t *testing.T) {
+	for _, test := range successGridTests {
+		output, err := NewSuccessGrid(test.got, test.minBonus, test.maxBonus, test.minDC, test.maxDC)
+
+		if output.Roll != test.want.Roll || !reflect.DeepEqual(output.Bonuses, test.want.Bonuses) || !reflect.DeepEqual(output.DCs, test.want.DCs) || !chancesEqual(output.Chances, test.want.Chances) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+
+		if csv := output.CSV(); csv != test.wantCSV {
+			t.Errorf("have %q, wanted %q", csv, test.wantCSV)
+		}
+
+		if table := output.Table(); table != test.wantTable {
+			t.Errorf("have %q, wanted %q", table, test.wantTable)
+		}
+	}
+
+	if _, err := NewSuccessGrid("1d20", 5, 0, 10, 20); err == nil {
+		t.Errorf("have nil error for a back to front range, wanted an error")
+	}
+
+	for _, input := range []string{"1d20+5 banana", "roll 1d4", "2d6 and 1d20"} {
+		if _, err := NewSuccessGrid(input, 0, 1, 10, 11); !errors.Is(err, ErrNotJustRoll) {
+			t.Errorf("have err %v for %q, wanted %v", err, input, ErrNotJustRoll)
+		}
+	}
+}
+
+// chancesEqual reports whether two grids of chances match, allowing for floating point rounding.
+func chancesEqual(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for row := range a {
+		if len(a[row]) != len(b[row]) {
+			return false
+		}
+
+		for i := range a[row] {
+			if math.Abs(a[row][i]-b[row][i]) > 1e-9 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// BenchmarkNewSuccessGrid benchmarks diceroller.NewSuccessGrid.
+func BenchmarkNewSuccessGrid(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = NewSuccessGrid("2d20kh1", 0, 10, 5, 25)
+	}
+}
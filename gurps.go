@@ -0,0 +1,101 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// GURPSOutcome is how a GURPS success roll turned out.
+type GURPSOutcome int
+
+const (
+	GURPSFailure         GURPSOutcome = iota // Rolled over the skill.
+	GURPSSuccess                             // Rolled the skill or under.
+	GURPSCriticalSuccess                     // Rolled 3 or 4, 5 on skill 15+, or 6 on skill 16+.
+	GURPSCriticalFailure                     // Rolled 18, 17 on skill 15 or under, or 10 or more over the skill.
+)
+
+/*
+ * String returns the outcome in words.
+ * e.g. "critical success"
+ */
+func (outcome GURPSOutcome) String() string {
+	switch outcome {
+	case GURPSSuccess:
+		return "success"
+	case GURPSCriticalSuccess:
+		return "critical success"
+	case GURPSCriticalFailure:
+		return "critical failure"
+	default:
+		return "failure"
+	}
+}
+
+// GURPSRoll holds the details of a GURPS success roll.
+type GURPSRoll struct {
+	Roll    DiceRoll     // The 3d6 roll.
+	Skill   int          // The effective skill level rolled against.
+	Outcome GURPSOutcome // How it turned out.
+	Margin  int          // The margin of success (skill minus the roll), negative being the margin of failure.
+}
+
+/*
+ * RollGURPS rolls 3d6 against an effective skill level and classifies the outcome per the GURPS rules: rolling the skill or under succeeds,
+ *   3 and 4 always succeed (critically), and 17 and 18 always fail.
+ * e.g. RollGURPS(12) // {{3d6 6 3 0 [2 4 3] 9 ...} 12 success 3}
+ */
+func RollGURPS(skill int) (output GURPSRoll, err error) {
+	return defaultRoller.RollGURPS(skill)
+}
+
+/*
+ * RollGURPS is like the package's RollGURPS, but rolls with the Roller's random source.
+ * e.g. roller.RollGURPS(12)
+ */
+func (roller *Roller) RollGURPS(skill int) (output GURPSRoll, err error) {
+	output.Roll, err = roller.rollParsed(newRollSpec(3, DieSpec{Faces: 6}, 0))
+	if err != nil {
+		return
+	}
+
+	output.Roll, err = postProcess(output.Roll)
+	if err != nil {
+		return
+	}
+
+	output.Skill = skill
+	output.Margin = skill - output.Roll.Total
+	output.Outcome = gurpsOutcome(output.Roll.Total, skill)
+
+	return
+}
+
+/*
+ * gurpsOutcome classifies a 3d6 total rolled against an effective skill level.
+ */
+func gurpsOutcome(total, skill int) GURPSOutcome {
+	switch {
+	case total <= 4, total == 5 && skill >= 15, total == 6 && skill >= 16:
+		return GURPSCriticalSuccess
+	case total == 18, total == 17 && skill <= 15, total-skill >= 10:
+		return GURPSCriticalFailure
+	case total == 17, total > skill:
+		return GURPSFailure
+	default:
+		return GURPSSuccess
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "testing"
+
+type gurpsOutcomeTest struct {
+	total, skill int
+	want         GURPSOutcome
+}
+
+var gurpsOutcomeTests = []gurpsOutcomeTest{
+	{3, 3, GURPSCriticalSuccess},
+	{4, 1, GURPSCriticalSuccess},
+	{5, 14, GURPSSuccess},
+	{5, 15, GURPSCriticalSuccess},
+	{6, 15, GURPSSuccess},
+	{6, 16, GURPSCriticalSuccess},
+	{12, 12, GURPSSuccess},
+	{13, 12, GURPSFailure},
+	{14, 4, GURPSCriticalFailure},
+	{16, 18, GURPSSuccess},
+	{17, 15, GURPSCriticalFailure},
+	{17, 16, GURPSFailure},
+	{17, 20, GURPSFailure},
+	{18, 25, GURPSCriticalFailure},
+}
+
+// TestGURPSOutcome calls diceroller.gurpsOutcome with many totals and skills, checking for valid return values.
+func TestGURPSOutcome(t *testing.T) {
+	for _, test := range gurpsOutcomeTests {
+		if output := gurpsOutcome(test.total, test.skill); output != test.want {
+			t.Errorf("have %v, wanted %v for %d against %d", output, test.want, test.total, test.skill)
+		}
+	}
+}
+
+// TestRollGURPS calls diceroller.RollGURPS, checking the roll, margin and outcome agree.
+func TestRollGURPS(t *testing.T) {
+	reseed()
+
+	output, err := RollGURPS(12)
+	if output.Roll.DiscoveredRoll != "3d6" || output.Skill != 12 || output.Margin != 12-output.Roll.Total || output.Outcome != gurpsOutcome(output.Roll.Total, 12) || err != nil {
+		t.Errorf("have %v, err %v", output, err)
+	}
+}
+
+// TestGURPSOutcomeString calls diceroller.GURPSOutcome.String, checking for valid return values.
+func TestGURPSOutcomeString(t *testing.T) {
+	for outcome, want := range map[GURPSOutcome]string{GURPSFailure: "failure", GURPSSuccess: "success", GURPSCriticalSuccess: "critical success", GURPSCriticalFailure: "critical failure"} {
+		if output := outcome.String(); output != want {
+			t.Errorf("have %q, wanted %q", output, want)
+		}
+	}
+}
+
+// BenchmarkRollGURPS benchmarks diceroller.RollGURPS.
+func BenchmarkRollGURPS(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollGURPS(12)
+	}
+}
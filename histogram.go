@@ -0,0 +1,92 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// How many characters wide a Histogram's longest bar is, if its Width isn't set.
+const defaultHistogramWidth = 40
+
+// Histogram is how often each total of a roll comes up, from a Simulation or a RollDistribution, to be drawn as a bar chart.
+type Histogram struct {
+	Roll        string    // The roll, as discovered, e.g. '3d6'.
+	Min         int       // The lowest total.
+	Frequencies []float64 // How often each total comes up, from 0 to 1, starting with Min.
+	Width       int       // How many characters wide the longest bar is when rendered, or 0 for 40.
+}
+
+/*
+ * Histogram returns how often each total came up in the simulation, to be drawn as a bar chart.
+ * e.g. simulation.Histogram().Render()
+ */
+func (output Simulation) Histogram() Histogram {
+	histogram := Histogram{Roll: output.Roll, Min: output.Min, Frequencies: make([]float64, len(output.Counts))}
+
+	for i := range output.Counts {
+		histogram.Frequencies[i] = output.Frequency(output.Min + i)
+	}
+
+	return histogram
+}
+
+/*
+ * Histogram returns the chance of each possible total, to be drawn as a bar chart.
+ * e.g. dist.Histogram().Render()
+ */
+func (output RollDistribution) Histogram() Histogram {
+	return Histogram{Roll: output.Roll, Min: output.Min, Frequencies: output.Probabilities}
+}
+
+/*
+ * Render draws the histogram as an ASCII bar chart, one line per total, with each bar as long as how often the total comes up
+ *   compared to the most common total, followed by the percentage.
+ * e.g. Histogram{Roll: "2d6", Min: 2, Frequencies: ..., Width: 12}.Render() // " 2 | ##             2.78%\n 3 | ####           5.56%\n..."
+ */
+func (histogram Histogram) Render() string {
+	width := histogram.Width
+	if width < 1 {
+		width = defaultHistogramWidth
+	}
+
+	var highest float64
+	for _, frequency := range histogram.Frequencies {
+		highest = max(highest, frequency)
+	}
+
+	var (
+		label  = max(len(strconv.Itoa(histogram.Min)), len(strconv.Itoa(histogram.Min+len(histogram.Frequencies)-1)))
+		output strings.Builder
+	)
+
+	for i, frequency := range histogram.Frequencies {
+		var bar int
+		if highest > 0 {
+			bar = int(math.Round(frequency / highest * float64(width)))
+		}
+
+		fmt.Fprintf(&output, "%*d | %-*s %6.2f%%\n", label, histogram.Min+i, width, strings.Repeat("#", bar), frequency*100)
+	}
+
+	return output.String()
+}
@@ -0,0 +1,77 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHistogram calls diceroller.Histogram.Render for a distribution and a simulation, checking the chart is drawn right.
+func TestHistogram(t *testing.T) {
+	dist, _ := Distribution("2d6")
+
+	histogram := dist.Histogram()
+	histogram.Width = 12
+
+	want := ` 2 | ##             2.78%
+ 3 | ####           5.56%
+ 4 | ######         8.33%
+ 5 | ########      11.11%
+ 6 | ##########    13.89%
+ 7 | ############  16.67%
+ 8 | ##########    13.89%
+ 9 | ########      11.11%
+10 | ######         8.33%
+11 | ####           5.56%
+12 | ##             2.78%
+`
+	if output := histogram.Render(); output != want {
+		t.Errorf("have\n%s\nwanted\n%s", output, want)
+	}
+
+	reseed()
+
+	simulation, _ := Simulate("1d4", 1000)
+	lines := strings.Split(strings.TrimSuffix(simulation.Histogram().Render(), "\n"), "\n")
+
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "1 | #") {
+		t.Errorf("have %q, wanted four bars starting at 1", lines)
+	}
+
+	for _, line := range lines {
+		if bar := strings.Count(line, "#"); bar < 30 || bar > defaultHistogramWidth {
+			t.Errorf("have a bar of %d, wanted about %d for an even die in %q", bar, defaultHistogramWidth, line)
+		}
+	}
+
+	if output := (Histogram{Min: 1, Frequencies: []float64{0, 0}}).Render(); output != "1 | "+strings.Repeat(" ", 40)+"   0.00%\n2 | "+strings.Repeat(" ", 40)+"   0.00%\n" {
+		t.Errorf("have %q, wanted two empty bars", output)
+	}
+}
+
+// BenchmarkHistogram benchmarks diceroller.Histogram.Render.
+func BenchmarkHistogram(b *testing.B) {
+	dist, _ := Distribution("3d6")
+	histogram := dist.Histogram()
+
+	for i := 0; i < b.N; i++ {
+		_ = histogram.Render()
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record is one roll, along with who made it and when, as kept in a roll history.
+type Record struct {
+	Time    time.Time `json:"time"`              // When the roll was made.
+	User    string    `json:"user,omitempty"`    // Who made the roll, if known.
+	Session string    `json:"session,omitempty"` // The session, game or channel the roll was made in, if known.
+	Label   string    `json:"label,omitempty"`   // What the roll was for, e.g. 'attack' or 'fireball', if known.
+	Roll    DiceRoll  `json:"roll"`              // The roll itself.
+	Die     *DieSpec  `json:"die,omitempty"`     // The die rolled, if it was weighted, as the roll's notation can't say so.
+}
+
+// History keeps the most recent rolls a Roller made with WithHistory, oldest first, e.g. for a bot's '!history' command. Records
+// from elsewhere, e.g. with who made the roll, can be added too. It's safe for concurrent use, and a nil History is empty.
+type History struct {
+	mu      sync.Mutex
+	size    int      // The most records to keep.
+	records []Record // The records kept, oldest first.
+}
+
+/*
+ * NewHistory returns an empty History which keeps the most recent size records, or every record if size is 0 or less.
+ * e.g. NewHistory(1000)
+ */
+func NewHistory(size int) *History {
+	return &History{size: size}
+}
+
+/*
+ * WithHistory makes a Roller keep a History of its most recent size rolls, or every roll if size is 0 or less. Roller.History
+ *   returns it.
+ * e.g. NewRoller(WithHistory(100))
+ */
+func WithHistory(size int) RollerOption {
+	return func(roller *Roller) {
+		roller.history = NewHistory(size)
+	}
+}
+
+/*
+ * History returns the Roller's History, or nil if it wasn't made with WithHistory.
+ */
+func (roller *Roller) History() *History {
+	return roller.history
+}
+
+/*
+ * RollLabelled is like RollDetails for one roll, but the roll's Record in the Roller's History has the label, e.g. 'attack'.
+ * e.g. roller.RollLabelled("fireball", "8d6") // {8d6 6 8 0 [6 3 6 4 6 1 3 4] 33 ...}
+ */
+func (roller *Roller) RollLabelled(label, input string) (DiceRoll, error) {
+	return roller.rollLabelled(label, input)
+}
+
+/*
+ * Add adds a record to the History, dropping the oldest if it's full. The record is added as it is, so it's up to the caller to
+ *   give it a time.
+ */
+func (history *History) Add(record Record) {
+	if history == nil {
+		return
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	history.records = append(history.records, record)
+
+	if history.size > 0 && len(history.records) > history.size {
+		history.records = slices.Delete(history.records, 0, len(history.records)-history.size)
+	}
+}
+
+/*
+ * add adds a roll to the History, made now, with the label.
+ */
+func (history *History) add(roll DiceRoll, label string) {
+	if history != nil {
+		history.Add(Record{Time: time.Now(), Label: label, Roll: roll})
+	}
+}
+
+/*
+ * addDie adds a roll of a die to the History, if there is one, keeping the die if it's weighted.
+ */
+func (history *History) addDie(roll DiceRoll, die DieSpec) {
+	if history == nil {
+		return
+	}
+
+	record := Record{Time: time.Now(), Roll: roll}
+	if len(die.Weights) > 0 {
+		record.Die = &DieSpec{Faces: die.Faces, Weights: slices.Clone(die.Weights)}
+	}
+
+	history.Add(record)
+}
+
+/*
+ * All returns every record in the History, oldest first.
+ */
+func (history *History) All() []Record {
+	return history.Last(-1)
+}
+
+/*
+ * Last returns the most recent n records in the History, oldest first, or all of them if there are fewer, or n is less than 0.
+ * e.g. history.Last(5)
+ */
+func (history *History) Last(n int) []Record {
+	if history == nil {
+		return nil
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	if n < 0 || n > len(history.records) {
+		n = len(history.records)
+	}
+
+	return slices.Clone(history.records[len(history.records)-n:])
+}
+
+/*
+ * Labelled returns the records in the History with the given label, oldest first.
+ * e.g. history.Labelled("attack")
+ */
+func (history *History) Labelled(label string) (output []Record) {
+	for _, record := range history.All() {
+		if record.Label == label {
+			output = append(output, record)
+		}
+	}
+
+	return
+}
+
+/*
+ * Totals returns the totals of each roll in the History, oldest first, keyed by the roll as discovered.
+ * e.g. history.Totals() // map[1d20:[12 7 20] 2d6+3:[9]]
+ */
+func (history *History) Totals() map[string][]int {
+	output := make(map[string][]int)
+
+	for _, record := range history.All() {
+		output[record.Roll.DiscoveredRoll] = append(output[record.Roll.DiscoveredRoll], record.Roll.Total)
+	}
+
+	return output
+}
+
+/*
+ * Len returns how many records are in the History.
+ */
+func (history *History) Len() int {
+	if history == nil {
+		return 0
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	return len(history.records)
+}
+
+/*
+ * MarshalJSON exports the History as a JSON array of its records, oldest first.
+ */
+func (history *History) MarshalJSON() ([]byte, error) {
+	records := history.All()
+	if records == nil {
+		records = []Record{}
+	}
+
+	return json.Marshal(records)
+}
+
+/*
+ * matches reports whether a Record was made by the given user in the given session. An empty user or session matches any.
+ */
+func (record Record) matches(user, session string) bool {
+	return (user == "" || record.User == user) && (session == "" || record.Session == session)
+}
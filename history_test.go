@@ -0,0 +1,119 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestHistory rolls with a diceroller.Roller made WithHistory, checking every roll is kept, up to the size, and can be queried.
+func TestHistory(t *testing.T) {
+	roller := NewRoller(WithSeed(42, 1024), WithHistory(5))
+
+	attack, _ := roller.RollLabelled("attack", "1d20+5")
+	damage, _ := roller.RollDetails("2d6+3", "1d20+5")
+
+	if roller.History().Len() != 3 {
+		t.Fatalf("have %d records, wanted 3", roller.History().Len())
+	}
+
+	if labelled := roller.History().Labelled("attack"); len(labelled) != 1 || !reflect.DeepEqual(labelled[0].Roll, attack) || labelled[0].Time.IsZero() {
+		t.Errorf("have %v, wanted the attack roll, with a time", labelled)
+	}
+
+	want := map[string][]int{"1d20+5": {attack.Total, damage[1].Total}, "2d6+3": {damage[0].Total}}
+	if totals := roller.History().Totals(); !reflect.DeepEqual(totals, want) {
+		t.Errorf("have %v, wanted %v", totals, want)
+	}
+
+	// Every way a Roller rolls is recorded, and the oldest records are dropped once it's full.
+	compiled, _ := roller.Compile("1d4")
+	_, _ = compiled.Roll()
+	_, _ = roller.RollDie(DieSpec{Faces: 6}, 1, 0)
+	_, _ = roller.RollOneWith("1d8", WithSeed(1, 2))
+	_, _ = roller.RollOne("nothing")
+
+	last := roller.History().Last(5)
+	if len(last) != 5 || last[0].Roll.DiscoveredRoll != "2d6+3" || last[4].Roll.DiscoveredRoll != "1d8" {
+		t.Errorf("have %v, wanted the last five rolls, from 2d6+3 to 1d8", last)
+	}
+
+	if last := roller.History().Last(2); len(last) != 2 || last[1].Roll.DiscoveredRoll != "1d8" || len(roller.History().Last(99)) != 5 {
+		t.Errorf("have %v, wanted the last two rolls", last)
+	}
+
+	output, err := json.Marshal(roller.History())
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(output, &records); err != nil || len(records) != 5 || !records[0].Time.Equal(last[0].Time) {
+		t.Errorf("have %v, err %v, wanted the five records back from %s", records, err, output)
+	}
+
+	// Without WithHistory, there's no History, and asking it for records is fine.
+	none := NewRoller().History()
+	if none != nil || none.Len() != 0 || none.All() != nil || len(none.Totals()) != 0 {
+		t.Errorf("have %v, wanted no history", none)
+	}
+
+	if output, _ := json.Marshal(NewHistory(5)); string(output) != "[]" {
+		t.Errorf("have %s, wanted []", output)
+	}
+}
+
+// TestHistoryConcurrent adds records to a diceroller.History from many goroutines, checking none are lost, for running with -race.
+func TestHistoryConcurrent(t *testing.T) {
+	var (
+		history = NewHistory(0)
+		wg      sync.WaitGroup
+	)
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				history.Add(Record{User: "alice"})
+				_ = history.Last(3)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if history.Len() != 1000 {
+		t.Errorf("have %d records, wanted 1000", history.Len())
+	}
+}
+
+// BenchmarkHistory benchmarks rolling with a diceroller.Roller which keeps a History.
+func BenchmarkHistory(b *testing.B) {
+	roller := NewRoller(WithHistory(1000))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollOne("4d6kh3")
+	}
+}
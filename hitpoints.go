@@ -0,0 +1,197 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The most levels RollHitPoints will roll for, across all classes.
+const maxHitPointLevels = 100
+
+// Hit dice for one class, e.g. 'd8 x5 +3 CON each', '5d8+3' or 'd10'. The bit after the modifier is a label, and is ignored.
+var hitDiceRegex = regexp.MustCompile(`(?i)^(\d{0,3})d(\d{1,6})(?:\s*x\s*(\d{1,3}))?(?:\s*([+-])\s*(\d{1,6})(?:\s*[a-z]+)?(?:\s+each)?)?$`)
+
+// HitDice holds one class's hit dice: the die, how many levels of it, and the modifier added at each level.
+type HitDice struct {
+	Faces    int
+	Levels   int
+	Modifier int
+}
+
+// HitPointMethod is how RollHitPoints works out the hit points gained at each level.
+type HitPointMethod int
+
+const (
+	HitPointRoll    HitPointMethod = iota // Roll the hit die.
+	HitPointAverage                       // Take the fixed average, as in D&D 5e: half the faces, plus one.
+	HitPointBest                          // Roll, but take the average if it's higher.
+)
+
+/*
+ * String returns the method's name.
+ */
+func (method HitPointMethod) String() string {
+	switch method {
+	case HitPointRoll:
+		return "roll"
+	case HitPointAverage:
+		return "average"
+	case HitPointBest:
+		return "best"
+	default:
+		return fmt.Sprintf("HitPointMethod(%d)", int(method))
+	}
+}
+
+// HitPointOptions holds the house rules for RollHitPoints. The zero value rolls every level, including the first.
+type HitPointOptions struct {
+	Method        HitPointMethod // How the hit points gained at each level are worked out.
+	MaxFirstLevel bool           // Whether the first level gets the most the die can roll, as in D&D 5e. Its Roll shows the die maxed.
+}
+
+// HitPointLevel holds how many hit points a character gained at one level, with the roll and average side by side.
+type HitPointLevel struct {
+	Level   int      // The character level, starting at 1.
+	Faces   int      // The hit die's faces.
+	Roll    DiceRoll // The hit die roll, with the modifier.
+	Rolled  int      // What the roll gives, at least 1.
+	Average int      // What taking the average gives, at least 1.
+	Gained  int      // The hit points actually gained, by the options.
+	Total   int      // The running total of hit points at this level.
+}
+
+// HitPoints holds a character's hit points, level by level, and what they'd be had every level been rolled or averaged.
+type HitPoints struct {
+	Levels       []HitPointLevel
+	Total        int // The hit points gained, by the options.
+	RolledTotal  int // The hit points had every level been rolled (with the first level maxed, if that's an option).
+	AverageTotal int // The hit points had every level been averaged (likewise).
+}
+
+// highestFace is a RandSource which always comes up on the die's highest face, so a maxed first level's roll shows what it gave.
+type highestFace struct{}
+
+/*
+ * IntN returns n-1, so the die comes up n.
+ */
+func (highestFace) IntN(n int) int {
+	return n - 1
+}
+
+/*
+ * ParseHitDice returns the hit dice for each class, comma-separated, e.g. 'd8 x5 +3 CON each, d10 x2 +3 CON each'. A leading count
+ * is the same as 'x', so '5d8+3' is five levels of d8 with 3 added at each.
+ */
+func ParseHitDice(input string) (output []HitDice, err error) {
+	var levels int
+
+	for _, part := range strings.Split(input, ",") {
+		result := hitDiceRegex.FindStringSubmatch(strings.TrimSpace(part))
+		if result == nil {
+			return nil, fmt.Errorf("%q isn't hit dice, e.g. 'd8 x5 +3 CON each'", strings.TrimSpace(part))
+		}
+
+		hd := HitDice{Levels: 1}
+		hd.Faces, _ = strconv.Atoi(result[2])
+
+		switch {
+		case result[1] != "" && result[3] != "":
+			return nil, fmt.Errorf("%q has a count and 'x', wanted one or the other", strings.TrimSpace(part))
+		case result[1] != "":
+			hd.Levels, _ = strconv.Atoi(result[1])
+		case result[3] != "":
+			hd.Levels, _ = strconv.Atoi(result[3])
+		}
+
+		if result[5] != "" {
+			hd.Modifier, _ = strconv.Atoi(result[4] + result[5])
+		}
+
+		if hd.Faces < 1 || hd.Levels < 1 {
+			return nil, fmt.Errorf("%q needs at least one level of a die with at least one face", strings.TrimSpace(part))
+		}
+
+		if levels += hd.Levels; levels > maxHitPointLevels {
+			return nil, fmt.Errorf("%q is more than %d levels", input, maxHitPointLevels)
+		}
+
+		output = append(output, hd)
+	}
+
+	return
+}
+
+/*
+ * RollHitPoints rolls hit points level by level for hit dice written as for ParseHitDice, and returns each level's roll and average,
+ *   the running total, and the totals had every level been rolled or averaged, for comparison.
+ * e.g. RollHitPoints("d8 x5 +3 CON each", HitPointOptions{MaxFirstLevel: true}) // {[...] 34 34 43}
+ */
+func RollHitPoints(hitDice string, options HitPointOptions) (output HitPoints, err error) {
+	classes, err := ParseHitDice(hitDice)
+	if err != nil {
+		return
+	}
+
+	for _, hd := range classes {
+		spec := newRollSpec(1, DieSpec{Faces: hd.Faces}, hd.Modifier)
+
+		for range hd.Levels {
+			level := HitPointLevel{Level: len(output.Levels) + 1, Faces: hd.Faces}
+			maxed := level.Level == 1 && options.MaxFirstLevel
+
+			roll := spec
+			if maxed {
+				roll.random = highestFace{}
+			}
+
+			if level.Roll, err = postProcess(roll.roll()); err != nil {
+				return HitPoints{}, err
+			}
+
+			level.Rolled = max(level.Roll.Total, 1)
+			level.Average = max(hd.Faces/2+1+hd.Modifier, 1)
+
+			if maxed {
+				level.Average = level.Rolled
+			}
+
+			switch options.Method {
+			case HitPointAverage:
+				level.Gained = level.Average
+			case HitPointBest:
+				level.Gained = max(level.Rolled, level.Average)
+			default:
+				level.Gained = level.Rolled
+			}
+
+			output.Total += level.Gained
+			output.RolledTotal += level.Rolled
+			output.AverageTotal += level.Average
+			level.Total = output.Total
+
+			output.Levels = append(output.Levels, level)
+		}
+	}
+
+	return
+}
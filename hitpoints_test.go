@@ -0,0 +1,131 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type parseHitDiceTest struct {
+	input string
+	want  []HitDice
+}
+
+var parseHitDiceTests = []parseHitDiceTest{
+	{"d8", []HitDice{{8, 1, 0}}},
+	{"d8 x5 +3 CON each", []HitDice{{8, 5, 3}}},
+	{"5d8+3", []HitDice{{8, 5, 3}}},
+	{"D6X2 - 1 con", []HitDice{{6, 2, -1}}},
+	{"d8 x5 +3 each, d10 x2", []HitDice{{8, 5, 3}, {10, 2, 0}}},
+}
+
+// TestParseHitDice calls diceroller.ParseHitDice with many hit dice, checking for valid return values.
+func TestParseHitDice(t *testing.T) {
+	for _, test := range parseHitDiceTests {
+		if output, err := ParseHitDice(test.input); !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v for %q, err %v", output, test.want, test.input, err)
+		}
+	}
+
+	for _, input := range []string{"", "d8 x", "2d8 x5", "d0", "0d8", "d8 x0", "d8 +3 CON each extra", "d8 x60, d10 x41", "8"} {
+		if output, err := ParseHitDice(input); err == nil {
+			t.Errorf("have %v, nil error for %q, wanted an error", output, input)
+		}
+	}
+}
+
+type rollHitPointsTest struct {
+	options          HitPointOptions
+	wantGained       []int
+	wantTotal        int
+	wantRolledTotal  int
+	wantAverageTotal int
+}
+
+var rollHitPointsTests = []rollHitPointsTest{
+	{HitPointOptions{}, []int{5, 7, 6, 5, 7, 1, 4}, 35, 35, 52},
+	{HitPointOptions{MaxFirstLevel: true}, []int{11, 5, 7, 6, 5, 10, 1}, 45, 45, 55},
+	{HitPointOptions{Method: HitPointAverage, MaxFirstLevel: true}, []int{11, 8, 8, 8, 8, 6, 6}, 55, 45, 55},
+	{HitPointOptions{Method: HitPointBest}, []int{8, 8, 8, 8, 8, 6, 6}, 52, 35, 52},
+}
+
+// TestRollHitPoints calls diceroller.RollHitPoints with many options, checking for valid return values.
+func TestRollHitPoints(t *testing.T) {
+	for _, test := range rollHitPointsTests {
+		reseed()
+
+		output, err := RollHitPoints("d8 x5 +3 CON each, d10 x2", test.options)
+		if output.Total != test.wantTotal || output.RolledTotal != test.wantRolledTotal || output.AverageTotal != test.wantAverageTotal ||
+			err != nil {
+			t.Errorf("have %d %d %d, wanted %d %d %d for %+v, err %v", output.Total, output.RolledTotal, output.AverageTotal,
+				test.wantTotal, test.wantRolledTotal, test.wantAverageTotal, test.options, err)
+		}
+
+		var gained []int
+		var total int
+		for i, level := range output.Levels {
+			gained = append(gained, level.Gained)
+			total += level.Gained
+
+			if level.Level != i+1 || level.Total != total {
+				t.Errorf("have level %d with total %d, wanted level %d with total %d", level.Level, level.Total, i+1, total)
+			}
+
+			if level.Rolled != max(level.Roll.Total, 1) {
+				t.Errorf("have rolled %d, wanted %d from the roll %v for %+v", level.Rolled, max(level.Roll.Total, 1), level.Roll, test.options)
+			}
+		}
+
+		if first := output.Levels[0]; test.options.MaxFirstLevel && !slices.Equal(first.Roll.Results, []int{first.Faces}) {
+			t.Errorf("have %v, wanted [%d] for a maxed first level", first.Roll.Results, first.Faces)
+		}
+
+		if !slices.Equal(gained, test.wantGained) {
+			t.Errorf("have %v, wanted %v for %+v", gained, test.wantGained, test.options)
+		}
+	}
+
+	reseed()
+
+	if output, err := RollHitPoints("d4-5", HitPointOptions{}); err != nil || output.Total != 1 || output.Levels[0].Average != 1 {
+		t.Errorf("have %v, wanted at least 1 hit point, err %v", output, err)
+	}
+
+	if output, err := RollHitPoints("d8 x", HitPointOptions{}); err == nil || !reflect.DeepEqual(output, HitPoints{}) {
+		t.Errorf("have %v, nil error, wanted an error", output)
+	}
+}
+
+// TestHitPointMethodString calls diceroller.HitPointMethod.String, checking for valid return values.
+func TestHitPointMethodString(t *testing.T) {
+	for method, want := range map[HitPointMethod]string{HitPointRoll: "roll", HitPointAverage: "average", HitPointBest: "best", 9: "HitPointMethod(9)"} {
+		if method.String() != want {
+			t.Errorf("have %q, wanted %q", method.String(), want)
+		}
+	}
+}
+
+// BenchmarkRollHitPoints benchmarks diceroller.RollHitPoints.
+func BenchmarkRollHitPoints(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollHitPoints("d8 x5 +3 CON each, d10 x2", HitPointOptions{MaxFirstLevel: true})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// HouseRules holds a table's house rules in one place, so the check, attack and damage helpers all apply them the same way.
+// The zero value is the rules as written: crits on a natural 20, fumbles on a natural 1, and nothing else changed.
+type HouseRules struct {
+	CritRange      int  `json:"crit_range,omitempty"`       // The lowest natural d20 roll which is a critical, e.g. 19 for 19-20. Zero means 20 only.
+	FumbleRange    int  `json:"fumble_range,omitempty"`     // The highest natural d20 roll which is a fumble, e.g. 2 for 1-2. Zero means 1 only.
+	CritsSucceed   bool `json:"crits_succeed,omitempty"`    // Whether a critical check always succeeds, whatever the DC. Critical attacks always hit regardless.
+	FumblesFail    bool `json:"fumbles_fail,omitempty"`     // Whether a fumbled check always fails, whatever the DC. Fumbled attacks always miss regardless.
+	MaxDamageCrits bool `json:"max_damage_crits,omitempty"` // Whether critical damage adds the dice's maximum to one roll of them, rather than rolling twice as many dice.
+	RerollOnes     bool `json:"reroll_ones,omitempty"`      // Whether damage dice which come up 1 are rerolled once, keeping the new roll.
+	MinimumTotal   int  `json:"minimum_total,omitempty"`    // The lowest a check, attack or damage total can be, e.g. 1 so a hit always hurts. Zero means no minimum.
+}
+
+/*
+ * Check rolls one string in the correct 'nDn+n' format against a DC under the house rules, spotting criticals and fumbles on a single d20.
+ * e.g. HouseRules{CritRange: 19, CritsSucceed: true}.Check("1d20+2", 25) // {{1d20+2 20 1 2 [19] 21 ...} 25 true -4 true false}
+ */
+func (rules HouseRules) Check(input string, dc int) (output Check, err error) {
+	return rules.check(input, dc, rules.CritsSucceed, rules.FumblesFail)
+}
+
+/*
+ * Attack rolls one string in the correct 'nDn+n' format against an AC (armour class) under the house rules. It works like Check,
+ *   except a critical always hits and a fumble always misses.
+ * e.g. HouseRules{}.Attack("1d20+5", 16) // {{1d20+5 20 1 5 [20] 25 ...} 16 true 9 true false}
+ */
+func (rules HouseRules) Attack(input string, ac int) (output Check, err error) {
+	return rules.check(input, ac, true, true)
+}
+
+/*
+ * Damage rolls one string in the correct 'nDn+n' format for damage under the house rules. A critical hit rolls twice as many dice,
+ *   or with MaxDamageCrits, adds the dice's maximum to the modifier instead.
+ * e.g. HouseRules{MaxDamageCrits: true}.Damage("2d6+3", true) // {2d6+3 6 2 15 [4 2] 21 ...}
+ */
+func (rules HouseRules) Damage(input string, critical bool) (output DiceRoll, err error) {
+	spec, err := parseRoll(input)
+	if err != nil {
+		return
+	}
+
+	if rules.RerollOnes {
+		spec.reroll = 1
+	}
+
+	// How many dice count towards the total, for the maximum damage.
+	kept := spec.rolls
+	if spec.keep != "" {
+		kept = min(spec.keepCount, spec.rolls)
+	}
+
+	// A critical makes the roll bigger, so it's checked again, in case it could now total more than an int can hold.
+	if critical {
+		if rules.MaxDamageCrits {
+			if spec.modifier, err = checkedAdd(spec.modifier, kept*(spec.die.Faces+spec.dieModifier)); err != nil {
+				return
+			}
+		} else {
+			spec.rolls *= 2
+			spec.keepCount *= 2
+		}
+
+		if err = spec.validate(); err != nil {
+			return
+		}
+	}
+
+	return rules.finish(spec.roll())
+}
+
+/*
+ * check rolls against a DC, with criticals and fumbles succeeding or failing outright if asked.
+ */
+func (rules HouseRules) check(input string, dc int, critsSucceed, fumblesFail bool) (output Check, err error) {
+	spec, err := parseRoll(input)
+	if err != nil {
+		return
+	}
+
+	dr, err := rules.finish(spec.roll())
+	if err != nil {
+		return
+	}
+
+	output = newCheck(dr, dc)
+	output.Critical, output.Fumble = rules.naturals(dr)
+
+	switch {
+	case output.Critical && critsSucceed:
+		output.Success = true
+	case output.Fumble && fumblesFail:
+		output.Success = false
+	}
+
+	return
+}
+
+/*
+ * naturals returns whether a roll of a single d20 (once any dropped dice are ignored, so advantage counts) is a critical or a fumble.
+ */
+func (rules HouseRules) naturals(dr DiceRoll) (critical, fumble bool) {
+	kept := keptResults(dr)
+	if dr.Faces != 20 || len(kept) != 1 {
+		return
+	}
+
+	critRange, fumbleRange := rules.CritRange, rules.FumbleRange
+	if critRange == 0 {
+		critRange = 20
+	}
+
+	if fumbleRange == 0 {
+		fumbleRange = 1
+	}
+
+	return kept[0] >= critRange, kept[0] <= fumbleRange
+}
+
+/*
+ * finish raises a roll's total to the minimum total, if there is one, by adding to its modifier, so the roll still adds up when
+ *   prettified, then post-processes it as usual.
+ */
+func (rules HouseRules) finish(input DiceRoll) (DiceRoll, error) {
+	if rules.MinimumTotal != 0 && input.Total < rules.MinimumTotal {
+		input.Modifier += rules.MinimumTotal - input.Total
+		input.Total = rules.MinimumTotal
+	}
+
+	return postProcess(input)
+}
@@ -0,0 +1,147 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type houseRulesNaturalsTest struct {
+	rules        HouseRules
+	roll         DiceRoll
+	wantCritical bool
+	wantFumble   bool
+}
+
+var houseRulesNaturalsTests = []houseRulesNaturalsTest{
+	{HouseRules{}, DiceRoll{Faces: 20, Results: []int{20}}, true, false},
+	{HouseRules{}, DiceRoll{Faces: 20, Results: []int{19}}, false, false},
+	{HouseRules{}, DiceRoll{Faces: 20, Results: []int{1}}, false, true},
+	{HouseRules{CritRange: 19}, DiceRoll{Faces: 20, Results: []int{19}}, true, false},
+	{HouseRules{FumbleRange: 2}, DiceRoll{Faces: 20, Results: []int{2}}, false, true},
+	{HouseRules{}, DiceRoll{Faces: 20, Results: []int{1, 20}, Dropped: []int{0}}, true, false},
+	{HouseRules{}, DiceRoll{Faces: 20, Results: []int{20, 20}}, false, false},
+	{HouseRules{}, DiceRoll{Faces: 6, Results: []int{1}}, false, false},
+}
+
+// TestHouseRulesNaturals calls diceroller.HouseRules.naturals with many rolls and crit and fumble ranges, checking for valid return values.
+func TestHouseRulesNaturals(t *testing.T) {
+	for _, test := range houseRulesNaturalsTests {
+		critical, fumble := test.rules.naturals(test.roll)
+
+		if critical != test.wantCritical || fumble != test.wantFumble {
+			t.Errorf("have %v %v, wanted %v %v for %v under %+v", critical, fumble, test.wantCritical, test.wantFumble, test.roll.Results, test.rules)
+		}
+	}
+}
+
+type houseRulesCheckTest struct {
+	rules       HouseRules
+	got         string
+	dc          int
+	attack      bool
+	wantTotal   int
+	wantSuccess bool
+	wantCrit    bool
+	wantFumble  bool
+}
+
+var houseRulesCheckTests = []houseRulesCheckTest{
+	{HouseRules{CritRange: 19, CritsSucceed: true}, "1d20+2", 25, false, 21, true, true, false},  // Rolls 19.
+	{HouseRules{MinimumTotal: 12}, "1d20", 12, false, 12, true, false, false},                    // Rolls 10.
+	{HouseRules{CritRange: 19}, "1d20+5", 25, false, 24, false, true, false},                     // Rolls 19.
+	{HouseRules{}, "1d20adv", 30, true, 20, true, true, false},                                   // Rolls 11 and 20.
+	{HouseRules{FumblesFail: true, MinimumTotal: 12}, "1d20", 10, false, 12, false, false, true}, // Rolls 1.
+}
+
+// TestHouseRulesCheck calls diceroller.HouseRules.Check and diceroller.HouseRules.Attack under many house rules, checking for valid return values.
+func TestHouseRulesCheck(t *testing.T) {
+	reseed()
+
+	for _, test := range houseRulesCheckTests {
+		check := test.rules.Check
+		if test.attack {
+			check = test.rules.Attack
+		}
+
+		output, err := check(test.got, test.dc)
+
+		if output.Roll.Total != test.wantTotal || output.Success != test.wantSuccess || output.Critical != test.wantCrit ||
+			output.Fumble != test.wantFumble || err != nil {
+			t.Errorf("have %+v, wanted total %d, success %v, critical %v, fumble %v, err %v", output, test.wantTotal, test.wantSuccess,
+				test.wantCrit, test.wantFumble, err)
+		}
+	}
+
+	if _, err := (HouseRules{}).Attack("nonsense", 10); err == nil {
+		t.Errorf("have nil error for no roll, wanted an error")
+	}
+}
+
+type houseRulesDamageTest struct {
+	rules    HouseRules
+	got      string
+	critical bool
+	want     DiceRoll
+}
+
+var houseRulesDamageTests = []houseRulesDamageTest{
+	{HouseRules{}, "2d6+3", true, DiceRoll{DiscoveredRoll: "2d6+3", Faces: 6, Rolls: 4, Modifier: 3, Results: []int{6, 3, 6, 4}, Total: 22, NaturalTotal: 19}},
+	{HouseRules{}, "4d6kh3-10", false, DiceRoll{DiscoveredRoll: "4d6kh3-10", Faces: 6, Rolls: 4, Modifier: -10, Results: []int{6, 1, 3, 4}, Total: 3, NaturalTotal: 13, Dropped: []int{1}}},
+	{HouseRules{MaxDamageCrits: true}, "2d6+3", true, DiceRoll{DiscoveredRoll: "2d6+3", Faces: 6, Rolls: 2, Modifier: 15, Results: []int{2, 2}, Total: 19, NaturalTotal: 4}},
+	{HouseRules{RerollOnes: true}, "2d6+3", true, DiceRoll{DiscoveredRoll: "2d6+3", Faces: 6, Rolls: 4, Modifier: 3, Results: []int{6, 3, 6, 5}, Total: 23, NaturalTotal: 20}},
+	{HouseRules{MinimumTotal: 5}, "4d6kh3-10", false, DiceRoll{DiscoveredRoll: "4d6kh3-10", Faces: 6, Rolls: 4, Modifier: -5, Results: []int{2, 6, 2, 1}, Total: 5, NaturalTotal: 10, Dropped: []int{3}}},
+}
+
+// TestHouseRulesDamage calls diceroller.HouseRules.Damage under many house rules, checking for valid return values.
+func TestHouseRulesDamage(t *testing.T) {
+	reseed()
+
+	for _, test := range houseRulesDamageTests {
+		output, err := test.rules.Damage(test.got, test.critical)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	// Raising a total to the minimum adds to the modifier, so the roll still adds up.
+	if output := PrettifyOne(houseRulesDamageTests[4].want); output != "2 + 6 + 2 + ~~1~~ (-5) = 5" {
+		t.Errorf("have %q, wanted the minimum total in the modifier", output)
+	}
+
+	// A critical doubles the dice, or adds their maximum, which fits on 64-bit platforms, but not 32-bit ones.
+	for _, rules := range []HouseRules{{}, {MaxDamageCrits: true}} {
+		if _, err := rules.Damage("20000d99999", true); (err != nil) != (math.MaxInt == math.MaxInt32) || (err != nil && !errors.Is(err, ErrOverflow)) {
+			t.Errorf("have err %v for a critical under %+v on a %d-bit int", err, rules, 32<<(^uint(0)>>63))
+		}
+	}
+}
+
+// BenchmarkHouseRulesDamage benchmarks diceroller.HouseRules.Damage.
+func BenchmarkHouseRulesDamage(b *testing.B) {
+	rules := HouseRules{RerollOnes: true, MaxDamageCrits: true}
+
+	for i := 0; i < b.N; i++ {
+		_, _ = rules.Damage("2d6+3", true)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The delimiters InlineRolls looks for when its own are empty, as in '[[2d6]]'.
+const (
+	DefaultInlineOpen  = "[["
+	DefaultInlineClose = "]]"
+)
+
+// InlineRolls finds the rolls in a chat message between delimiters, such as the '[[2d6+3]]' in 'I hit for [[2d6+3]]!', rolls them,
+// and puts the results in their place, as chat bots do.
+type InlineRolls struct {
+	Open   string                // What starts an inline roll. Empty means DefaultInlineOpen.
+	Close  string                // What ends an inline roll. Empty means DefaultInlineClose.
+	Roller *Roller               // What rolls them, e.g. one made with WithMaxTotalDice. Nil means the package's default Roller.
+	Format func(DiceRoll) string // How each roll is written in the message. Nil means just the total, e.g. '10'.
+}
+
+/*
+ * ProcessText rolls the inline rolls in a message, between '[[' and ']]', and returns the message with each roll's total in its
+ *   place, and the details of every roll, in order. InlineRolls has ProcessText too, for other delimiters, Rollers and formats.
+ * e.g. ProcessText("I hit for [[2d6+3]]!") // "I hit for 10!", [{2d6+3 6 2 3 [4 3] 10 ...}], nil
+ */
+func ProcessText(msg string) (string, []DiceRoll, error) {
+	return InlineRolls{}.ProcessText(msg)
+}
+
+/*
+ * ProcessText is like the package's ProcessText, but with the InlineRolls' delimiters, Roller and format. The rolls are rolled
+ *   together, so WithMaxTotalDice limits the whole message. A delimiter which isn't closed is left as it is, and a bad roll is an
+ *   error, with nothing rolled.
+ * e.g. InlineRolls{Open: "{", Close: "}", Format: PrettifyOne}.ProcessText("Fireball: {8d6}") // "Fireball: 6 + 3 + 6 + 4 + 6 + 1 + 3 + 4 = 33", ...
+ */
+func (inline InlineRolls) ProcessText(msg string) (string, []DiceRoll, error) {
+	var (
+		open, close = inline.Open, inline.Close
+		roller      = inline.Roller
+		format      = inline.Format
+	)
+
+	if open == "" {
+		open = DefaultInlineOpen
+	}
+
+	if close == "" {
+		close = DefaultInlineClose
+	}
+
+	if roller == nil {
+		roller = defaultRoller
+	}
+
+	if format == nil {
+		format = func(roll DiceRoll) string { return strconv.Itoa(roll.Total) }
+	}
+
+	// Find every inline roll first, so they can be rolled together.
+	var (
+		text   []string // The message around the rolls: one more than there are rolls.
+		inputs []string
+		rest   = msg
+	)
+
+	for {
+		before, after, found := strings.Cut(rest, open)
+		if !found {
+			break
+		}
+
+		input, remaining, found := strings.Cut(after, close)
+		if !found {
+			break
+		}
+
+		text = append(text, before)
+		inputs = append(inputs, input)
+		rest = remaining
+	}
+
+	text = append(text, rest)
+
+	if len(inputs) == 0 {
+		return msg, nil, nil
+	}
+
+	rolls, err := roller.RollDetails(inputs...)
+	if err != nil {
+		return "", nil, fmt.Errorf("can't roll the inline rolls in %q: %w", msg, err)
+	}
+
+	var output strings.Builder
+	for i, roll := range rolls {
+		output.WriteString(text[i] + format(roll))
+	}
+
+	output.WriteString(text[len(rolls)])
+
+	return output.String(), rolls, nil
+}
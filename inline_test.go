@@ -0,0 +1,77 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var inlineTests = map[string]string{
+	"I hit for [[2d6+3]]!":      "I hit for 12!",
+	"[[2d6]][[1d4]]":            "93",
+	"[[1d20]] to hit":           "19 to hit",
+	"no rolls here":             "no rolls here",
+	"an unclosed [[2d6 is text": "an unclosed [[2d6 is text",
+	"":                          "",
+}
+
+// TestProcessText calls diceroller.InlineRolls.ProcessText with messages, checking for valid return values.
+func TestProcessText(t *testing.T) {
+	for msg, want := range inlineTests {
+		output, rolls, err := InlineRolls{Roller: NewRoller(WithSeed(42, 1024))}.ProcessText(msg)
+		if output != want || err != nil {
+			t.Errorf("have %q, %v, wanted %q from %q", output, err, want, msg)
+		}
+
+		if want == msg && rolls != nil {
+			t.Errorf("have %v, wanted no rolls from %q", rolls, msg)
+		}
+	}
+
+	_, rolls, _ := InlineRolls{Roller: NewRoller(WithSeed(42, 1024))}.ProcessText("[[2d6]] and [[1d4]]")
+	if want := [][]int{{6, 3}, {3}}; len(rolls) != 2 || !reflect.DeepEqual([][]int{rolls[0].Results, rolls[1].Results}, want) {
+		t.Errorf("have %v, wanted results %v", rolls, want)
+	}
+
+	fireball := InlineRolls{Open: "{", Close: "}", Roller: NewRoller(WithSeed(42, 1024)), Format: PrettifyOne}
+	if output, _, _ := fireball.ProcessText("Fireball: {8d6}"); output != "Fireball: 6 + 3 + 6 + 4 + 6 + 1 + 3 + 4 = 33" {
+		t.Errorf("have %q, wanted the 8d6 prettified", output)
+	}
+
+	// A bad roll rolls nothing, as does going over the Roller's limit across the whole message.
+	if output, rolls, err := ProcessText("[[2d6]] and [[bad]]"); !errors.Is(err, ErrNoRollFound) || output != "" || rolls != nil {
+		t.Errorf("have %q, %v, %v, wanted %v", output, rolls, err, ErrNoRollFound)
+	}
+
+	limited := InlineRolls{Roller: NewRoller(WithMaxTotalDice(10))}
+	if _, _, err := limited.ProcessText("[[6d6]] then [[6d6]]"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+}
+
+// BenchmarkProcessText benchmarks diceroller.ProcessText.
+func BenchmarkProcessText(b *testing.B) {
+	reseed()
+
+	for i := 0; i < b.N; i++ {
+		_, _, _ = ProcessText("I hit for [[2d6+3]], and [[1d20]] to save.")
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// IronswornOutcome is how an Ironsworn action roll turned out.
+type IronswornOutcome int
+
+const (
+	IronswornMiss      IronswornOutcome = iota // The action score beat neither challenge die.
+	IronswornWeakHit                           // The action score beat one challenge die.
+	IronswornStrongHit                         // The action score beat both challenge dice.
+)
+
+/*
+ * String returns the outcome in words.
+ * e.g. "strong hit"
+ */
+func (outcome IronswornOutcome) String() string {
+	switch outcome {
+	case IronswornWeakHit:
+		return "weak hit"
+	case IronswornStrongHit:
+		return "strong hit"
+	default:
+		return "miss"
+	}
+}
+
+// The highest an Ironsworn action score can be, however big the modifiers.
+const ironswornMaxScore = 10
+
+// IronswornRoll holds the details of an Ironsworn action roll.
+type IronswornRoll struct {
+	Action    DiceRoll         // The 1d6 action die, plus modifiers.
+	Score     int              // The action score: the action die's total, capped at 10.
+	Challenge DiceRoll         // The two d10 challenge dice.
+	Outcome   IronswornOutcome // How it turned out.
+	Match     bool             // Whether the challenge dice came up the same, for a dramatic twist.
+}
+
+/*
+ * RollIronsworn makes an Ironsworn action roll: 1d6 plus modifiers (capped at 10) against two d10 challenge dice. Beating both is a
+ *   strong hit, beating one is a weak hit, and beating neither is a miss. Ties go to the challenge dice.
+ * e.g. RollIronsworn(2) // {{1d6+2 6 1 2 [6] 8 ...} 8 {2d10 10 2 0 [5 10] 15 ...} weak hit false}
+ */
+func RollIronsworn(modifier int) (output IronswornRoll, err error) {
+	return defaultRoller.RollIronsworn(modifier)
+}
+
+/*
+ * RollIronsworn is like the package's RollIronsworn, but rolls with the Roller's random source.
+ * e.g. roller.RollIronsworn(2)
+ */
+func (roller *Roller) RollIronsworn(modifier int) (output IronswornRoll, err error) {
+	if output.Action, err = roller.rollParsed(newRollSpec(1, DieSpec{Faces: 6}, modifier)); err != nil {
+		return
+	}
+
+	if output.Action, err = postProcess(output.Action); err != nil {
+		return
+	}
+
+	if output.Challenge, err = roller.rollParsed(newRollSpec(2, DieSpec{Faces: 10}, 0)); err != nil {
+		return
+	}
+
+	if output.Challenge, err = postProcess(output.Challenge); err != nil {
+		return
+	}
+
+	output.Score = min(output.Action.Total, ironswornMaxScore)
+	output.Outcome = ironswornOutcome(output.Score, output.Challenge.Results)
+	output.Match = output.Challenge.Results[0] == output.Challenge.Results[1]
+
+	return
+}
+
+/*
+ * ironswornOutcome counts how many challenge dice an action score beats.
+ */
+func ironswornOutcome(score int, challenge []int) (output IronswornOutcome) {
+	for _, die := range challenge {
+		if score > die {
+			output++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ironswornOutcomeTest struct {
+	score     int
+	challenge []int
+	want      IronswornOutcome
+}
+
+var ironswornOutcomeTests = []ironswornOutcomeTest{
+	{8, []int{5, 7}, IronswornStrongHit},
+	{8, []int{5, 8}, IronswornWeakHit},
+	{8, []int{10, 3}, IronswornWeakHit},
+	{4, []int{4, 4}, IronswornMiss},
+	{10, []int{10, 10}, IronswornMiss},
+	{2, []int{1, 1}, IronswornStrongHit},
+}
+
+// TestIronswornOutcome calls diceroller.ironswornOutcome with many action scores and challenge dice, checking for valid return values.
+func TestIronswornOutcome(t *testing.T) {
+	for _, test := range ironswornOutcomeTests {
+		if output := ironswornOutcome(test.score, test.challenge); output != test.want {
+			t.Errorf("have %v, wanted %v for %d against %v", output, test.want, test.score, test.challenge)
+		}
+	}
+}
+
+type rollIronswornTest struct {
+	modifier int
+	want     IronswornRoll
+}
+
+var rollIronswornTests = []rollIronswornTest{
+	{2, IronswornRoll{
+		DiceRoll{DiscoveredRoll: "1d6+2", Faces: 6, Rolls: 1, Modifier: 2, Results: []int{6}, Total: 8, NaturalTotal: 6}, 8,
+		DiceRoll{DiscoveredRoll: "2d10", Faces: 10, Rolls: 2, Results: []int{5, 10}, Total: 15, NaturalTotal: 15}, IronswornWeakHit, false,
+	}},
+	{0, IronswornRoll{
+		DiceRoll{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Results: []int{4}, Total: 4, NaturalTotal: 4}, 4,
+		DiceRoll{DiscoveredRoll: "2d10", Faces: 10, Rolls: 2, Results: []int{10, 1}, Total: 11, NaturalTotal: 11}, IronswornWeakHit, false,
+	}},
+	{9, IronswornRoll{
+		DiceRoll{DiscoveredRoll: "1d6+9", Faces: 6, Rolls: 1, Modifier: 9, Results: []int{3}, Total: 12, NaturalTotal: 3}, 10,
+		DiceRoll{DiscoveredRoll: "2d10", Faces: 10, Rolls: 2, Results: []int{7, 3}, Total: 10, NaturalTotal: 10}, IronswornStrongHit, false,
+	}},
+}
+
+// TestRollIronsworn calls diceroller.RollIronsworn with many modifiers, checking for valid return values and the capped action score.
+func TestRollIronsworn(t *testing.T) {
+	reseed()
+
+	for _, test := range rollIronswornTests {
+		output, err := RollIronsworn(test.modifier)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+// BenchmarkRollIronsworn benchmarks diceroller.RollIronsworn.
+func BenchmarkRollIronsworn(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollIronsworn(2)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "iter"
+
+/*
+ * Rolls parses the first roll in the input once, and returns an endless sequence of rolls of it, with the package's random source,
+ *   to range over without building a giant slice, e.g. for a simulation or a live display. Each roll is rolled as it's asked for,
+ *   until the loop stops. A bad roll is an error up front, and the sequence stops after an error from a post-processor.
+ * e.g. rolls, _ := Rolls("3d6"); for roll, err := range rolls { ... }
+ */
+func Rolls(input string) (iter.Seq2[DiceRoll, error], error) {
+	return defaultRoller.Rolls(input)
+}
+
+/*
+ * Rolls is like the package's Rolls, but rolls with the Roller's random source.
+ * e.g. rolls, _ := roller.Rolls("3d6"); for roll, err := range rolls { ... }
+ */
+func (roller *Roller) Rolls(input string) (iter.Seq2[DiceRoll, error], error) {
+	compiled, err := roller.Compile(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return compiled.Rolls(), nil
+}
+
+/*
+ * Rolls returns an endless sequence of rolls of the CompiledRoll, each rolled as it's asked for, until the loop stops or a
+ *   post-processor returns an error.
+ * e.g. for roll, err := range fireball.Rolls() { ... }
+ */
+func (compiled *CompiledRoll) Rolls() iter.Seq2[DiceRoll, error] {
+	return func(yield func(DiceRoll, error) bool) {
+		for {
+			dr, err := compiled.Roll()
+			if !yield(dr, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestRolls calls diceroller.Rolls, checking it rolls the same as RollN for as long as it's ranged over, and stops on errors.
+func TestRolls(t *testing.T) {
+	reseed()
+
+	want, _ := RollN("4d6kh3", 10)
+
+	reseed()
+
+	rolls, err := Rolls("4d6kh3")
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	var output []DiceRoll
+	for roll, err := range rolls {
+		if err != nil {
+			t.Fatalf("have err %v", err)
+		}
+
+		if output = append(output, roll); len(output) == len(want) {
+			break
+		}
+	}
+
+	if !reflect.DeepEqual(output, want) {
+		t.Errorf("have %v, wanted %v", output, want)
+	}
+
+	if _, err := NewRoller(WithMaxDice(3)).Rolls("4d6kh3"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+
+	if _, err := Rolls("2d0"); err == nil {
+		t.Error("have no error, wanted one for a bad roll")
+	}
+
+	t.Cleanup(ClearPostProcessors)
+
+	failing := errors.New("failing")
+
+	AddPostProcessor("fail", func(DiceRoll) (DiceRoll, error) { return DiceRoll{}, failing })
+
+	rolls, _ = Rolls("1d6")
+
+	var errs []error
+	for _, err := range rolls {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 1 || !errors.Is(errs[0], failing) {
+		t.Errorf("have %v, wanted one error, then the sequence to stop", errs)
+	}
+}
+
+// BenchmarkRolls benchmarks ranging over diceroller.Rolls.
+func BenchmarkRolls(b *testing.B) {
+	rolls, _ := Rolls("4d6kh3")
+
+	var i int
+	for range rolls {
+		if i++; i >= b.N {
+			break
+		}
+	}
+}
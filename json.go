@@ -0,0 +1,134 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// The version of the JSON a DiceRoll is encoded as. It goes up if the JSON changes in a way older code can't read.
+const DiceRollSchema = 1
+
+// diceRollJSON is how a DiceRoll is encoded as JSON: lower case names which won't change if the struct does, and each die with
+// whether it was dropped.
+type diceRollJSON struct {
+	Schema       int       `json:"schema"`
+	Roll         string    `json:"roll"`
+	Faces        int       `json:"faces"`
+	Rolls        int       `json:"rolls"`
+	Modifier     int       `json:"modifier"`
+	DieModifier  int       `json:"die_modifier,omitempty"`
+	Dice         []dieJSON `json:"dice"`
+	Total        int       `json:"total"`
+	NaturalTotal int       `json:"natural_total"`
+	Unsorted     []int     `json:"unsorted,omitempty"`
+	Sets         []setJSON `json:"sets,omitempty"`
+	NaturalMax   int       `json:"natural_max,omitempty"`
+	NaturalMin   int       `json:"natural_min,omitempty"`
+}
+
+// dieJSON is one die of a DiceRoll, as encoded as JSON.
+type dieJSON struct {
+	Value   int  `json:"value"`
+	Dropped bool `json:"dropped,omitempty"`
+}
+
+// setJSON is one MatchSet of a DiceRoll, as encoded as JSON.
+type setJSON struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+/*
+ * MarshalJSON encodes a DiceRoll as JSON with a stable schema, for bots and web frontends: lower case names, a "schema" version
+ *   (DiceRollSchema), and each die in "dice" with whether it was dropped, in the same order as Results.
+ * e.g. {"schema":1,"roll":"4d6kh3","faces":6,"rolls":4,"modifier":0,"dice":[{"value":6},{"value":3,"dropped":true},...],"total":16,"natural_total":16}
+ */
+func (roll DiceRoll) MarshalJSON() ([]byte, error) {
+	output := diceRollJSON{
+		Schema:       DiceRollSchema,
+		Roll:         roll.DiscoveredRoll,
+		Faces:        roll.Faces,
+		Rolls:        roll.Rolls,
+		Modifier:     roll.Modifier,
+		DieModifier:  roll.DieModifier,
+		Dice:         make([]dieJSON, len(roll.Results)),
+		Total:        roll.Total,
+		NaturalTotal: roll.NaturalTotal,
+		Unsorted:     roll.Unsorted,
+		NaturalMax:   roll.NaturalMax,
+		NaturalMin:   roll.NaturalMin,
+	}
+
+	for i, v := range roll.Results {
+		output.Dice[i] = dieJSON{Value: v, Dropped: slices.Contains(roll.Dropped, i)}
+	}
+
+	for _, set := range roll.Sets {
+		output.Sets = append(output.Sets, setJSON(set))
+	}
+
+	return json.Marshal(output)
+}
+
+/*
+ * UnmarshalJSON decodes a DiceRoll encoded by MarshalJSON. JSON from a newer schema than DiceRollSchema is an error, rather than
+ *   being read wrongly.
+ */
+func (roll *DiceRoll) UnmarshalJSON(data []byte) error {
+	var input diceRollJSON
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+
+	if input.Schema < 1 || input.Schema > DiceRollSchema {
+		return fmt.Errorf("can't decode a dice roll with schema %d, only 1 to %d", input.Schema, DiceRollSchema)
+	}
+
+	output := DiceRoll{
+		DiscoveredRoll: input.Roll,
+		Faces:          input.Faces,
+		Rolls:          input.Rolls,
+		Modifier:       input.Modifier,
+		DieModifier:    input.DieModifier,
+		Total:          input.Total,
+		NaturalTotal:   input.NaturalTotal,
+		Unsorted:       input.Unsorted,
+		NaturalMax:     input.NaturalMax,
+		NaturalMin:     input.NaturalMin,
+	}
+
+	for i, die := range input.Dice {
+		output.Results = append(output.Results, die.Value)
+
+		if die.Dropped {
+			output.Dropped = append(output.Dropped, i)
+		}
+	}
+
+	for _, set := range input.Sets {
+		output.Sets = append(output.Sets, MatchSet(set))
+	}
+
+	*roll = output
+
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestDiceRollJSON encodes rolls as JSON, checking the schema, and that they decode to the same rolls.
+func TestDiceRollJSON(t *testing.T) {
+	reseed()
+
+	rolls, _ := RollDetails("4d6kh3")
+
+	want := `{"schema":1,"roll":"4d6kh3","faces":6,"rolls":4,"modifier":0,"dice":[{"value":6},{"value":3,"dropped":true},{"value":6},{"value":4}],"total":16,"natural_total":16}`
+	if output, err := json.Marshal(rolls[0]); string(output) != want || err != nil {
+		t.Errorf("have %s, err %v, wanted %s", output, err, want)
+	}
+
+	more, _ := RollDetails("2d20kl1s++1-2", "d20adv", "8d6")
+	sets, _ := RollSets("10d10sd")
+	rolls = append(append(append(rolls, more...), sets...), DiceRoll{})
+
+	for _, roll := range rolls {
+		encoded, err := json.Marshal(roll)
+		if err != nil {
+			t.Fatalf("have err %v", err)
+		}
+
+		var decoded DiceRoll
+		if err := json.Unmarshal(encoded, &decoded); err != nil || !reflect.DeepEqual(decoded, roll) {
+			t.Errorf("have %#v, err %v, wanted %#v from %s", decoded, err, roll, encoded)
+		}
+	}
+
+	for _, input := range []string{`{"schema":2,"roll":"1d6"}`, `{"roll":"1d6"}`, `{"schema":"one"}`, `[]`} {
+		var decoded DiceRoll
+		if err := json.Unmarshal([]byte(input), &decoded); err == nil {
+			t.Errorf("have nil error for %s, wanted an error", input)
+		}
+	}
+}
+
+// BenchmarkDiceRollJSON benchmarks encoding and decoding a diceroller.DiceRoll as JSON.
+func BenchmarkDiceRollJSON(b *testing.B) {
+	rolls, _ := RollDetails("4d6kh3+1")
+
+	for i := 0; i < b.N; i++ {
+		encoded, _ := json.Marshal(rolls[0])
+		_ = json.Unmarshal(encoded, &rolls[0])
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"slices"
+	"sort"
+)
+
+/*
+ * keepDice drops all but the highest ('kh'), lowest ('kl') or middle ('km') count dice from a DiceRoll, and takes them off the total.
+ */
+func keepDice(output *DiceRoll, keep string, count int) {
+	output.Dropped = dropIndexes(output.Results, keep, count)
+
+	for _, i := range output.Dropped {
+		output.Total -= output.Results[i]
+	}
+}
+
+/*
+ * keptResults returns the results of a DiceRoll which count towards the total, i.e. all but the dropped dice.
+ */
+func keptResults(input DiceRoll) (output []int) {
+	for i, v := range input.Results {
+		if !slices.Contains(input.Dropped, i) {
+			output = append(output, v)
+		}
+	}
+
+	return
+}
+
+/*
+ * dropIndexes returns the indexes of the values dropped when keeping the highest ('kh'), lowest ('kl') or middle ('km') count of them.
+ * Where values tie, the ones rolled first are dropped first. When keeping the middle dice and the rest can't be split evenly
+ *   either side, the extra die is dropped from the bottom.
+ */
+func dropIndexes(values []int, keep string, count int) (dropped []int) {
+	drop := len(values) - count
+	if drop <= 0 {
+		return
+	}
+
+	// Indexes of the values, lowest value first.
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+
+	switch keep {
+	case "kh":
+		dropped = order[:drop]
+	case "kl":
+		dropped = order[count:]
+	case "km":
+		low := drop - drop/2
+		dropped = append(order[:low:low], order[low+count:]...)
+	}
+
+	dropped = slices.Clone(dropped)
+	slices.Sort(dropped)
+
+	return
+}
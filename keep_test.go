@@ -0,0 +1,119 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type dropIndexesTest struct {
+	values []int
+	keep   string
+	count  int
+	want   []int
+}
+
+var dropIndexesTests = []dropIndexesTest{
+	{[]int{3, 1, 4, 1}, "kh", 3, []int{1}},
+	{[]int{3, 1, 4, 1}, "kh", 1, []int{0, 1, 3}},
+	{[]int{3, 1, 4, 1}, "kl", 2, []int{0, 2}},
+	{[]int{15, 2, 9}, "km", 1, []int{0, 1}},
+	{[]int{6, 1, 4, 3}, "km", 1, []int{0, 1, 3}},
+	{[]int{6, 1, 4, 3}, "km", 2, []int{0, 1}},
+	{[]int{6, 1}, "kh", 2, nil},
+	{[]int{6, 1}, "kh", 5, nil},
+}
+
+// TestDropIndexes calls diceroller.dropIndexes with many sets of values, checking for valid return values.
+func TestDropIndexes(t *testing.T) {
+	for _, test := range dropIndexesTests {
+		output := dropIndexes(test.values, test.keep, test.count)
+
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %v, wanted %v for %v %s%d", output, test.want, test.values, test.keep, test.count)
+		}
+	}
+}
+
+var rollDetailsKeepTests = []rollDetailsTest{
+	{[]string{"4d6kh3", "3d20km1", "2d8KL1+1", "4d6kh3s"}, []DiceRoll{
+		{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{6, 3, 6, 4}, Total: 16, NaturalTotal: 16, Dropped: []int{1}},
+		{DiscoveredRoll: "3d20km1", Faces: 20, Rolls: 3, Modifier: 0, Results: []int{20, 1, 8}, Total: 8, NaturalTotal: 8, Dropped: []int{0, 1}},
+		{DiscoveredRoll: "2d8KL1+1", Faces: 8, Rolls: 2, Modifier: 1, Results: []int{7, 8}, Total: 8, NaturalTotal: 7, Dropped: []int{1}},
+		{DiscoveredRoll: "4d6kh3s", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{1, 2, 3, 6}, Total: 11, NaturalTotal: 11, Unsorted: []int{2, 1, 6, 3}, Dropped: []int{0}},
+	}},
+}
+
+// TestRollDetailsKeep calls diceroller.RollDetails with dice roll strings which keep some dice (e.g. '4d6kh3'), checking for valid return values.
+func TestRollDetailsKeep(t *testing.T) {
+	reseed()
+
+	for _, test := range rollDetailsKeepTests {
+		output, err := RollDetails(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+var rollDetailsAdvantageTests = []rollDetailsTest{
+	{[]string{"d20adv+5", "1d20dis", "2d6adv"}, []DiceRoll{
+		{DiscoveredRoll: "d20adv+5", Faces: 20, Rolls: 2, Modifier: 5, Results: []int{19, 10}, Total: 24, NaturalTotal: 19, Dropped: []int{1}},
+		{DiscoveredRoll: "1d20dis", Faces: 20, Rolls: 2, Modifier: 0, Results: []int{19, 11}, Total: 11, NaturalTotal: 11, Dropped: []int{0}},
+		{DiscoveredRoll: "2d6adv", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{6, 1, 3, 4}, Total: 10, NaturalTotal: 10, Dropped: []int{1, 2}},
+	}},
+}
+
+// TestRollDetailsAdvantage calls diceroller.RollDetails with advantage and disadvantage rolls (e.g. 'd20adv+5'), checking for valid return values.
+func TestRollDetailsAdvantage(t *testing.T) {
+	reseed()
+
+	for _, test := range rollDetailsAdvantageTests {
+		output, err := RollDetails(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+var prettifyKeepTests = []prettifyTest{
+	{[]DiceRoll{{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Results: []int{6, 3, 6, 4}, Total: 16, NaturalTotal: 16, Dropped: []int{1}}}, []string{"6 + ~~3~~ + 6 + 4 = 16"}},
+	{[]DiceRoll{{DiscoveredRoll: "2d20kh1+5", Faces: 20, Rolls: 2, Modifier: 5, Results: []int{12, 18}, Total: 23, NaturalTotal: 18, Dropped: []int{0}}}, []string{"~~12~~ + 18 (+5) = 23"}},
+}
+
+// TestPrettifyKeep calls diceroller.Prettify with DiceRoll structs which have dropped dice, checking for valid return values.
+func TestPrettifyKeep(t *testing.T) {
+	for _, test := range prettifyKeepTests {
+		output := Prettify(test.got)
+
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %v, wanted %v", output, test.want)
+		}
+	}
+}
+
+// BenchmarkRollDetailsKeep benchmarks diceroller.RollDetails with dice roll strings which keep some dice.
+func BenchmarkRollDetailsKeep(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollDetails(rollDetailsKeepTests[0].got...)
+	}
+}
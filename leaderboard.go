@@ -0,0 +1,107 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"sort"
+	"time"
+)
+
+// LeaderboardEntry is one user's place on a leaderboard.
+type LeaderboardEntry struct {
+	User  string  // Who.
+	Value float64 // Their score, e.g. how many rolls they made.
+}
+
+// Leaderboards holds the leaderboards for a roll history, each sorted with the highest score first.
+type Leaderboards struct {
+	MostRolls         []LeaderboardEntry // How many rolls each user made.
+	HighestD20Average []LeaderboardEntry // The average of each user's d20s, for users who rolled any.
+	MostNaturalOnes   []LeaderboardEntry // How many d20s came up 1 for each user, for users who rolled any.
+}
+
+/*
+ * NewLeaderboards takes in a roll history and makes leaderboards from the rolls made from 'from' up to (but not including) 'to'.
+ * A zero time leaves that end of the window open, so NewLeaderboards(history, time.Time{}, time.Time{}) covers everything. Dropped dice don't count.
+ */
+func NewLeaderboards(history []Record, from, to time.Time) (output Leaderboards) {
+	var (
+		rolls = map[string]float64{}
+		d20s  = map[string][]int{}
+		ones  = map[string]float64{}
+	)
+
+	for _, record := range history {
+		if (!from.IsZero() && record.Time.Before(from)) || (!to.IsZero() && !record.Time.Before(to)) {
+			continue
+		}
+
+		rolls[record.User]++
+
+		if record.Roll.Faces != 20 {
+			continue
+		}
+
+		for _, v := range keptResults(record.Roll) {
+			d20s[record.User] = append(d20s[record.User], v)
+
+			if v == 1 {
+				ones[record.User]++
+			}
+		}
+	}
+
+	averages := map[string]float64{}
+	for user, results := range d20s {
+		var total int
+		for _, v := range results {
+			total += v
+		}
+
+		averages[user] = float64(total) / float64(len(results))
+
+		// Users who rolled d20s but never a 1 still get a place on the board.
+		ones[user] += 0
+	}
+
+	output.MostRolls = leaderboard(rolls)
+	output.HighestD20Average = leaderboard(averages)
+	output.MostNaturalOnes = leaderboard(ones)
+
+	return
+}
+
+/*
+ * leaderboard turns a map of users' scores into a leaderboard, highest score first, and alphabetically by user for equal scores.
+ */
+func leaderboard(scores map[string]float64) (output []LeaderboardEntry) {
+	for user, value := range scores {
+		output = append(output, LeaderboardEntry{User: user, Value: value})
+	}
+
+	sort.Slice(output, func(a, b int) bool {
+		if output[a].Value != output[b].Value {
+			return output[a].Value > output[b].Value
+		}
+
+		return output[a].User < output[b].User
+	})
+
+	return
+}
@@ -0,0 +1,73 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	leaderboardStart   = time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)
+	leaderboardHistory = []Record{
+		{Time: leaderboardStart, User: "alice", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{1}, Total: 1}},
+		{Time: leaderboardStart.Add(time.Minute), User: "bob", Roll: DiceRoll{DiscoveredRoll: "2d20kh1", Faces: 20, Rolls: 2, Results: []int{1, 18}, Total: 18, Dropped: []int{0}}},
+		{Time: leaderboardStart.Add(2 * time.Minute), User: "alice", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{15}, Total: 15}},
+		{Time: leaderboardStart.Add(3 * time.Minute), User: "carol", Roll: DiceRoll{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Results: []int{1, 1}, Total: 2}},
+		{Time: leaderboardStart.Add(time.Hour), User: "bob", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{1}, Total: 1}},
+	}
+)
+
+type leaderboardsTest struct {
+	from, to time.Time
+	want     Leaderboards
+}
+
+var leaderboardsTests = []leaderboardsTest{
+	{time.Time{}, time.Time{}, Leaderboards{
+		MostRolls:         []LeaderboardEntry{{"alice", 2}, {"bob", 2}, {"carol", 1}},
+		HighestD20Average: []LeaderboardEntry{{"bob", 9.5}, {"alice", 8}},
+		MostNaturalOnes:   []LeaderboardEntry{{"alice", 1}, {"bob", 1}},
+	}},
+	{leaderboardStart.Add(time.Minute), leaderboardStart.Add(time.Hour), Leaderboards{
+		MostRolls:         []LeaderboardEntry{{"alice", 1}, {"bob", 1}, {"carol", 1}},
+		HighestD20Average: []LeaderboardEntry{{"bob", 18}, {"alice", 15}},
+		MostNaturalOnes:   []LeaderboardEntry{{"alice", 0}, {"bob", 0}},
+	}},
+	{leaderboardStart.Add(2 * time.Hour), time.Time{}, Leaderboards{}},
+}
+
+// TestNewLeaderboards calls diceroller.NewLeaderboards with a history and many time windows, checking for valid return values.
+func TestNewLeaderboards(t *testing.T) {
+	for _, test := range leaderboardsTests {
+		output := NewLeaderboards(leaderboardHistory, test.from, test.to)
+
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %v, wanted %v", output, test.want)
+		}
+	}
+}
+
+// BenchmarkNewLeaderboards benchmarks diceroller.NewLeaderboards.
+func BenchmarkNewLeaderboards(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewLeaderboards(leaderboardHistory, time.Time{}, time.Time{})
+	}
+}
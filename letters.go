@@ -0,0 +1,129 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+ * WithDiceLetters makes a Roller accept other letters for the 'd' in its rolls as well, e.g. 'W' for the German 'Würfel' ('2W6') or
+ *   't' for the Swedish 'tärning' ('2t6'), so chat bots for other languages can take their own notation. The letters match either
+ *   case, only between a roll's numbers, and the discovered roll keeps the letter it was written with. They work with other
+ *   notations too, e.g. WithRoll20's '[[2W6]]'.
+ * e.g. NewRoller(WithDiceLetters('W')).RollDetails("2W6+1") // [{2W6+1 6 2 1 [4 3] 8 ...}]
+ */
+func WithDiceLetters(letters ...rune) RollerOption {
+	return func(roller *Roller) {
+		roller.letters = append(roller.letters, letters...)
+	}
+}
+
+/*
+ * parseRoll is like the package's parseRoll, but takes the Roller's other letters for the 'd' as well.
+ */
+func (roller *Roller) parseRoll(input string) (rollSpec, error) {
+	return roller.parseWith(input, parseRoll)
+}
+
+/*
+ * parseWith parses one roll with the given notation's parser, taking the Roller's other letters for the 'd' as well.
+ */
+func (roller *Roller) parseWith(input string, parse func(string) (rollSpec, error)) (spec rollSpec, err error) {
+	if len(roller.letters) == 0 {
+		return parse(input)
+	}
+
+	translated, written := roller.translate(input)
+
+	// Say where no roll was found as it was written, rather than translated.
+	if spec, err = parse(translated); errors.Is(err, ErrNoRollFound) {
+		return spec, fmt.Errorf("%w in %q", ErrNoRollFound, input)
+	} else if err != nil {
+		return
+	}
+
+	spec.discovered = written(spec.discovered)
+
+	return
+}
+
+/*
+ * translate replaces the Roller's other letters for the 'd' in the input with 'd', as translateLetters does. It also returns a function
+ *   which gives part of the translated input back as it was written, e.g. a discovered roll.
+ */
+func (roller *Roller) translate(input string) (string, func(string) string) {
+	if len(roller.letters) == 0 {
+		return input, func(part string) string { return part }
+	}
+
+	translated, origin := translateLetters(input, roller.letters)
+
+	return translated, func(part string) string {
+		// The part is found in the same place in the translated input. A notation may add up its modifiers, e.g. Foundry's, so only
+		//   as much of the part as is in the input is given back as it was written.
+		for end := len(part); end > 0; end-- {
+			if start := strings.Index(translated, part[:end]); start >= 0 {
+				return input[origin[start]:origin[start+end]] + part[end:]
+			}
+		}
+
+		return part
+	}
+}
+
+/*
+ * translateLetters replaces the given letters with 'd' wherever they could be the 'd' of a roll: followed by a digit, and not part of
+ *   a word. It also returns where each byte of the output came from in the input, with one more for the end of the input.
+ */
+func translateLetters(input string, letters []rune) (string, []int) {
+	var (
+		output   strings.Builder
+		origin   = make([]int, 0, len(input)+1)
+		previous rune
+	)
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		write := input[i : i+size]
+
+		isLetter := slices.ContainsFunc(letters, func(letter rune) bool {
+			return unicode.ToLower(letter) == unicode.ToLower(r)
+		})
+
+		if isLetter && i+size < len(input) && isDigit(input[i+size]) && !unicode.IsLetter(previous) {
+			write = "d"
+		}
+
+		for range len(write) {
+			origin = append(origin, i)
+		}
+
+		output.WriteString(write)
+		previous = r
+		i += size
+	}
+
+	return output.String(), append(origin, len(input))
+}
@@ -0,0 +1,112 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type diceLettersTest struct {
+	input          string
+	wantDiscovered string
+	wantResults    []int
+	wantTotal      int
+}
+
+// With WithSeed(42, 1024), d6s come up 6, 3, 6, 4 and d20s come up 19, 10.
+var diceLettersTests = []diceLettersTest{
+	{"2W6+1", "2W6+1", []int{6, 3}, 10},
+	{"Angriff: 1w20+5", "1w20+5", []int{19}, 24},
+	{"Würfel: 4W6kh3", "4W6kh3", []int{6, 3, 6, 4}, 16},
+	{"W20adv", "W20adv", []int{19, 10}, 19},
+	{"3t6", "3t6", []int{6, 3, 6}, 15},
+	{"2к6s", "2к6s", []int{3, 6}, 9},
+	{"2d6", "2d6", []int{6, 3}, 9},
+}
+
+// TestDiceLetters rolls rolls with other letters for the 'd' with a diceroller.Roller made with WithDiceLetters, checking for valid
+// return values, and that the letters are only taken where they're a roll's 'd'.
+func TestDiceLetters(t *testing.T) {
+	for _, test := range diceLettersTests {
+		output, err := NewRoller(WithSeed(42, 1024), WithDiceLetters('W', 't'), WithDiceLetters('к')).RollDetails(test.input)
+		if err != nil {
+			t.Fatalf("have err %v for %q", err, test.input)
+		}
+
+		if roll := output[0]; roll.DiscoveredRoll != test.wantDiscovered || !reflect.DeepEqual(roll.Results, test.wantResults) || roll.Total != test.wantTotal {
+			t.Errorf("have %+v, wanted %q %v totalling %d", roll, test.wantDiscovered, test.wantResults, test.wantTotal)
+		}
+	}
+
+	roller := NewRoller(WithDiceLetters('W', 't'), WithMaxTotalDice(3))
+
+	for _, input := range []string{"Zwei W6", "tw6", "W6"} {
+		if _, err := roller.RollOne(input); !errors.Is(err, ErrNoRollFound) || !strings.Contains(err.Error(), input) {
+			t.Errorf("have err %v for %q, wanted %v", err, input, ErrNoRollFound)
+		}
+	}
+
+	if _, err := roller.Roll("2W6", "2W6"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+
+	if _, err := NewRoller().RollOne("2W6"); !errors.Is(err, ErrNoRollFound) {
+		t.Errorf("have err %v without WithDiceLetters, wanted %v", err, ErrNoRollFound)
+	}
+}
+
+// TestDiceLettersNotations rolls rolls with other letters for the 'd' in Roll20's and Foundry's notations, checking the letters are
+// taken there too, and the discovered roll keeps them.
+func TestDiceLettersNotations(t *testing.T) {
+	for _, test := range []struct {
+		option RollerOption
+		diceLettersTest
+	}{
+		{WithRoll20(), diceLettersTest{"[[2w6]]", "2w6", []int{6, 3}, 9}},
+		{WithRoll20(), diceLettersTest{"[[W20cs>19+5]]", "W20cs>19+5", []int{19}, 24}},
+		{WithRoll20(), diceLettersTest{"[[{2W6,1d6}kh1]]", "{2W6,1d6}kh1", []int{9, 6}, 9}},
+		{WithFoundry(map[string]any{"n": 2}), diceLettersTest{"(@n)W6 + 1 + 2", "2W6+3", []int{6, 3}, 12}},
+		{WithFoundry(nil), diceLettersTest{"4w6kh3", "4w6kh3", []int{6, 3, 6, 4}, 16}},
+	} {
+		output, err := NewRoller(WithSeed(42, 1024), WithDiceLetters('W'), test.option).RollDetails(test.input)
+		if err != nil {
+			t.Fatalf("have err %v for %q", err, test.input)
+		}
+
+		if roll := output[0]; roll.DiscoveredRoll != test.wantDiscovered || !reflect.DeepEqual(roll.Results, test.wantResults) || roll.Total != test.wantTotal {
+			t.Errorf("have %+v, wanted %q %v totalling %d", roll, test.wantDiscovered, test.wantResults, test.wantTotal)
+		}
+	}
+
+	if _, err := NewRoller(WithDiceLetters('W'), WithRoll20(), WithMaxTotalDice(3)).Roll("[[2W6]]", "[[2W6]]"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+}
+
+// BenchmarkDiceLetters benchmarks rolling with other letters for the 'd'.
+func BenchmarkDiceLetters(b *testing.B) {
+	roller := NewRoller(WithDiceLetters('W'))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollOne("Angriff: 1W20+5")
+	}
+}
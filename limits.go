@@ -0,0 +1,107 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "fmt"
+
+// limits caps how big a Roller's rolls can be, so e.g. a bot can't be asked to roll '99999d99999'. A zero limit is no limit.
+type limits struct {
+	maxDice      int // The most dice one roll can roll, counting both dice of advantage.
+	maxFaces     int // The most faces a die can have.
+	maxTotalDice int // The most dice one call can roll, across all its rolls.
+}
+
+/*
+ * WithMaxDice limits how many dice a Roller will roll in one roll, e.g. for a bot taking rolls from chat. Advantage and disadvantage
+ *   count both dice. Rolls over the limit return ErrLimitExceeded, and aren't rolled.
+ * e.g. NewRoller(WithMaxDice(100))
+ */
+func WithMaxDice(max int) RollerOption {
+	return func(roller *Roller) {
+		roller.limits.maxDice = max
+	}
+}
+
+/*
+ * WithMaxFaces limits how many faces a Roller's dice can have. Rolls over the limit return ErrLimitExceeded, and aren't rolled.
+ * e.g. NewRoller(WithMaxFaces(1000))
+ */
+func WithMaxFaces(max int) RollerOption {
+	return func(roller *Roller) {
+		roller.limits.maxFaces = max
+	}
+}
+
+/*
+ * WithMaxTotalDice limits how many dice a Roller will roll in one call, across all the rolls given to it, e.g. Roll("50d6", "60d6")
+ *   is 110 dice, counted in the Roller's notation, e.g. Roll20's, whose groups count all their rolls' dice. If they're over the limit
+ *   it returns ErrLimitExceeded, and none of them are rolled.
+ * e.g. NewRoller(WithMaxTotalDice(500))
+ */
+func WithMaxTotalDice(max int) RollerOption {
+	return func(roller *Roller) {
+		roller.limits.maxTotalDice = max
+	}
+}
+
+/*
+ * check returns ErrLimitExceeded if one roll is over the limits.
+ */
+func (limits limits) check(spec rollSpec) error {
+	if limits.maxDice > 0 && spec.rolls > limits.maxDice {
+		return fmt.Errorf("%w: %q rolls %d dice, the most is %d", ErrLimitExceeded, spec.discovered, spec.rolls, limits.maxDice)
+	}
+
+	if limits.maxFaces > 0 && spec.die.Faces > limits.maxFaces {
+		return fmt.Errorf("%w: %q has %d faces, the most is %d", ErrLimitExceeded, spec.discovered, spec.die.Faces, limits.maxFaces)
+	}
+
+	return nil
+}
+
+/*
+ * checkTotal returns ErrLimitExceeded if all the rolls together are over the limit on total dice, counted with the given function.
+ *   Rolls which can't be counted are skipped, to be reported when they're rolled.
+ */
+func (limits limits) checkTotal(input []string, count func(string) (int, error)) error {
+	if limits.maxTotalDice <= 0 {
+		return nil
+	}
+
+	var total int
+
+	for _, in := range input {
+		if dice, err := count(in); err == nil {
+			total += dice
+		}
+	}
+
+	return limits.checkTotalDice(total)
+}
+
+/*
+ * checkTotalDice returns ErrLimitExceeded if the given number of dice, rolled together, is over the limit on total dice.
+ */
+func (limits limits) checkTotalDice(total int) error {
+	if limits.maxTotalDice > 0 && total > limits.maxTotalDice {
+		return fmt.Errorf("%w: %d dice rolled together, the most is %d", ErrLimitExceeded, total, limits.maxTotalDice)
+	}
+
+	return nil
+}
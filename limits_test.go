@@ -0,0 +1,95 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+type limitsTest struct {
+	options []RollerOption
+	input   []string
+	wantErr bool
+}
+
+var limitsTests = []limitsTest{
+	{nil, []string{"9999d9999"}, false},
+	{[]RollerOption{WithMaxDice(100)}, []string{"100d6"}, false},
+	{[]RollerOption{WithMaxDice(100)}, []string{"101d6"}, true},
+	{[]RollerOption{WithMaxDice(3)}, []string{"d20adv"}, false},
+	{[]RollerOption{WithMaxDice(3)}, []string{"2d20adv"}, true},
+	{[]RollerOption{WithMaxFaces(100)}, []string{"1d100"}, false},
+	{[]RollerOption{WithMaxFaces(100)}, []string{"1d1000"}, true},
+	{[]RollerOption{WithMaxTotalDice(100)}, []string{"50d6", "50d6"}, false},
+	{[]RollerOption{WithMaxTotalDice(100)}, []string{"50d6", "51d6"}, true},
+	{[]RollerOption{WithMaxDice(10), WithMaxFaces(10), WithMaxTotalDice(15)}, []string{"10d10", "5d10"}, false},
+	{[]RollerOption{WithRoll20(), WithMaxTotalDice(10)}, []string{"[[9d6]]", "[[d6]]"}, false},
+	{[]RollerOption{WithRoll20(), WithMaxTotalDice(10)}, []string{"[[9d6]]", "[[2d6]]"}, true},
+	{[]RollerOption{WithRoll20(), WithMaxTotalDice(10)}, []string{"[[{4d6,6d6}kh1]]"}, false},
+	{[]RollerOption{WithRoll20(), WithMaxTotalDice(10), WithMaxDice(10)}, []string{"[[{9d6,9d6,9d6,9d6}kh1]]"}, true},
+	{[]RollerOption{WithFoundry(map[string]any{"n": 3}), WithMaxTotalDice(10)}, []string{"(@n)d6", "(@n)d6", "(@n)d6"}, false},
+	{[]RollerOption{WithFoundry(map[string]any{"n": 9}), WithMaxTotalDice(10)}, []string{"(@n)d6", "(@n)d6", "(@n)d6"}, true},
+	{[]RollerOption{WithNaturalLanguage(), WithMaxTotalDice(10)}, []string{"roll six d six", "roll five d six"}, true},
+}
+
+// TestLimits calls a diceroller.Roller's Roll methods with limits, checking rolls over them return ErrLimitExceeded.
+func TestLimits(t *testing.T) {
+	for _, test := range limitsTests {
+		roller := NewRoller(append(test.options, WithSeed(42, 1024))...)
+
+		for _, err := range []error{first(roller.Roll(test.input...)), first(roller.RollTotal(test.input...)), first(roller.RollDetails(test.input...))} {
+			if errors.Is(err, ErrLimitExceeded) != test.wantErr || (err != nil && !test.wantErr) {
+				t.Errorf("have err %v, wanted a limit error %t for %q", err, test.wantErr, test.input)
+			}
+		}
+	}
+
+	roller := NewRoller(WithMaxDice(2), WithMaxFaces(6))
+	if _, err := roller.RollDie(DieSpec{Faces: 6}, 3, 0); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+
+	if _, err := roller.RollOne("1d8"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+
+	// A Roll20 group's rolls count together, even as one roll.
+	if _, err := NewRoller(WithRoll20(), WithMaxTotalDice(10)).RollOne("[[{9d6,9d6}kh1]]"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+
+	// Options for one roll can change the limits for that roll only.
+	if _, err := roller.RollOneWith("1d8", WithMaxFaces(8)); err != nil {
+		t.Errorf("have err %v, wanted none", err)
+	}
+
+	if _, err := roller.RollOne("1d8"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+}
+
+// BenchmarkLimits benchmarks a diceroller.Roller with limits.
+func BenchmarkLimits(b *testing.B) {
+	roller := NewRoller(WithMaxDice(100), WithMaxFaces(100), WithMaxTotalDice(200))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.Roll("4d6kh3", "1d20")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownOdds is returned by Luck, along with the luck of the other rolls, if it skipped rolls it can't work out the odds of.
+var ErrUnknownOdds = errors.New("can't work out the odds of the roll")
+
+/*
+ * Luck takes in a roll history and works out how lucky a user has been in a session: the sum of how many standard deviations above (or,
+ *   if negative, below) the average each of their rolls came out. An empty user or session matches everyone or every session. Rolls
+ *   of a weighted die use the record's Die. Rolls it can't work out the odds of, such as those in Roll20's or Foundry's notation, are
+ *   skipped, and it returns ErrUnknownOdds with the luck of the rest.
+ * e.g. Luck(history, "alice", "") // 2.5: a little luckier than average, over all of Alice's sessions.
+ */
+func Luck(history []Record, user, session string) (output float64, err error) {
+	var (
+		dists   = map[string]distribution{} // Most histories are the same few rolls over and over, so only work out each distribution once.
+		skipped int
+	)
+
+	for _, record := range history {
+		if !record.matches(user, session) {
+			continue
+		}
+
+		spec, ok := record.spec()
+		if !ok {
+			skipped++
+			continue
+		}
+
+		key := fmt.Sprint(spec.discovered, spec.die.Weights)
+
+		dist, ok := dists[key]
+		if !ok {
+			if dist, err = spec.distribution(); err != nil {
+				return
+			}
+
+			dists[key] = dist
+		}
+
+		// A roll which can only come out one way, e.g. 1d1, is neither lucky nor unlucky.
+		if stddev := dist.stddev(); stddev > 0 {
+			output += (float64(record.Roll.Total) - dist.mean()) / stddev
+		}
+	}
+
+	if skipped > 0 {
+		err = fmt.Errorf("%w: skipped %d rolls", ErrUnknownOdds, skipped)
+	}
+
+	return
+}
+
+/*
+ * spec works out what a record rolled from its discovered roll, and its Die if it's weighted, or returns false if it can't: if the roll
+ *   isn't in this package's notation, or the roll doesn't match it, e.g. a house rule changed its modifier.
+ */
+func (record Record) spec() (spec rollSpec, ok bool) {
+	roll := record.Roll
+
+	spec, err := parseWholeRoll(roll.DiscoveredRoll)
+	if err != nil || spec.die.Faces != roll.Faces || spec.rolls != roll.Rolls || spec.modifier != roll.Modifier ||
+		spec.dieModifier != roll.DieModifier {
+		return spec, false
+	}
+
+	if record.Die != nil {
+		if record.Die.Faces != spec.die.Faces {
+			return spec, false
+		}
+
+		spec.die = *record.Die
+	}
+
+	return spec, true
+}
@@ -0,0 +1,113 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+var luckHistory = []Record{
+	{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{20}, Total: 20}},
+	{User: "alice", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Results: []int{1}, Total: 1}},
+	{User: "alice", Session: "two", Roll: DiceRoll{DiscoveredRoll: "2d6+2", Faces: 6, Rolls: 2, Modifier: 2, Results: []int{6, 6}, Total: 14}},
+	{User: "bob", Session: "one", Roll: DiceRoll{DiscoveredRoll: "1d1", Faces: 1, Rolls: 1, Results: []int{1}, Total: 1}},
+	{User: "bob", Session: "two", Roll: DiceRoll{DiscoveredRoll: "1d4", Faces: 4, Rolls: 1, Results: []int{1}, Total: 1}},
+}
+
+type luckTest struct {
+	user, session string
+	want          float64
+}
+
+var luckTests = []luckTest{
+	{"alice", "one", 0},
+	{"alice", "two", 5 / math.Sqrt(35.0/6)},
+	{"alice", "", 5 / math.Sqrt(35.0/6)},
+	{"bob", "one", 0},
+	{"bob", "", -1.5 / math.Sqrt(1.25)},
+	{"", "two", 5/math.Sqrt(35.0/6) - 1.5/math.Sqrt(1.25)},
+	{"carol", "", 0},
+}
+
+// TestLuck calls diceroller.Luck with a history and many users and sessions, checking for valid return values.
+func TestLuck(t *testing.T) {
+	for _, test := range luckTests {
+		output, err := Luck(luckHistory, test.user, test.session)
+
+		if math.Abs(output-test.want) > 1e-9 || err != nil {
+			t.Errorf("have %v, wanted %v for %q %q, err %v", output, test.want, test.user, test.session, err)
+		}
+	}
+
+	if _, err := Luck([]Record{{Roll: DiceRoll{DiscoveredRoll: "nonsense"}}}, "", ""); !errors.Is(err, ErrUnknownOdds) {
+		t.Errorf("have err %v for a record with no roll, wanted ErrUnknownOdds", err)
+	}
+}
+
+// TestLuckSkips calls diceroller.Luck with rolls it can't work out the odds of, checking they're skipped and the rest still count.
+func TestLuckSkips(t *testing.T) {
+	history := append([]Record{
+		{Roll: DiceRoll{DiscoveredRoll: "{2d20}kh1", Faces: 0, Rolls: 1, Results: []int{30}, Total: 30}},
+		{Roll: DiceRoll{DiscoveredRoll: "4d6x", Faces: 6, Rolls: 4, Results: []int{6, 6, 1, 2, 3}, Total: 18}},
+		{Roll: DiceRoll{DiscoveredRoll: "1d20cs>19", Faces: 20, Rolls: 1, Results: []int{20}, Total: 20}},
+		{Roll: DiceRoll{DiscoveredRoll: "1d20", Faces: 20, Rolls: 1, Modifier: 5, Results: []int{1}, Total: 6}},
+	}, luckHistory...)
+
+	output, err := Luck(history, "", "")
+	if want := 5/math.Sqrt(35.0/6) - 1.5/math.Sqrt(1.25); math.Abs(output-want) > 1e-9 || !errors.Is(err, ErrUnknownOdds) {
+		t.Errorf("have %v, wanted %v, err %v, wanted ErrUnknownOdds", output, want, err)
+	}
+}
+
+// TestLuckWeighted calls diceroller.Luck with rolls of a weighted die from a Roller's History, checking its odds are used.
+func TestLuckWeighted(t *testing.T) {
+	roller := NewRoller(WithSeed(42, 1024), WithHistory(0))
+	loaded, _ := NewWeightedDie(1, 3)
+
+	roll, err := roller.RollDie(loaded, 1, 0)
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	records := roller.History().All()
+	if len(records) != 1 || records[0].Die == nil || !reflect.DeepEqual(*records[0].Die, loaded) {
+		t.Fatalf("have %v, wanted one record of the loaded die", records)
+	}
+
+	// The die rolls 2 three times in four: an average of 1.75, and a standard deviation of √0.1875.
+	output, err := Luck(records, "", "")
+	if want := (float64(roll.Total) - 1.75) / math.Sqrt(0.1875); math.Abs(output-want) > 1e-9 || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", output, want, err)
+	}
+
+	records[0].Die = &DieSpec{Faces: 3, Weights: []int{1, 1, 1}}
+	if _, err := Luck(records, "", ""); !errors.Is(err, ErrUnknownOdds) {
+		t.Errorf("have err %v for a die which doesn't match the roll, wanted ErrUnknownOdds", err)
+	}
+}
+
+// BenchmarkLuck benchmarks diceroller.Luck.
+func BenchmarkLuck(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Luck(luckHistory, "", "")
+	}
+}
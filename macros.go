@@ -0,0 +1,525 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// Macro names start with a letter, and can then have letters, numbers, underscores and hyphens, e.g. 'sneak-attack'.
+	macroNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+	// A reference to a macro within other text, e.g. 'roll @attack twice', but not an email address such as 'gm@example.com'.
+	macroReferenceRegex = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.])@([A-Za-z][A-Za-z0-9_-]*)`)
+
+	// ErrMacroPermission is returned when a user isn't allowed to define or overwrite a macro.
+	ErrMacroPermission = errors.New("not allowed to change that macro")
+
+	// ErrMacroNotFound is returned when expanding a macro, or a macro it references, which doesn't exist.
+	ErrMacroNotFound = errors.New("macro not found")
+
+	// ErrMacroCycle is returned when expanding a macro which references itself, directly or through other macros.
+	ErrMacroCycle = errors.New("macro references itself")
+
+	// ErrMacroDepth is returned when expanding a macro means following more than 8 references deep.
+	ErrMacroDepth = errors.New("macro references are nested too deeply")
+)
+
+// How many references deep a macro can go, e.g. 'attack' referencing 'damage' referencing 'sneak' is 2 deep.
+const maxMacroDepth = 8
+
+// MacroScope says where a macro can be used. The zero value is the global scope, for macros everyone can use everywhere.
+type MacroScope struct {
+	Room string `json:"room,omitempty"` // If set, the macro can only be used in this room (or channel, or game).
+	User string `json:"user,omitempty"` // If set, the macro belongs to this user, and only they can use it. With Room, only in that room.
+}
+
+// Macro is a named roll, e.g. 'attack' for '1d20+7'. It can also be a comma-separated list of rolls and references to other macros,
+// as '@name', e.g. 'attack' for '@to-hit, @damage'.
+type Macro struct {
+	Name  string     `json:"name"`            // The macro's name.
+	Roll  string     `json:"roll"`            // The roll (or rolls and references) it stands for.
+	Owner string     `json:"owner,omitempty"` // Who defined it, or empty if it was set or imported directly, so only Set can overwrite it.
+	Scope MacroScope `json:"scope"`           // Where it can be used.
+}
+
+// MacroPermission decides whether a user may define a macro. If a macro of that name already exists in that scope, existing is it.
+type MacroPermission func(user string, macro Macro, existing *Macro) bool
+
+// MacroStore holds named rolls, e.g. 'attack' for '1d20+7', in the global scope, per room and per user. It's safe for concurrent use,
+// and the zero value is an empty store.
+type MacroStore struct {
+	// Optional check for Define. If nil, users can always define their own user macros, and can define new room and global macros,
+	//   but only the owner of a room or global macro can overwrite it, so players can't clobber the GM's macros. Macros with no
+	//   owner, from Set or Import, can't be overwritten with Define.
+	Permission MacroPermission
+
+	mu     sync.RWMutex
+	macros map[MacroScope]map[string]Macro
+}
+
+// ImportError describes a line which couldn't be imported into a MacroStore.
+type ImportError struct {
+	Line int    // The line number, starting at 1.
+	Text string // The line itself.
+	Err  error  // What was wrong with it.
+}
+
+/*
+ * Error returns the problem and the line it was on.
+ * e.g. "line 3: no dice roll found in "fireball""
+ */
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+/*
+ * Unwrap returns what was wrong with the line.
+ */
+func (e ImportError) Unwrap() error {
+	return e.Err
+}
+
+/*
+ * NewMacroStore returns an empty MacroStore.
+ */
+func NewMacroStore() *MacroStore {
+	return &MacroStore{macros: map[MacroScope]map[string]Macro{}}
+}
+
+/*
+ * Set saves a roll under a name in the global scope, replacing any roll already saved under that name, without any permission checks.
+ *   The roll has to be one valid roll, with no other text.
+ * e.g. store.Set("attack", "1d20+7")
+ */
+func (store *MacroStore) Set(name, roll string) error {
+	macro, err := newMacro(name, roll, "", MacroScope{})
+	if err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.put(macro)
+
+	return nil
+}
+
+/*
+ * Define saves a roll under a name in a scope on behalf of a user, if they have permission (see MacroStore.Permission), or returns ErrMacroPermission.
+ * e.g. store.Define("alice", diceroller.MacroScope{User: "alice"}, "attack", "1d20+7")
+ */
+func (store *MacroStore) Define(user string, scope MacroScope, name, roll string) error {
+	macro, err := newMacro(name, roll, user, scope)
+	if err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var existing *Macro
+	if found, ok := store.macros[scope][name]; ok {
+		existing = &found
+	}
+
+	permission := store.Permission
+	if permission == nil {
+		permission = defaultMacroPermission
+	}
+
+	if !permission(user, macro, existing) {
+		return fmt.Errorf("%w: %q", ErrMacroPermission, name)
+	}
+
+	store.put(macro)
+
+	return nil
+}
+
+/*
+ * Get returns the roll saved under a name in the global scope, and whether there was one.
+ */
+func (store *MacroStore) Get(name string) (string, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	macro, ok := store.macros[MacroScope{}][name]
+
+	return macro.Roll, ok
+}
+
+/*
+ * Resolve finds the macro a user means by a name in a room. The user's own macros in the room shadow their others, which shadow
+ *   the room's, which shadow the global ones.
+ * e.g. store.Resolve("table-1", "alice", "attack")
+ */
+func (store *MacroStore) Resolve(room, user, name string) (Macro, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return store.resolve(room, user, name)
+}
+
+/*
+ * Expand returns the rolls the macro a user means by a name in a room stands for, following any references to other macros.
+ *   References are resolved as the same user in the same room, so a user's own macros can be used within room or global macros.
+ *   It returns ErrMacroNotFound, ErrMacroCycle or ErrMacroDepth if the references can't be followed.
+ * e.g. store.Expand("table-1", "alice", "attack") // ["1d20+7", "2d6+4"]
+ */
+func (store *MacroStore) Expand(room, user, name string) ([]string, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return store.expand(room, user, name, nil)
+}
+
+/*
+ * Roll expands the macro a user means by a name in a room, as Expand does, and rolls it.
+ * e.g. store.Roll("table-1", "alice", "attack") // [{1d20+7 20 1 7 [14] 21 ...} {2d6+4 6 2 4 [3 5] 12 ...}]
+ */
+func (store *MacroStore) Roll(room, user, name string) ([]DiceRoll, error) {
+	rolls, err := store.Expand(room, user, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return RollDetails(rolls...)
+}
+
+/*
+ * ExpandText replaces each macro reference in some text, as '@name', with the rolls the macro stands for, comma-separated, so the
+ *   result can go to Parse or RollDetails. References are expanded as Expand does. An '@' within a word, as in an email address,
+ *   isn't a reference.
+ * e.g. store.ExpandText("table-1", "alice", "I swing: @attack") // "I swing: 1d20+7, 2d6+4"
+ */
+func (store *MacroStore) ExpandText(room, user, text string) (string, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var (
+		output strings.Builder
+		last   int
+	)
+
+	for _, match := range macroReferenceRegex.FindAllStringSubmatchIndex(text, -1) {
+		// The match can include the character before the '@', so start from the name and step back over the '@'.
+		start, end := match[2]-1, match[3]
+
+		rolls, err := store.expand(room, user, text[match[2]:end], nil)
+		if err != nil {
+			return "", err
+		}
+
+		output.WriteString(text[last:start])
+		output.WriteString(strings.Join(rolls, ", "))
+		last = end
+	}
+
+	output.WriteString(text[last:])
+
+	return output.String(), nil
+}
+
+/*
+ * expand returns the rolls a macro stands for, where path holds the names of the macros which led to it. The caller must hold the lock.
+ */
+func (store *MacroStore) expand(room, user, name string, path []string) (output []string, err error) {
+	path = append(path, name)
+
+	for _, previous := range path[:len(path)-1] {
+		if previous == name {
+			return nil, fmt.Errorf("%w: %s", ErrMacroCycle, strings.Join(path, " -> "))
+		}
+	}
+
+	if len(path) > maxMacroDepth+1 {
+		return nil, fmt.Errorf("%w: %s", ErrMacroDepth, strings.Join(path, " -> "))
+	}
+
+	macro, ok := store.resolve(room, user, name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrMacroNotFound, name)
+	}
+
+	for _, part := range macroParts(macro.Roll) {
+		reference, isReference := strings.CutPrefix(part, "@")
+		if !isReference {
+			output = append(output, part)
+			continue
+		}
+
+		rolls, err := store.expand(room, user, reference, path)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, rolls...)
+	}
+
+	return
+}
+
+/*
+ * resolve finds the macro a user means by a name in a room. The caller must hold the lock.
+ */
+func (store *MacroStore) resolve(room, user, name string) (Macro, bool) {
+	// Most specific first. An empty room or user has no macros of its own.
+	scopes := []MacroScope{{}}
+	if room != "" {
+		scopes = append([]MacroScope{{Room: room}}, scopes...)
+	}
+
+	if user != "" {
+		scopes = append([]MacroScope{{User: user}}, scopes...)
+	}
+
+	if room != "" && user != "" {
+		scopes = append([]MacroScope{{Room: room, User: user}}, scopes...)
+	}
+
+	for _, scope := range scopes {
+		if macro, ok := store.macros[scope][name]; ok {
+			return macro, true
+		}
+	}
+
+	return Macro{}, false
+}
+
+/*
+ * Names returns the names of all the macros saved in the global scope, in alphabetical order.
+ */
+func (store *MacroStore) Names() []string {
+	return store.ScopeNames(MacroScope{})
+}
+
+/*
+ * ScopeNames returns the names of all the macros saved in a scope, in alphabetical order.
+ */
+func (store *MacroStore) ScopeNames(scope MacroScope) (output []string) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	for name := range store.macros[scope] {
+		output = append(output, name)
+	}
+
+	sort.Strings(output)
+
+	return
+}
+
+/*
+ * put saves a macro. The caller must hold the lock.
+ */
+func (store *MacroStore) put(macro Macro) {
+	// A MacroStore made without NewMacroStore, e.g. to set its Permission, has no macros yet.
+	if store.macros == nil {
+		store.macros = map[MacroScope]map[string]Macro{}
+	}
+
+	if store.macros[macro.Scope] == nil {
+		store.macros[macro.Scope] = map[string]Macro{}
+	}
+
+	store.macros[macro.Scope][macro.Name] = macro
+}
+
+/*
+ * newMacro checks a macro's name and each of its rolls and references are valid, and returns it. References aren't followed,
+ *   so they can be defined in any order.
+ */
+func newMacro(name, roll, owner string, scope MacroScope) (Macro, error) {
+	if !macroNameRegex.MatchString(name) {
+		return Macro{}, fmt.Errorf("%q is not a valid macro name", name)
+	}
+
+	parts := macroParts(roll)
+
+	for _, part := range parts {
+		if reference, isReference := strings.CutPrefix(part, "@"); isReference {
+			if !macroNameRegex.MatchString(reference) {
+				return Macro{}, fmt.Errorf("%q is not a valid macro reference", part)
+			}
+
+			continue
+		}
+
+		if _, err := parseWholeRoll(part); err != nil {
+			return Macro{}, err
+		}
+	}
+
+	return Macro{Name: name, Roll: strings.Join(parts, ", "), Owner: owner, Scope: scope}, nil
+}
+
+/*
+ * macroParts splits a macro's comma-separated rolls and references, removing whitespace from the rolls. References are only trimmed,
+ *   so '@to-hit 1d6' stays an invalid reference rather than becoming '@to-hit1d6'.
+ * e.g. macroParts("@to-hit, 2d6 + 4") // ["@to-hit", "2d6+4"]
+ */
+func macroParts(roll string) (output []string) {
+	for _, part := range strings.Split(roll, ",") {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(part, "@") {
+			part = inputReplacer.Replace(part)
+		}
+
+		output = append(output, part)
+	}
+
+	return
+}
+
+/*
+ * defaultMacroPermission lets users define their own user macros, and new room and global macros, but only lets the owner overwrite
+ *   an existing room or global macro. A macro with no owner was set or imported by whoever runs the store, so no user can overwrite
+ *   it, not even one with no name.
+ */
+func defaultMacroPermission(user string, macro Macro, existing *Macro) bool {
+	if macro.Scope.User != "" {
+		return macro.Scope.User == user
+	}
+
+	return existing == nil || (existing.Owner != "" && existing.Owner == user)
+}
+
+/*
+ * Import reads macros, one 'name = roll' per line, and saves each valid one. The roll may be in quotes, so simple TOML files of
+ *   'name = "roll"' lines can be imported too. Blank lines and lines starting with '#' or ';' are skipped.
+ * It returns how many macros were imported and the problem with each line which wasn't, carrying on past bad lines.
+ *   The error is only for problems reading, in which case the lines read so far are still imported.
+ * e.g. store.Import(strings.NewReader("attack = 1d20+7\nsneak = \"3d6\"")) // 2, nil, nil
+ */
+func (store *MacroStore) Import(r io.Reader) (imported int, lineErrors []ImportError, err error) {
+	var (
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		name, roll, found := strings.Cut(trimmed, "=")
+		if !found {
+			lineErrors = append(lineErrors, ImportError{line, text, fmt.Errorf("expected 'name = roll'")})
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		roll = strings.Trim(strings.TrimSpace(roll), `"'`)
+
+		if setErr := store.Set(name, roll); setErr != nil {
+			lineErrors = append(lineErrors, ImportError{line, text, setErr})
+			continue
+		}
+
+		imported++
+	}
+
+	err = scanner.Err()
+
+	return
+}
+
+/*
+ * Save writes every macro in the store, in every scope, as indented JSON, so it can be loaded again with Load.
+ */
+func (store *MacroStore) Save(w io.Writer) error {
+	store.mu.RLock()
+
+	var macros []Macro
+	for _, scoped := range store.macros {
+		for _, macro := range scoped {
+			macros = append(macros, macro)
+		}
+	}
+
+	store.mu.RUnlock()
+
+	// Global macros first, then by room, user and name, so saving the same macros always writes the same JSON.
+	sort.Slice(macros, func(a, b int) bool {
+		switch {
+		case macros[a].Scope.Room != macros[b].Scope.Room:
+			return macros[a].Scope.Room < macros[b].Scope.Room
+		case macros[a].Scope.User != macros[b].Scope.User:
+			return macros[a].Scope.User < macros[b].Scope.User
+		default:
+			return macros[a].Name < macros[b].Name
+		}
+	})
+
+	if macros == nil {
+		macros = []Macro{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(macros)
+}
+
+/*
+ * Load reads macros written by Save and adds them to the store, replacing any of the same name in the same scope, without any
+ *   permission checks. Unknown fields are an error, and if any macro is invalid, none are loaded.
+ */
+func (store *MacroStore) Load(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var loaded []Macro
+	if err := decoder.Decode(&loaded); err != nil {
+		return fmt.Errorf("reading macros: %w", err)
+	}
+
+	for i, macro := range loaded {
+		checked, err := newMacro(macro.Name, macro.Roll, macro.Owner, macro.Scope)
+		if err != nil {
+			return fmt.Errorf("macro %q: %w", macro.Name, err)
+		}
+
+		loaded[i] = checked
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, macro := range loaded {
+		store.put(macro)
+	}
+
+	return nil
+}
@@ -0,0 +1,430 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type macroSetTest struct {
+	name, roll string
+	wantErr    bool
+}
+
+var macroSetTests = []macroSetTest{
+	{"attack", "1d20+7", false},
+	{"sneak-attack", "3 d 6", false},
+	{"Damage_2", "2d6kh1++1", false},
+	{"2fast", "1d6", true},
+	{"bad name", "1d6", true},
+	{"fireball", "eight d six", true},
+	{"typo", "2d6/2", true},
+	{"nothing", "2d0", true},
+}
+
+// TestMacroStoreSet calls diceroller.MacroStore.Set with many names and rolls, checking for errors and what was saved.
+func TestMacroStoreSet(t *testing.T) {
+	store := NewMacroStore()
+
+	for _, test := range macroSetTests {
+		err := store.Set(test.name, test.roll)
+
+		if (err != nil) != test.wantErr {
+			t.Errorf("have err %v for %q = %q, wanted an error %v", err, test.name, test.roll, test.wantErr)
+		}
+	}
+
+	if roll, ok := store.Get("sneak-attack"); roll != "3d6" || !ok {
+		t.Errorf("have %q %v, wanted %q true", roll, ok, "3d6")
+	}
+
+	if _, ok := store.Get("fireball"); ok {
+		t.Errorf("have a fireball macro, wanted none")
+	}
+
+	if names := store.Names(); !reflect.DeepEqual(names, []string{"Damage_2", "attack", "sneak-attack"}) {
+		t.Errorf("have %v, wanted %v", names, []string{"Damage_2", "attack", "sneak-attack"})
+	}
+}
+
+type macroDefineTest struct {
+	user     string
+	scope    MacroScope
+	name     string
+	roll     string
+	wantPerm bool
+}
+
+var macroDefineTests = []macroDefineTest{
+	{"gm", MacroScope{}, "attack", "1d20+5", true},
+	{"gm", MacroScope{Room: "table-1"}, "attack", "1d20+6", true},
+	{"alice", MacroScope{User: "alice"}, "attack", "1d20+7", true},
+	{"alice", MacroScope{Room: "table-1"}, "attack", "1d20+20", false},
+	{"alice", MacroScope{}, "attack", "1d20+20", false},
+	{"alice", MacroScope{User: "bob"}, "attack", "1d20+20", false},
+	{"alice", MacroScope{Room: "table-1"}, "sneak", "3d6", true},
+	{"alice", MacroScope{Room: "table-1"}, "sneak", "4d6", true},
+	{"gm", MacroScope{Room: "table-1"}, "sneak", "5d6", false},
+	{"alice", MacroScope{Room: "table-1", User: "alice"}, "sneak", "6d6", true},
+	{"bob", MacroScope{Room: "table-1", User: "alice"}, "sneak", "1d6", false},
+	{"", MacroScope{}, "fireball", "8d6", true},
+	{"", MacroScope{}, "fireball", "1d6", false},
+}
+
+// TestMacroStoreDefine calls diceroller.MacroStore.Define with many users and scopes, checking the default permissions and shadowing.
+func TestMacroStoreDefine(t *testing.T) {
+	store := NewMacroStore()
+
+	for _, test := range macroDefineTests {
+		err := store.Define(test.user, test.scope, test.name, test.roll)
+
+		if (err == nil) != test.wantPerm || (err != nil && !errors.Is(err, ErrMacroPermission)) {
+			t.Errorf("have err %v for %s defining %q in %v, wanted permission %v", err, test.user, test.name, test.scope, test.wantPerm)
+		}
+	}
+
+	type resolveTest struct {
+		room, user, name, want string
+	}
+
+	for _, test := range []resolveTest{
+		{"table-1", "alice", "attack", "1d20+7"},
+		{"table-1", "bob", "attack", "1d20+6"},
+		{"table-2", "bob", "attack", "1d20+5"},
+		{"", "", "attack", "1d20+5"},
+		{"table-1", "", "attack", "1d20+6"},
+		{"table-1", "bob", "sneak", "4d6"},
+		{"table-2", "bob", "sneak", ""},
+		{"table-1", "alice", "sneak", "6d6"},
+		{"table-2", "alice", "sneak", ""},
+	} {
+		macro, _ := store.Resolve(test.room, test.user, test.name)
+		if macro.Roll != test.want {
+			t.Errorf("have %q, wanted %q for %s in %s", macro.Roll, test.want, test.user, test.room)
+		}
+	}
+
+	if names := store.ScopeNames(MacroScope{Room: "table-1"}); !reflect.DeepEqual(names, []string{"attack", "sneak"}) {
+		t.Errorf("have %v, wanted %v", names, []string{"attack", "sneak"})
+	}
+
+	// Macros from Set have no owner, so nobody can overwrite them with Define, not even a user with no name.
+	_ = store.Set("heal", "2d4+2")
+	for _, user := range []string{"", "gm"} {
+		if err := store.Define(user, MacroScope{}, "heal", "9d4"); !errors.Is(err, ErrMacroPermission) {
+			t.Errorf("have err %v for %q overwriting a set macro, wanted %v", err, user, ErrMacroPermission)
+		}
+	}
+}
+
+// TestMacroStorePermission calls diceroller.MacroStore.Define with a custom permission check, checking it's used.
+func TestMacroStorePermission(t *testing.T) {
+	store := NewMacroStore()
+	store.Permission = func(user string, macro Macro, existing *Macro) bool {
+		return user == "gm"
+	}
+
+	if err := store.Define("gm", MacroScope{User: "alice"}, "attack", "1d20"); err != nil {
+		t.Errorf("have err %v, wanted nil", err)
+	}
+
+	if err := store.Define("alice", MacroScope{User: "alice"}, "attack", "1d20"); !errors.Is(err, ErrMacroPermission) {
+		t.Errorf("have err %v, wanted %v", err, ErrMacroPermission)
+	}
+}
+
+// TestMacroStoreZero calls each way of adding macros on a zero MacroStore, checking it's usable without NewMacroStore.
+func TestMacroStoreZero(t *testing.T) {
+	if err := (&MacroStore{}).Set("attack", "1d20+7"); err != nil {
+		t.Errorf("have err %v setting a macro, wanted nil", err)
+	}
+
+	store := &MacroStore{Permission: func(string, Macro, *Macro) bool { return true }}
+	if err := store.Define("gm", MacroScope{}, "attack", "1d20+7"); err != nil {
+		t.Errorf("have err %v defining a macro, wanted nil", err)
+	}
+
+	if roll, ok := store.Get("attack"); roll != "1d20+7" || !ok {
+		t.Errorf("have %q %t, wanted %q true", roll, ok, "1d20+7")
+	}
+
+	if imported, _, err := (&MacroStore{}).Import(strings.NewReader("attack = 1d20+7\n")); imported != 1 || err != nil {
+		t.Errorf("have %d imported, err %v, wanted 1 and nil", imported, err)
+	}
+
+	if err := (&MacroStore{}).Load(strings.NewReader(`[{"name":"attack","roll":"1d20+7"}]`)); err != nil {
+		t.Errorf("have err %v loading a macro, wanted nil", err)
+	}
+}
+
+type macroExpandTest struct {
+	room, user, name string
+	want             []string
+	wantErr          error
+}
+
+var macroExpandTests = []macroExpandTest{
+	{"", "", "damage", []string{"2d6+4"}, nil},
+	{"", "", "attack", []string{"1d20+7", "2d6+4"}, nil},
+	{"", "", "full-attack", []string{"1d20+7", "2d6+4", "1d20+7", "2d6+4", "1d6"}, nil},
+	{"", "alice", "attack", []string{"1d20+7", "2d6+4", "3d6"}, nil},
+	{"", "", "loop", nil, ErrMacroCycle},
+	{"", "", "ouroboros", nil, ErrMacroCycle},
+	{"", "", "missing", nil, ErrMacroNotFound},
+	{"", "", "broken", nil, ErrMacroNotFound},
+	{"", "", "deep0", nil, ErrMacroDepth},
+	{"", "", "deep1", []string{"1d4"}, nil},
+}
+
+// TestMacroStoreExpand calls diceroller.MacroStore.Expand with macros referencing other macros, checking for valid return values and errors.
+func TestMacroStoreExpand(t *testing.T) {
+	store := NewMacroStore()
+
+	for name, roll := range map[string]string{
+		"damage":      "2d6 + 4",
+		"attack":      "1d20+7, @damage",
+		"full-attack": "@attack, @attack, 1d6",
+		"loop":        "1d6, @loop",
+		"ouroboros":   "@tail",
+		"tail":        "@ouroboros",
+		"broken":      "@missing",
+	} {
+		if err := store.Set(name, roll); err != nil {
+			t.Fatalf("have err %v setting %q", err, name)
+		}
+	}
+
+	if err := store.Define("alice", MacroScope{User: "alice"}, "damage", "2d6+4, @sneak"); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if err := store.Define("alice", MacroScope{User: "alice"}, "sneak", "3d6"); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	// A chain of references one too deep: deep0 -> deep1 -> ... -> deep9.
+	for i := 0; i <= maxMacroDepth; i++ {
+		if err := store.Set(fmt.Sprintf("deep%d", i), fmt.Sprintf("@deep%d", i+1)); err != nil {
+			t.Fatalf("have err %v", err)
+		}
+	}
+
+	if err := store.Set(fmt.Sprintf("deep%d", maxMacroDepth+1), "1d4"); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	for _, test := range macroExpandTests {
+		output, err := store.Expand(test.room, test.user, test.name)
+
+		if !reflect.DeepEqual(output, test.want) || !errors.Is(err, test.wantErr) {
+			t.Errorf("have %v, err %v, wanted %v, err %v for %q", output, err, test.want, test.wantErr, test.name)
+		}
+	}
+
+	for _, roll := range []string{"@", "@2bad", "1d6,", "@attack 1d6"} {
+		if err := store.Set("bad", roll); err == nil {
+			t.Errorf("have no err for %q, wanted one", roll)
+		}
+	}
+
+	if roll, _ := store.Get("damage"); roll != "2d6+4" {
+		t.Errorf("have %q, wanted %q", roll, "2d6+4")
+	}
+
+	if roll, _ := store.Get("attack"); roll != "1d20+7, @damage" {
+		t.Errorf("have %q, wanted %q", roll, "1d20+7, @damage")
+	}
+}
+
+const macroImport = `# Macros from the old bot.
+attack = 1d20+7
+sneak = "3d6"
+
+; Some bad lines.
+fireball
+heal = 'lots'
+2bad = 1d4
+damage=2d6+3
+`
+
+// TestMacroStoreImport calls diceroller.MacroStore.Import with a mix of good and bad lines, checking for valid return values.
+func TestMacroStoreImport(t *testing.T) {
+	store := NewMacroStore()
+
+	imported, lineErrors, err := store.Import(strings.NewReader(macroImport))
+	if imported != 3 || err != nil {
+		t.Errorf("have %d imported, wanted 3, err %v", imported, err)
+	}
+
+	var lines []int
+	for _, lineError := range lineErrors {
+		lines = append(lines, lineError.Line)
+	}
+
+	if !reflect.DeepEqual(lines, []int{6, 7, 8}) {
+		t.Errorf("have errors on lines %v, wanted %v", lines, []int{6, 7, 8})
+	}
+
+	if want := "line 6: expected 'name = roll'"; lineErrors[0].Error() != want {
+		t.Errorf("have %q, wanted %q", lineErrors[0].Error(), want)
+	}
+
+	if names := store.Names(); !reflect.DeepEqual(names, []string{"attack", "damage", "sneak"}) {
+		t.Errorf("have %v, wanted %v", names, []string{"attack", "damage", "sneak"})
+	}
+}
+
+// TestMacroStoreRoll calls diceroller.MacroStore.Roll, checking each roll the macro stands for is rolled.
+func TestMacroStoreRoll(t *testing.T) {
+	reseed()
+
+	store := NewMacroStore()
+	_ = store.Set("damage", "2d6+4")
+	_ = store.Set("attack", "1d20+7, @damage")
+
+	output, err := store.Roll("", "", "attack")
+	if len(output) != 2 || output[0].DiscoveredRoll != "1d20+7" || output[1].DiscoveredRoll != "2d6+4" || err != nil {
+		t.Errorf("have %v, wanted rolls of 1d20+7 and 2d6+4, err %v", output, err)
+	}
+
+	if output, err := store.Roll("", "", "missing"); !errors.Is(err, ErrMacroNotFound) || output != nil {
+		t.Errorf("have %v, err %v, wanted ErrMacroNotFound", output, err)
+	}
+}
+
+type macroExpandTextTest struct {
+	text    string
+	want    string
+	wantErr error
+}
+
+var macroExpandTextTests = []macroExpandTextTest{
+	{"I swing: @attack", "I swing: 1d20+7, 2d6+4", nil},
+	{"@sneak and 1d4", "3d6 and 1d4", nil},
+	{"(@sneak)+@sneak.", "(3d6)+3d6.", nil},
+	{"mail gm@example.com, or roll 2d6", "mail gm@example.com, or roll 2d6", nil},
+	{"just @ a sign", "just @ a sign", nil},
+	{"no macros", "no macros", nil},
+	{"@sneak then @missing", "", ErrMacroNotFound},
+}
+
+// TestMacroStoreExpandText calls diceroller.MacroStore.ExpandText with references in other text, checking for valid return values.
+func TestMacroStoreExpandText(t *testing.T) {
+	store := NewMacroStore()
+	_ = store.Set("damage", "2d6+4")
+	_ = store.Set("attack", "1d20+7, @damage")
+	_ = store.Set("sneak", "3d6")
+
+	for _, test := range macroExpandTextTests {
+		output, err := store.ExpandText("", "", test.text)
+
+		if output != test.want || !errors.Is(err, test.wantErr) {
+			t.Errorf("have %q, err %v, wanted %q, err %v for %q", output, err, test.want, test.wantErr, test.text)
+		}
+	}
+}
+
+// TestMacroStoreSaveLoad calls diceroller.MacroStore.Save and Load, checking every scope survives the round trip.
+func TestMacroStoreSaveLoad(t *testing.T) {
+	store := NewMacroStore()
+	_ = store.Set("attack", "1d20+7, @damage")
+	_ = store.Set("damage", "2d6+4")
+	_ = store.Define("gm", MacroScope{Room: "table-1"}, "attack", "1d20+6")
+	_ = store.Define("alice", MacroScope{User: "alice"}, "sneak", "3d6")
+
+	var saved strings.Builder
+	if err := store.Save(&saved); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	want := `[
+  {
+    "name": "attack",
+    "roll": "1d20+7, @damage",
+    "scope": {}
+  },
+  {
+    "name": "damage",
+    "roll": "2d6+4",
+    "scope": {}
+  },
+  {
+    "name": "sneak",
+    "roll": "3d6",
+    "owner": "alice",
+    "scope": {
+      "user": "alice"
+    }
+  },
+  {
+    "name": "attack",
+    "roll": "1d20+6",
+    "owner": "gm",
+    "scope": {
+      "room": "table-1"
+    }
+  }
+]
+`
+	if saved.String() != want {
+		t.Errorf("have %s, wanted %s", saved.String(), want)
+	}
+
+	loaded := NewMacroStore()
+	if err := loaded.Load(strings.NewReader(saved.String())); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded.macros, store.macros) {
+		t.Errorf("have %v, wanted %v", loaded.macros, store.macros)
+	}
+
+	for _, input := range []string{
+		`[{"name": "fine", "roll": "1d6"}, {"name": "2bad", "roll": "1d6"}]`,
+		`[{"name": "ok", "roll": "1d6", "colour": "red"}]`,
+		`{}`,
+		`nope`,
+	} {
+		if err := loaded.Load(strings.NewReader(input)); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+
+	if _, ok := loaded.Get("fine"); ok {
+		t.Errorf("have macro %q loaded, wanted none loaded alongside an invalid one", "fine")
+	}
+
+	var empty strings.Builder
+	if err := NewMacroStore().Save(&empty); empty.String() != "[]\n" || err != nil {
+		t.Errorf("have %q, wanted %q, err %v", empty.String(), "[]\n", err)
+	}
+}
+
+// BenchmarkMacroStoreImport benchmarks diceroller.MacroStore.Import.
+func BenchmarkMacroStoreImport(b *testing.B) {
+	store := NewMacroStore()
+
+	for i := 0; i < b.N; i++ {
+		_, _, _ = store.Import(strings.NewReader(macroImport))
+	}
+}
@@ -0,0 +1,218 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NarrativeDie is one of the Genesys (and FFG Star Wars) narrative dice.
+type NarrativeDie int
+
+const (
+	BoostDie       NarrativeDie = iota // Light blue d6.
+	SetbackDie                         // Black d6.
+	AbilityDie                         // Green d8.
+	DifficultyDie                      // Purple d8.
+	ProficiencyDie                     // Yellow d12.
+	ChallengeDie                       // Red d12.
+)
+
+/*
+ * String returns the die's name.
+ * e.g. "proficiency"
+ */
+func (die NarrativeDie) String() string {
+	switch die {
+	case BoostDie:
+		return "boost"
+	case SetbackDie:
+		return "setback"
+	case AbilityDie:
+		return "ability"
+	case DifficultyDie:
+		return "difficulty"
+	case ProficiencyDie:
+		return "proficiency"
+	case ChallengeDie:
+		return "challenge"
+	default:
+		return fmt.Sprintf("NarrativeDie(%d)", int(die))
+	}
+}
+
+// NarrativeSymbols counts the symbols on a face, or a whole roll. Narrative dice don't have numeric totals.
+type NarrativeSymbols struct {
+	Success   int
+	Failure   int
+	Advantage int
+	Threat    int
+	Triumph   int // Each triumph also counts as a success.
+	Despair   int // Each despair also counts as a failure.
+}
+
+/*
+ * String lists the symbols in words, leaving out any there are none of, or "blank".
+ * e.g. "2 success, 1 advantage, 1 triumph"
+ */
+func (symbols NarrativeSymbols) String() string {
+	var parts []string
+
+	for _, symbol := range []struct {
+		name  string
+		count int
+	}{
+		{"success", symbols.Success},
+		{"failure", symbols.Failure},
+		{"advantage", symbols.Advantage},
+		{"threat", symbols.Threat},
+		{"triumph", symbols.Triumph},
+		{"despair", symbols.Despair},
+	} {
+		if symbol.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", symbol.count, symbol.name))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "blank"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// NarrativePool says how many of each narrative die to roll.
+type NarrativePool struct {
+	Boost       int
+	Setback     int
+	Ability     int
+	Difficulty  int
+	Proficiency int
+	Challenge   int
+}
+
+// NarrativeRoll holds the details of a roll of narrative dice.
+type NarrativeRoll struct {
+	Dice      []NarrativeDie     // Each die rolled, in pool order: boost, setback, ability, difficulty, proficiency then challenge.
+	Faces     []NarrativeSymbols // What each die came up.
+	Net       NarrativeSymbols   // What's left once successes and failures, and advantages and threats, cancel out. Triumphs and despairs don't cancel.
+	Succeeded bool               // Whether any successes were left.
+}
+
+// The faces of each narrative die. Triumphs and despairs already count as a success or failure.
+var narrativeFaces = map[NarrativeDie][]NarrativeSymbols{
+	BoostDie: {
+		{}, {},
+		{Success: 1}, {Success: 1, Advantage: 1},
+		{Advantage: 2}, {Advantage: 1},
+	},
+	SetbackDie: {
+		{}, {},
+		{Failure: 1}, {Failure: 1},
+		{Threat: 1}, {Threat: 1},
+	},
+	AbilityDie: {
+		{}, {Success: 1}, {Success: 1}, {Success: 2},
+		{Advantage: 1}, {Advantage: 1}, {Success: 1, Advantage: 1}, {Advantage: 2},
+	},
+	DifficultyDie: {
+		{}, {Failure: 1}, {Failure: 2}, {Threat: 1},
+		{Threat: 1}, {Threat: 1}, {Threat: 2}, {Failure: 1, Threat: 1},
+	},
+	ProficiencyDie: {
+		{}, {Success: 1}, {Success: 1}, {Success: 2}, {Success: 2}, {Advantage: 1},
+		{Success: 1, Advantage: 1}, {Success: 1, Advantage: 1}, {Success: 1, Advantage: 1}, {Advantage: 2}, {Advantage: 2},
+		{Success: 1, Triumph: 1},
+	},
+	ChallengeDie: {
+		{}, {Failure: 1}, {Failure: 1}, {Failure: 2}, {Failure: 2}, {Threat: 1},
+		{Threat: 1}, {Failure: 1, Threat: 1}, {Failure: 1, Threat: 1}, {Threat: 2}, {Threat: 2},
+		{Failure: 1, Despair: 1},
+	},
+}
+
+/*
+ * RollNarrative rolls a pool of Genesys narrative dice, and cancels successes against failures and advantages against threats.
+ * e.g. RollNarrative(NarrativePool{Ability: 2, Proficiency: 1, Difficulty: 2}) // {[ability ability difficulty difficulty proficiency] [...] 1 success, 1 triumph true}
+ */
+func RollNarrative(pool NarrativePool) (output NarrativeRoll, err error) {
+	return defaultRoller.RollNarrative(pool)
+}
+
+/*
+ * RollNarrative is like the package's RollNarrative, but rolls with the Roller's random source.
+ * e.g. roller.RollNarrative(NarrativePool{Ability: 2, Difficulty: 1})
+ */
+func (roller *Roller) RollNarrative(pool NarrativePool) (output NarrativeRoll, err error) {
+	var (
+		counts = []int{pool.Boost, pool.Setback, pool.Ability, pool.Difficulty, pool.Proficiency, pool.Challenge}
+		source = roller.source()
+		total  NarrativeSymbols
+	)
+
+	for die, count := range counts {
+		if count < 0 {
+			return NarrativeRoll{}, errors.New("can't roll a negative number of narrative dice")
+		}
+
+		faces := narrativeFaces[NarrativeDie(die)]
+
+		for range count {
+			face := faces[DieSpec{Faces: len(faces)}.rollFrom(source)-1]
+
+			output.Dice = append(output.Dice, NarrativeDie(die))
+			output.Faces = append(output.Faces, face)
+
+			total.Success += face.Success
+			total.Failure += face.Failure
+			total.Advantage += face.Advantage
+			total.Threat += face.Threat
+			total.Triumph += face.Triumph
+			total.Despair += face.Despair
+		}
+	}
+
+	output.Net = cancelNarrative(total)
+	output.Succeeded = output.Net.Success > 0
+
+	return
+}
+
+/*
+ * cancelNarrative cancels successes against failures, and advantages against threats, leaving triumphs and despairs alone.
+ */
+func cancelNarrative(input NarrativeSymbols) NarrativeSymbols {
+	output := NarrativeSymbols{Triumph: input.Triumph, Despair: input.Despair}
+
+	if input.Success > input.Failure {
+		output.Success = input.Success - input.Failure
+	} else {
+		output.Failure = input.Failure - input.Success
+	}
+
+	if input.Advantage > input.Threat {
+		output.Advantage = input.Advantage - input.Threat
+	} else {
+		output.Threat = input.Threat - input.Advantage
+	}
+
+	return output
+}
@@ -0,0 +1,122 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rollNarrativeTest struct {
+	pool      NarrativePool
+	wantDice  []NarrativeDie
+	wantFaces []NarrativeSymbols
+	wantNet   NarrativeSymbols
+}
+
+var rollNarrativeTests = []rollNarrativeTest{
+	{
+		NarrativePool{Ability: 2, Proficiency: 1, Difficulty: 2},
+		[]NarrativeDie{AbilityDie, AbilityDie, DifficultyDie, DifficultyDie, ProficiencyDie},
+		[]NarrativeSymbols{{Success: 1}, {Success: 2}, {Failure: 2}, {Failure: 1}, {Success: 1, Triumph: 1}},
+		NarrativeSymbols{Success: 1, Triumph: 1},
+	},
+	{
+		NarrativePool{Boost: 1, Setback: 1, Challenge: 2, Proficiency: 2},
+		[]NarrativeDie{BoostDie, SetbackDie, ProficiencyDie, ProficiencyDie, ChallengeDie, ChallengeDie},
+		[]NarrativeSymbols{{}, {Failure: 1}, {Success: 1, Advantage: 1}, {Success: 2}, {Failure: 1}, {Failure: 1}},
+		NarrativeSymbols{Advantage: 1},
+	},
+	{NarrativePool{}, nil, nil, NarrativeSymbols{}},
+}
+
+// TestRollNarrative calls diceroller.RollNarrative with many pools, checking for valid return values.
+func TestRollNarrative(t *testing.T) {
+	reseed()
+
+	for _, test := range rollNarrativeTests {
+		output, err := RollNarrative(test.pool)
+
+		if !reflect.DeepEqual(output.Dice, test.wantDice) || !reflect.DeepEqual(output.Faces, test.wantFaces) || output.Net != test.wantNet ||
+			output.Succeeded != (test.wantNet.Success > 0) || err != nil {
+			t.Errorf("have %v, wanted %v %v %v, err %v", output, test.wantDice, test.wantFaces, test.wantNet, err)
+		}
+	}
+
+	if _, err := RollNarrative(NarrativePool{Ability: -1}); err == nil {
+		t.Errorf("have nil error for a negative pool, wanted an error")
+	}
+}
+
+type cancelNarrativeTest struct {
+	input, want NarrativeSymbols
+}
+
+var cancelNarrativeTests = []cancelNarrativeTest{
+	{NarrativeSymbols{Success: 3, Failure: 1, Advantage: 1, Threat: 2}, NarrativeSymbols{Success: 2, Threat: 1}},
+	{NarrativeSymbols{Success: 2, Failure: 2, Advantage: 2, Threat: 2}, NarrativeSymbols{}},
+	{NarrativeSymbols{Success: 1, Failure: 2, Triumph: 1, Despair: 1}, NarrativeSymbols{Failure: 1, Triumph: 1, Despair: 1}},
+}
+
+// TestCancelNarrative calls diceroller.cancelNarrative with many symbols, checking they cancel.
+func TestCancelNarrative(t *testing.T) {
+	for _, test := range cancelNarrativeTests {
+		if output := cancelNarrative(test.input); output != test.want {
+			t.Errorf("have %v, wanted %v", output, test.want)
+		}
+	}
+}
+
+// TestNarrativeFaces checks each narrative die has the right number of faces and symbols.
+func TestNarrativeFaces(t *testing.T) {
+	for die, want := range map[NarrativeDie][2]int{
+		BoostDie: {6, 6}, SetbackDie: {6, 4}, AbilityDie: {8, 10}, DifficultyDie: {8, 10}, ProficiencyDie: {12, 18}, ChallengeDie: {12, 17},
+	} {
+		var symbols int
+		for _, face := range narrativeFaces[die] {
+			symbols += face.Success + face.Failure + face.Advantage + face.Threat
+		}
+
+		if len(narrativeFaces[die]) != want[0] || symbols != want[1] {
+			t.Errorf("have %d faces and %d symbols on a %v die, wanted %v", len(narrativeFaces[die]), symbols, die, want)
+		}
+	}
+}
+
+// TestNarrativeSymbolsString calls diceroller.NarrativeSymbols.String, checking for valid return values.
+func TestNarrativeSymbolsString(t *testing.T) {
+	for symbols, want := range map[NarrativeSymbols]string{
+		{}:                                     "blank",
+		{Success: 2, Advantage: 1, Triumph: 1}: "2 success, 1 advantage, 1 triumph",
+		{Failure: 1, Threat: 3, Despair: 1}:    "1 failure, 3 threat, 1 despair",
+	} {
+		if output := symbols.String(); output != want {
+			t.Errorf("have %q, wanted %q", output, want)
+		}
+	}
+}
+
+// BenchmarkRollNarrative benchmarks diceroller.RollNarrative.
+func BenchmarkRollNarrative(b *testing.B) {
+	pool := NarrativePool{Ability: 2, Proficiency: 2, Difficulty: 2, Challenge: 1, Boost: 1, Setback: 1}
+
+	for i := 0; i < b.N; i++ {
+		_, _ = RollNarrative(pool)
+	}
+}
@@ -0,0 +1,313 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// The words for numbers natural language rolls understand, up to nineteen, and the tens, e.g. 'twenty' in 'twenty one'.
+	numberWords = map[string]int{
+		"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+		"eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16, "seventeen": 17, "eighteen": 18,
+		"nineteen": 19, "twenty": 20, "thirty": 30, "forty": 40, "fifty": 50, "sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+		"hundred": 100,
+	}
+
+	// Words natural language rolls can have anywhere, such as 'please roll me', which are skipped.
+	fillerWords = []string{"please", "can", "could", "you", "i", "want", "to", "lets", "roll", "throw", "toss", "me", "us", "give",
+		"some", "the", "of", "for", "and", "with", "then", "them", "it"}
+
+	// The words for dice, which are d6s if there's nothing else to say what they are.
+	dieWords = []string{"dice", "die", "dices"}
+
+	// A minus sign written as a hyphen, e.g. '2d6 - 1' or '2d6-1', rather than a hyphen in a word, such as 'six-sided'.
+	minusRegex = regexp.MustCompile(`-\s*(\d)`)
+
+	// Dice written as in the notation, e.g. 'd20', '3d6' or 'd20s'.
+	phraseDiceRegex = regexp.MustCompile(`^(\d*)d(\d+)s?$`)
+)
+
+/*
+ * ParseNaturalLanguage turns a phrase such as 'roll two six-sided dice plus three' or 'three d twenties' into a roll in the 'nDn+n'
+ *   format, for voice assistants and casual chat. It understands numbers in words or digits, dice as 'six-sided dice', 'd twenty'
+ *   or 'd20' ('dice' alone are d6s, 'percentile dice' are d100s), 'plus' and 'minus' modifiers, 'with advantage' or 'disadvantage',
+ *   'keep the highest three' or 'drop the lowest', and 'sorted'. Words it doesn't understand are an error, rather than guessed at.
+ * e.g. ParseNaturalLanguage("roll two six-sided dice plus three") // "2d6+3"
+ * e.g. ParseNaturalLanguage("four d sixes, drop the lowest") // "4d6kh3"
+ */
+func ParseNaturalLanguage(phrase string) (string, error) {
+	var (
+		words           = phraseWords(phrase)
+		rolls, faces    = 1, 0
+		modifier        int
+		keep, sort, adv string
+		keepCount, i    int
+		number          = func(word string) (int, bool) { n, err := strconv.Atoi(word); return n, err == nil }
+		countGiven      bool
+	)
+
+	for i < len(words) && slices.Contains(fillerWords, words[i]) {
+		i++
+	}
+
+	// The number of dice, unless it's the number of faces, as in 'six-sided'.
+	if i < len(words) {
+		if n, ok := number(words[i]); ok && (i+1 == len(words) || (words[i+1] != "sided" && words[i+1] != "faced")) {
+			rolls, countGiven = n, true
+			i++
+		} else if words[i] == "a" || words[i] == "an" {
+			i++
+		}
+	}
+
+	// The dice.
+	switch {
+	case i < len(words) && phraseDiceRegex.MatchString(words[i]):
+		result := phraseDiceRegex.FindStringSubmatch(words[i])
+		if result[1] != "" {
+			if countGiven {
+				return "", fmt.Errorf("could not understand %q in %q: the number of dice is given twice", words[i], phrase)
+			}
+
+			rolls, _ = number(result[1])
+		}
+
+		faces, _ = number(result[2])
+		i++
+	case i+1 < len(words) && words[i] == "d":
+		n, ok := number(words[i+1])
+		if !ok {
+			return "", fmt.Errorf("could not understand %q in %q: expected the number of faces", words[i+1], phrase)
+		}
+
+		faces = n
+		i += 2
+	case i+1 < len(words) && (words[i+1] == "sided" || words[i+1] == "faced"):
+		faces, _ = number(words[i])
+		i += 2
+	case i < len(words) && words[i] == "percentile":
+		faces = 100
+		i++
+	case i < len(words) && slices.Contains(dieWords, words[i]):
+		faces = 6
+	default:
+		return "", fmt.Errorf("%w in %q", ErrNoRollFound, phrase)
+	}
+
+	if i < len(words) && slices.Contains(dieWords, words[i]) {
+		i++
+	}
+
+	// Everything after the dice: modifiers, advantage, keeping and dropping, and sorting.
+	for i < len(words) {
+		word := words[i]
+		i++
+
+		switch word {
+		case "plus", "add", "minus", "subtract", "less", "take":
+			if i < len(words) && words[i] == "away" {
+				i++
+			}
+
+			n, ok := 0, false
+			if i < len(words) {
+				n, ok = number(words[i])
+			}
+
+			if !ok {
+				return "", fmt.Errorf("could not understand %q in %q: expected a number after it", word, phrase)
+			}
+
+			if word == "plus" || word == "add" {
+				modifier += n
+			} else {
+				modifier -= n
+			}
+
+			i++
+		case "advantage", "disadvantage":
+			adv = word
+		case "keep", "keeping", "drop", "dropping":
+			var direction string
+
+			count := 1
+			for i < len(words) && (direction == "" || count == 1) {
+				if n, ok := number(words[i]); ok {
+					count = n
+				} else if slices.Contains([]string{"highest", "best", "top"}, words[i]) {
+					direction = "h"
+				} else if slices.Contains([]string{"lowest", "worst", "bottom"}, words[i]) {
+					direction = "l"
+				} else if words[i] != "the" && !slices.Contains(dieWords, words[i]) {
+					break
+				}
+
+				i++
+			}
+
+			if direction == "" {
+				return "", fmt.Errorf("could not understand %q in %q: expected 'highest' or 'lowest'", word, phrase)
+			}
+
+			keep, keepCount = roll20Keep(string(word[0])+direction, count, rolls)
+		case "sorted", "sort":
+			sort = "s"
+		default:
+			if !slices.Contains(fillerWords, word) && !slices.Contains(dieWords, word) {
+				return "", fmt.Errorf("could not understand %q in %q", word, phrase)
+			}
+		}
+	}
+
+	output := fmt.Sprintf("%dd%d", rolls, faces)
+
+	switch {
+	case adv != "" && keep != "":
+		return "", fmt.Errorf("could not understand %q: it can't both keep dice and have %s", phrase, adv)
+	case adv != "" && rolls == 1:
+		output = fmt.Sprintf("d%d%s", faces, adv[:3])
+	case adv != "":
+		output += adv[:3]
+	case keep != "":
+		output += keep + strconv.Itoa(keepCount)
+	}
+
+	output += sort
+	if modifier != 0 {
+		output += fmt.Sprintf("%+d", modifier)
+	}
+
+	// Check it's a roll which can be rolled, e.g. not more than five digits, or no faces.
+	if _, err := parseWholeRoll(output); err != nil {
+		return "", err
+	}
+
+	return output, nil
+}
+
+/*
+ * WithNaturalLanguage makes a Roller understand phrases such as 'roll two six-sided dice plus three' instead of its own notation, as
+ *   ParseNaturalLanguage does, e.g. for a voice assistant. The discovered roll is in the notation, e.g. '2d6+3'.
+ * e.g. NewRoller(WithNaturalLanguage()).RollOne("three d twenties") // 31
+ */
+func WithNaturalLanguage() RollerOption {
+	return func(roller *Roller) {
+		roller.notation = &notation{roll: func(roller *Roller, input string) (DiceRoll, error) {
+			spec, err := parseNaturalRoll(input)
+			if err != nil {
+				return DiceRoll{}, err
+			}
+
+			return roller.rollParsed(spec)
+		}, dice: func(roller *Roller, input string) (int, error) {
+			spec, err := parseNaturalRoll(input)
+
+			return spec.rolls, err
+		}}
+	}
+}
+
+/*
+ * parseNaturalRoll reads a phrase as ParseNaturalLanguage does and breaks the roll it describes down into a rollSpec.
+ */
+func parseNaturalRoll(phrase string) (spec rollSpec, err error) {
+	expression, err := ParseNaturalLanguage(phrase)
+	if err != nil {
+		return
+	}
+
+	return parseRoll(expression)
+}
+
+/*
+ * phraseWords splits a phrase up into lower case words, with '+' and '-' as 'plus' and 'minus', and numbers in words, including
+ *   plurals such as 'twenties', as digits. Numbers in words next to each other are put together, e.g. 'twenty one' is '21'.
+ */
+func phraseWords(phrase string) (output []string) {
+	phrase = strings.ReplaceAll(strings.ToLower(phrase), "+", " plus ")
+	phrase = minusRegex.ReplaceAllString(phrase, " minus $1")
+	phrase = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\'':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		default:
+			return ' '
+		}
+	}, phrase)
+
+	var merge bool // Whether the last word was a number in words, which the next one can be put together with.
+
+	for _, word := range strings.Fields(phrase) {
+		value, ok := phraseNumber(word)
+		if !ok {
+			output, merge = append(output, word), false
+			continue
+		}
+
+		if merge {
+			last := len(output) - 1
+			previous, _ := strconv.Atoi(output[last])
+
+			switch {
+			case value == 100 && previous < 100:
+				output[last] = strconv.Itoa(max(previous, 1) * 100)
+				continue
+			case value < 10 && previous >= 20 && previous%10 == 0 && previous < 100, value < 100 && previous >= 100 && previous%100 == 0:
+				output[last] = strconv.Itoa(previous + value)
+				continue
+			}
+		}
+
+		output, merge = append(output, strconv.Itoa(value)), !isDigit(word[0])
+	}
+
+	return
+}
+
+/*
+ * phraseNumber returns the number a word is, or its plural, e.g. 'six', 'sixes' or 'twenties'.
+ */
+func phraseNumber(word string) (int, bool) {
+	singulars := []string{word, strings.TrimSuffix(word, "s"), strings.TrimSuffix(word, "es")}
+	if strings.HasSuffix(word, "ies") {
+		singulars = append(singulars, strings.TrimSuffix(word, "ies")+"y")
+	}
+
+	for _, singular := range singulars {
+		if value, ok := numberWords[singular]; ok {
+			return value, true
+		}
+	}
+
+	// Numbers in digits can be plural too, e.g. '20s'.
+	if value, err := strconv.Atoi(strings.TrimSuffix(word, "s")); err == nil {
+		return value, true
+	}
+
+	return 0, false
+}
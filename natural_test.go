@@ -0,0 +1,95 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+var parseNaturalLanguageTests = map[string]string{
+	"roll two six-sided dice plus three":            "2d6+3",
+	"three d twenties":                              "3d20",
+	"four d sixes, drop the lowest":                 "4d6kh3",
+	"Roll a d20 with advantage, plus five":          "d20adv+5",
+	"two d20s with disadvantage":                    "2d20dis",
+	"give me 2d6+3":                                 "2d6+3",
+	"roll 2d6 - 1":                                  "2d6-1",
+	"an eight sided die minus two":                  "1d8-2",
+	"two dice":                                      "2d6",
+	"percentile dice":                               "1d100",
+	"one hundred sided die":                         "1d100",
+	"twenty one d4":                                 "21d4",
+	"roll four dice and keep the highest three":     "4d6kh3",
+	"roll 4d6, keep best 3, sorted":                 "4d6kh3s",
+	"five d tens, keep the two lowest":              "5d10kl2",
+	"3 twelve-sided dice plus 1 plus 2 take away 4": "3d12-1",
+	"Please roll me a d twenty!":                    "1d20",
+}
+
+// TestParseNaturalLanguage calls diceroller.ParseNaturalLanguage with phrases, checking for valid return values.
+func TestParseNaturalLanguage(t *testing.T) {
+	for input, want := range parseNaturalLanguageTests {
+		if output, err := ParseNaturalLanguage(input); output != want || err != nil {
+			t.Errorf("have %q, err %v, wanted %q from %q", output, err, want, input)
+		}
+	}
+}
+
+// TestParseNaturalLanguageErrors calls diceroller.ParseNaturalLanguage with phrases it can't understand, checking for errors.
+func TestParseNaturalLanguageErrors(t *testing.T) {
+	for input, want := range map[string]error{
+		"":                                       ErrNoRollFound,
+		"roll":                                   ErrNoRollFound,
+		"two eggs":                               ErrNoRollFound,
+		"zero d6":                                ErrZeroDice,
+		"three d zeroes":                         ErrZeroFaces,
+		"a d999999":                              ErrTooLarge,
+		"2 3d6":                                  nil,
+		"d twenty plus":                          nil,
+		"d twenty or so":                         nil,
+		"four dice keep":                         nil,
+		"roll d fort":                            nil,
+		"a d20 with advantage, keep the highest": nil,
+	} {
+		output, err := ParseNaturalLanguage(input)
+		if err == nil || (want != nil && !errors.Is(err, want)) {
+			t.Errorf("have %q, err %v for %q, wanted an error", output, err, input)
+		}
+	}
+}
+
+// TestWithNaturalLanguage rolls a phrase with a diceroller.Roller made with WithNaturalLanguage, checking it's rolled as the roll.
+func TestWithNaturalLanguage(t *testing.T) {
+	output, err := NewRoller(WithSeed(42, 1024), WithNaturalLanguage()).RollDetails("roll four d sixes and drop the lowest")
+	if err != nil || output[0].DiscoveredRoll != "4d6kh3" || output[0].Total != 16 {
+		t.Errorf("have %+v, err %v, wanted 4d6kh3 totalling 16", output, err)
+	}
+
+	if _, err := NewRoller(WithNaturalLanguage()).RollOne("two eggs"); !errors.Is(err, ErrNoRollFound) {
+		t.Errorf("have err %v, wanted %v", err, ErrNoRollFound)
+	}
+}
+
+// BenchmarkParseNaturalLanguage benchmarks diceroller.ParseNaturalLanguage.
+func BenchmarkParseNaturalLanguage(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseNaturalLanguage("roll four six-sided dice, keep the highest three, plus two")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+ * Normalize rewrites one roll in the 'nDn+n' format into one canonical form, so rolls written differently but meaning the same
+ *   thing match, e.g. as map keys or macro names: no white space, lower case, no leading zeros, and no '+0' or '++0' modifiers.
+ *   Advantage and disadvantage stay as they are, without the number of dice if it's one.
+ * e.g. Normalize("2 D 6 + 2") // "2d6+2"
+ * e.g. Normalize("1D20ADV+0") // "d20adv"
+ */
+func Normalize(input string) (string, error) {
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return "", err
+	}
+
+	return spec.normalize(), nil
+}
+
+/*
+ * normalize writes a rollSpec back out in the canonical form used by Normalize.
+ */
+func (spec rollSpec) normalize() (output string) {
+	// Advantage is the only way to write a roll without the number of dice, so it's the only way 'adv' or 'dis' can be in one.
+	discovered := strings.ToLower(spec.discovered)
+	switch advantage := strings.Contains(discovered, "adv"); {
+	case advantage || strings.Contains(discovered, "dis"):
+		if spec.keepCount > 1 {
+			output = strconv.Itoa(spec.keepCount)
+		}
+
+		output += fmt.Sprintf("d%d", spec.die.Faces)
+		if advantage {
+			output += "adv"
+		} else {
+			output += "dis"
+		}
+	case spec.keep != "":
+		output = fmt.Sprintf("%dd%d%s%d", spec.rolls, spec.die.Faces, spec.keep, spec.keepCount)
+	default:
+		output = fmt.Sprintf("%dd%d", spec.rolls, spec.die.Faces)
+	}
+
+	output += spec.sort
+
+	// Per-die modifiers are written with a doubled sign.
+	if spec.dieModifier != 0 {
+		dieModifier := fmt.Sprintf("%+d", spec.dieModifier)
+		output += dieModifier[:1] + dieModifier
+	}
+
+	if spec.modifier != 0 {
+		output += fmt.Sprintf("%+d", spec.modifier)
+	}
+
+	return
+}
@@ -0,0 +1,79 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+var normalizeTests = map[string]string{
+	"2d6":           "2d6",
+	"2 D 6 + 2":     "2d6+2",
+	"1d20+0":        "1d20",
+	"02d006-01":     "2d6-1",
+	"4D6KH3":        "4d6kh3",
+	"4d6 kh 03 + 1": "4d6kh3+1",
+	"4d6S++0":       "4d6s",
+	"3d8SD--1-2":    "3d8sd--1-2",
+	"5d6km3++2+0":   "5d6km3++2",
+	"d20ADV+5":      "d20adv+5",
+	"1d20adv":       "d20adv",
+	"2d20Dis-0":     "2d20dis",
+	"\t4d6s\n":      "4d6s",
+}
+
+// TestNormalize calls diceroller.Normalize with rolls written in different ways, checking for valid return values, and that the
+// normalized roll normalizes to itself.
+func TestNormalize(t *testing.T) {
+	for input, want := range normalizeTests {
+		output, err := Normalize(input)
+		if output != want || err != nil {
+			t.Errorf("have %q, err %v, wanted %q from %q", output, err, want, input)
+		}
+
+		if again, err := Normalize(output); again != output || err != nil {
+			t.Errorf("have %q, err %v, wanted %q normalized again", again, err, output)
+		}
+	}
+}
+
+// TestNormalizeErrors calls diceroller.Normalize with things which aren't one roll, checking for errors.
+func TestNormalizeErrors(t *testing.T) {
+	for input, want := range map[string]error{
+		"":           ErrNoRollFound,
+		"nonsense":   ErrNoRollFound,
+		"2d6 to hit": ErrNotJustRoll,
+		"0d6":        ErrZeroDice,
+		"2d0":        ErrZeroFaces,
+		"2d6/2":      ErrNotJustRoll,
+		"2d123456":   ErrTooLarge,
+	} {
+		if _, err := Normalize(input); !errors.Is(err, want) {
+			t.Errorf("have err %v for %q, wanted %v", err, input, want)
+		}
+	}
+}
+
+// BenchmarkNormalize benchmarks diceroller.Normalize.
+func BenchmarkNormalize(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Normalize("4d6 KH3 s ++1 + 0")
+	}
+}
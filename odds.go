@@ -0,0 +1,115 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// How many dice a simulation rolls, all told, when a roll is too complex to work out exactly, e.g. 2000 rolls of 1000d6.
+const probabilitySimulationDice = 2_000_000
+
+// This is the regex used to understand questions such as 'at least 18 on 3d6' or 'what are the odds of rolling under 5 with 2d4?'.
+var oddsRegex = regexp.MustCompile(`^(?:what are the (?:odds|chances) of )?(?:rolling |getting )?(at least|at most|exactly|more than|less than|over|under|above|below) (-?\d{1,6}) (?:on|with) (.+)$`)
+
+/*
+ * Odds answers a question such as 'at least 18 on 3d6' with the exact probability, from 0 to 1.
+ * Questions take the form '<comparison> <number> on <roll>', where the comparison is one of 'at least', 'at most', 'exactly',
+ *   'more than' (or 'over', 'above') or 'less than' (or 'under', 'below'), and may start with 'what are the odds of rolling'.
+ * e.g. Odds("at least 18 on 3d6") // 0.004629629629629629
+ */
+func Odds(question string) (float64, error) {
+	// Normalise the question: lower case, single spaces, and no trailing question mark or full stop.
+	question = strings.Join(strings.Fields(strings.ToLower(question)), " ")
+	question = strings.TrimRight(question, "?.")
+
+	result := oddsRegex.FindStringSubmatch(question)
+	if result == nil {
+		return 0, fmt.Errorf("could not understand the question %q", question)
+	}
+
+	target, err := strconv.Atoi(result[2])
+	if err != nil {
+		return 0, err
+	}
+
+	// The roll has to be the whole of the end of the question, so we don't answer a question we weren't asked.
+	spec, err := parseWholeRoll(result[3])
+	if err != nil {
+		return 0, err
+	}
+
+	dist, err := spec.distribution()
+	if err != nil {
+		return 0, err
+	}
+
+	switch result[1] {
+	case "at least":
+		return dist.atLeast(target), nil
+	case "at most":
+		return dist.atMost(target), nil
+	case "exactly":
+		return dist.exactly(target), nil
+	case "more than", "over", "above":
+		return dist.atLeast(target + 1), nil
+	default: // "less than", "under", "below".
+		return dist.atMost(target - 1), nil
+	}
+}
+
+/*
+ * ProbabilityAtLeast returns the chance of one roll in the 'nDn+n' format totalling the target or more, from 0 to 1, e.g. the odds
+ *   of 2d6+3 beating 10. It's exact if it can be, and if the roll is too complex to work out exactly, it's estimated by simulating
+ *   the roll, from the package's random source, up to a million times.
+ * e.g. ProbabilityAtLeast("2d6+3", 11) // 0.41666666666666663
+ */
+func ProbabilityAtLeast(input string, target int) (float64, error) {
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return 0, err
+	}
+
+	dist, err := spec.distribution()
+	if err == nil {
+		return dist.atLeast(target), nil
+	}
+
+	if !errors.Is(err, ErrTooComplex) {
+		return 0, err
+	}
+
+	simulation, err := Simulate(spec.discovered, min(max(probabilitySimulationDice/spec.rolls, 1000), 1_000_000))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for i, c := range simulation.Counts {
+		if simulation.Min+i >= target {
+			count += c
+		}
+	}
+
+	return float64(count) / float64(simulation.Rolls), nil
+}
@@ -0,0 +1,135 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+type oddsTest struct {
+	got  string
+	want float64
+}
+
+var oddsTests = []oddsTest{
+	{"at least 18 on 3d6", 1.0 / 216},
+	{"At least 3 on 3d6", 1},
+	{"at most 2 on 1d4", 0.5},
+	{"exactly 7 on 2d6", 6.0 / 36},
+	{"more than 10 on 2d6", 3.0 / 36},
+	{"over 10 on 2d6", 3.0 / 36},
+	{"less than 4 on 2d6", 3.0 / 36},
+	{"under 2 on 1d20", 1.0 / 20},
+	{"What are the odds of rolling above 12 with 2d6+2?", 3.0 / 36},
+	{"exactly 1 on 2d6-1", 1.0 / 36},
+	{"exactly 30 on 2d6", 0},
+	{"at least 2 on 2 d 6", 1},
+	{"at least 1 on 4d6s", 1},
+	{"at least 20 on 2d20kh1", 1 - 19.0*19/400},
+	{"at least 20 on 2d20kl1", 1.0 / 400},
+	{"exactly 18 on 4d6kh3", 21.0 / 1296},
+	{"exactly 1 on 3d20km1", 58.0 / 8000},
+	{"at least 1 on 3d6kl5", 1},
+	{"exactly 8 on 2d6++1", 5.0 / 36},
+	{"at least 20 on d20adv", 1 - 19.0*19/400},
+	{"at least 25 on 1d20dis+5", 1.0 / 400},
+	{"exactly 20 on 4d6kh3++1-1", 21.0 / 1296},
+}
+
+// TestOdds calls diceroller.Odds with many questions, checking for valid return values.
+func TestOdds(t *testing.T) {
+	for _, test := range oddsTests {
+		output, err := Odds(test.got)
+
+		if math.Abs(output-test.want) > 1e-9 || err != nil {
+			t.Errorf("have %v, wanted %v for %q, err %v", output, test.want, test.got, err)
+		}
+	}
+}
+
+var oddsErrorTests = []string{
+	"",
+	"what is 2d6",
+	"at least 2 on",
+	"at least 2 on 2d6 please",
+	"at least 2 on 2d0",
+	"at least 2 on 99999d99999",
+	"at least 2 on 99999d99999kh1",
+}
+
+// TestOddsErrors calls diceroller.Odds with questions it can't answer, checking for errors.
+func TestOddsErrors(t *testing.T) {
+	for _, test := range oddsErrorTests {
+		if _, err := Odds(test); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", test)
+		}
+	}
+}
+
+// BenchmarkOdds benchmarks diceroller.Odds.
+func BenchmarkOdds(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Odds(oddsTests[0].got)
+	}
+}
+
+type probabilityTest struct {
+	input     string
+	target    int
+	want      float64
+	tolerance float64 // How far off an estimate can be, or 0 if it should be exact.
+}
+
+var probabilityTests = []probabilityTest{
+	{"2d6+3", 11, 15.0 / 36, 0},
+	{"2d6+3", 5, 1, 0},
+	{"2d6+3", 16, 0, 0},
+	{"1d20", 15, 0.3, 0},
+	{"4d6kh3", 18, 0.016203703703703703, 0},
+	{"10000d100", 505_000, 0.5, 0.1},
+	{"60d20kh30", 30, 1, 0},
+	{"60d20kh30", 601, 0, 0},
+}
+
+// TestProbabilityAtLeast calls diceroller.ProbabilityAtLeast, checking exact answers, and estimates for rolls too complex to work out.
+func TestProbabilityAtLeast(t *testing.T) {
+	reseed()
+
+	for _, test := range probabilityTests {
+		output, err := ProbabilityAtLeast(test.input, test.target)
+
+		if math.Abs(output-test.want) > test.tolerance+1e-9 || err != nil {
+			t.Errorf("have %v, wanted %v for %d on %q, err %v", output, test.want, test.target, test.input, err)
+		}
+	}
+
+	for _, input := range []string{"", "2d6 please", "2d0"} {
+		if _, err := ProbabilityAtLeast(input, 2); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+}
+
+// BenchmarkProbabilityAtLeast benchmarks diceroller.ProbabilityAtLeast.
+func BenchmarkProbabilityAtLeast(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ProbabilityAtLeast("2d6+3", 11)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// Winner says who won an opposed roll.
+type Winner int
+
+const (
+	WinnerNone     Winner = iota // The totals tied.
+	WinnerAttacker               // The attacker's total was higher.
+	WinnerDefender               // The defender's total was higher.
+)
+
+/*
+ * String returns the winner as a word.
+ * e.g. "attacker"
+ */
+func (winner Winner) String() string {
+	switch winner {
+	case WinnerAttacker:
+		return "attacker"
+	case WinnerDefender:
+		return "defender"
+	default:
+		return "none"
+	}
+}
+
+// OpposedRoll holds the details of an opposed (or contested) roll.
+type OpposedRoll struct {
+	Attacker DiceRoll // The attacker's roll.
+	Defender DiceRoll // The defender's roll.
+	Winner   Winner   // Who won, or WinnerNone for a tie.
+	Margin   int      // How much the winner won by, or 0 for a tie.
+}
+
+/*
+ * RollOpposed accepts two strings in the correct 'nDn+n' format, one for the attacker and one for the defender, rolls both and compares the totals.
+ * Ties are reported as such, as systems differ on who wins them.
+ * e.g. RollOpposed("1d20+5", "1d20+3") // {{1d20+5 20 1 5 [12] 17 ...} {1d20+3 20 1 3 [9] 12 ...} attacker 5}
+ */
+func RollOpposed(attacker, defender string) (output OpposedRoll, err error) {
+	output.Attacker, err = roll(attacker)
+	if err != nil {
+		return
+	}
+
+	output.Defender, err = roll(defender)
+	if err != nil {
+		return
+	}
+
+	output.Margin = output.Attacker.Total - output.Defender.Total
+
+	switch {
+	case output.Margin > 0:
+		output.Winner = WinnerAttacker
+	case output.Margin < 0:
+		output.Winner = WinnerDefender
+		output.Margin = -output.Margin
+	}
+
+	return
+}
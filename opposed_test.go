@@ -0,0 +1,80 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rollOpposedTest struct {
+	attacker, defender string
+	want               OpposedRoll
+}
+
+var rollOpposedTests = []rollOpposedTest{
+	{"1d20+5", "1d20+3", OpposedRoll{
+		DiceRoll{DiscoveredRoll: "1d20+5", Faces: 20, Rolls: 1, Modifier: 5, Results: []int{19}, Total: 24, NaturalTotal: 19},
+		DiceRoll{DiscoveredRoll: "1d20+3", Faces: 20, Rolls: 1, Modifier: 3, Results: []int{10}, Total: 13, NaturalTotal: 10},
+		WinnerAttacker, 11,
+	}},
+	{"1d6", "1d6+2", OpposedRoll{
+		DiceRoll{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Modifier: 0, Results: []int{6}, Total: 6, NaturalTotal: 6},
+		DiceRoll{DiscoveredRoll: "1d6+2", Faces: 6, Rolls: 1, Modifier: 2, Results: []int{4}, Total: 6, NaturalTotal: 4},
+		WinnerNone, 0,
+	}},
+	{"1d4", "1d4+10", OpposedRoll{
+		DiceRoll{DiscoveredRoll: "1d4", Faces: 4, Rolls: 1, Modifier: 0, Results: []int{4}, Total: 4, NaturalTotal: 4},
+		DiceRoll{DiscoveredRoll: "1d4+10", Faces: 4, Rolls: 1, Modifier: 10, Results: []int{3}, Total: 13, NaturalTotal: 3},
+		WinnerDefender, 9,
+	}},
+}
+
+// TestRollOpposed calls diceroller.RollOpposed with pairs of valid dice roll strings, checking for valid return values.
+func TestRollOpposed(t *testing.T) {
+	reseed()
+
+	for _, test := range rollOpposedTests {
+		output, err := RollOpposed(test.attacker, test.defender)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	if _, err := RollOpposed("1d20", "nonsense"); err == nil {
+		t.Errorf("have nil error for a defender with no roll, wanted an error")
+	}
+}
+
+// TestWinnerString calls diceroller.Winner.String, checking for valid return values.
+func TestWinnerString(t *testing.T) {
+	for winner, want := range map[Winner]string{WinnerNone: "none", WinnerAttacker: "attacker", WinnerDefender: "defender"} {
+		if output := winner.String(); output != want {
+			t.Errorf("have %q, wanted %q", output, want)
+		}
+	}
+}
+
+// BenchmarkRollOpposed benchmarks diceroller.RollOpposed.
+func BenchmarkRollOpposed(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollOpposed(rollOpposedTests[0].attacker, rollOpposedTests[0].defender)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+ * checkedAdd adds two numbers, returning ErrOverflow rather than wrapping around if the sum doesn't fit in an int.
+ */
+func checkedAdd(a, b int) (int, error) {
+	if (b > 0 && a > math.MaxInt-b) || (b < 0 && a < math.MinInt-b) {
+		return 0, fmt.Errorf("%w: %d + %d", ErrOverflow, a, b)
+	}
+
+	return a + b, nil
+}
+
+/*
+ * checkedMul multiplies two numbers which aren't negative, returning ErrOverflow rather than wrapping around if the product doesn't
+ *   fit in an int.
+ */
+func checkedMul(a, b int) (int, error) {
+	if a != 0 && b > math.MaxInt/a {
+		return 0, fmt.Errorf("%w: %d × %d", ErrOverflow, a, b)
+	}
+
+	return a * b, nil
+}
+
+/*
+ * checkedAbs returns how far a number is from zero, returning ErrOverflow for math.MinInt, which has no positive int.
+ */
+func checkedAbs(a int) (int, error) {
+	if a == math.MinInt {
+		return 0, fmt.Errorf("%w: -(%d)", ErrOverflow, a)
+	}
+
+	if a < 0 {
+		return -a, nil
+	}
+
+	return a, nil
+}
+
+/*
+ * checkTotal returns ErrOverflow if the roll's total could be too big for an int, either way, before any dice are rolled. Every die
+ *   can be at most Faces (or a higher minimum), plus the size of the per-die modifier, so the total is at most that many times the
+ *   dice, plus the size of the modifier. An exploding die can be rolled maxExplosions more times, so counts as that many more dice.
+ *   '99999d99999++99999+99999' is fine on 64-bit platforms, but not on 32-bit ones.
+ */
+func (spec rollSpec) checkTotal() error {
+	dieModifier, err := checkedAbs(spec.dieModifier)
+	if err != nil {
+		return err
+	}
+
+	modifier, err := checkedAbs(spec.modifier)
+	if err != nil {
+		return err
+	}
+
+	perDie, err := checkedAdd(max(spec.die.Faces, spec.minimum), dieModifier)
+	if err != nil {
+		return err
+	}
+
+	rolls := spec.rolls
+	if spec.explode > 0 {
+		if rolls, err = checkedMul(spec.rolls, 1+maxExplosions); err != nil {
+			return fmt.Errorf("%q could total more than an int can hold: %w", spec.discovered, err)
+		}
+	}
+
+	dice, err := checkedMul(rolls, perDie)
+	if err == nil {
+		_, err = checkedAdd(dice, modifier)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%q could total more than an int can hold: %w", spec.discovered, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type checkedTest struct {
+	a, b    int
+	want    int
+	wantErr bool
+}
+
+var checkedAddTests = []checkedTest{
+	{1, 2, 3, false},
+	{-5, 3, -2, false},
+	{math.MaxInt, 0, math.MaxInt, false},
+	{math.MaxInt, 1, 0, true},
+	{math.MinInt, -1, 0, true},
+	{math.MinInt, 1, math.MinInt + 1, false},
+}
+
+var checkedMulTests = []checkedTest{
+	{0, math.MaxInt, 0, false},
+	{9999, 9999, 99_980_001, false},
+	{math.MaxInt / 2, 2, math.MaxInt - 1, false},
+	{math.MaxInt / 2, 3, 0, true},
+}
+
+// TestChecked calls diceroller.checkedAdd and diceroller.checkedMul, checking for valid return values and ErrOverflow.
+func TestChecked(t *testing.T) {
+	for _, test := range checkedAddTests {
+		if output, err := checkedAdd(test.a, test.b); output != test.want || errors.Is(err, ErrOverflow) != test.wantErr {
+			t.Errorf("have %d, err %v, wanted %d, error %t for %d + %d", output, err, test.want, test.wantErr, test.a, test.b)
+		}
+	}
+
+	for _, test := range checkedMulTests {
+		if output, err := checkedMul(test.a, test.b); output != test.want || errors.Is(err, ErrOverflow) != test.wantErr {
+			t.Errorf("have %d, err %v, wanted %d, error %t for %d × %d", output, err, test.want, test.wantErr, test.a, test.b)
+		}
+	}
+
+	if _, err := checkedAbs(math.MinInt); !errors.Is(err, ErrOverflow) {
+		t.Errorf("have err %v, wanted %v", err, ErrOverflow)
+	}
+}
+
+// TestOverflow rolls rolls which could total more than an int can hold, checking they return ErrOverflow rather than rolling.
+func TestOverflow(t *testing.T) {
+	if _, err := RollDie(DieSpec{Faces: math.MaxInt}, 2, 0); !errors.Is(err, ErrOverflow) {
+		t.Errorf("have err %v, wanted %v", err, ErrOverflow)
+	}
+
+	if _, err := RollDie(DieSpec{Faces: 6}, 1, math.MinInt); !errors.Is(err, ErrOverflow) {
+		t.Errorf("have err %v, wanted %v", err, ErrOverflow)
+	}
+
+	if _, err := RollDie(DieSpec{Faces: math.MaxInt}, 1, 0); err != nil {
+		t.Errorf("have err %v, wanted none", err)
+	}
+
+	// The biggest roll the notation allows fits on 64-bit platforms, but not 32-bit ones.
+	if _, err := RollOne("99999d99999++99999+99999"); (err != nil) != (math.MaxInt == math.MaxInt32) {
+		t.Errorf("have err %v for a %d-bit int", err, 32<<(^uint(0)>>63))
+	}
+
+	// Exploding dice can each be rolled maxExplosions more times, and a minimum can be over the faces, so rolls which fit without
+	// them can overflow with them.
+	faces := 1 << 20
+	fits := rollSpec{discovered: "near", rolls: math.MaxInt / faces / 50, die: DieSpec{Faces: faces}}
+	if err := fits.checkTotal(); err != nil {
+		t.Errorf("have err %v, wanted none", err)
+	}
+
+	exploding, raised := fits, fits
+	exploding.explode = faces
+	raised.minimum = faces * 200
+
+	for _, spec := range []rollSpec{exploding, raised} {
+		if err := spec.checkTotal(); !errors.Is(err, ErrOverflow) {
+			t.Errorf("have err %v for %+v, wanted %v", err, spec, ErrOverflow)
+		}
+	}
+
+	roller := NewRoller(WithFoundry(nil))
+	if _, err := roller.RollOne("30000d1000"); err != nil {
+		t.Errorf("have err %v, wanted none", err)
+	}
+
+	if _, err := roller.RollOne("30000d1000x"); (err != nil) != (math.MaxInt == math.MaxInt32) || (err != nil && !errors.Is(err, ErrOverflow)) {
+		t.Errorf("have err %v for exploding dice on a %d-bit int", err, 32<<(^uint(0)>>63))
+	}
+}
+
+// BenchmarkCheckTotal benchmarks diceroller.rollSpec.checkTotal.
+func BenchmarkCheckTotal(b *testing.B) {
+	spec, _ := findRoll("4d6++1+2")
+
+	for i := 0; i < b.N; i++ {
+		_ = spec.checkTotal()
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The files an Overlay writes, in its Dir.
+const (
+	OverlayJSONFile = "overlay.json"
+	OverlayHTMLFile = "overlay.html"
+)
+
+// The page an Overlay writes, for an OBS browser source. It reloads itself to pick up new rolls, and has a transparent background.
+var overlayTemplate = template.Must(template.New(OverlayHTMLFile).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>{{if .Title}}{{.Title}}{{else}}Dice{{end}}</title>
+<style>
+body { background: transparent; color: #fff; font: bold 32px sans-serif; text-shadow: 2px 2px 4px #000; margin: 0; }
+h1 { font-size: 24px; margin: 0 0 8px; }
+.roll { margin: 4px 0; }
+</style>
+</head>
+<body>
+{{if .Title}}<h1>{{.Title}}</h1>
+{{end}}{{range .Rolls}}<div class="roll"><span class="text">{{.Text}}</span></div>
+{{end}}</body>
+</html>
+`))
+
+// Overlay writes the latest rolls to files for streaming software to show on screen: JSON for custom overlays, and an HTML page
+// which can be used directly as an OBS browser source. Each file is replaced in one go, so nothing ever reads half a file.
+type Overlay struct {
+	Dir     string        // Where to write overlay.json and overlay.html. It must already exist.
+	Title   string        // An optional heading above the rolls, e.g. a character's name.
+	Refresh time.Duration // How often the HTML page reloads. Zero means every second.
+}
+
+// OverlayRoll is one roll as an Overlay writes it.
+type OverlayRoll struct {
+	Roll     string `json:"roll"`              // The discovered roll, e.g. '4d6kh3'.
+	Results  []int  `json:"results"`           // Each die.
+	Dropped  []int  `json:"dropped,omitempty"` // Indexes into Results of any dice which don't count towards the total.
+	Modifier int    `json:"modifier"`          // The modifier added to the total.
+	Natural  int    `json:"natural_total"`     // The total of the dice, without any modifiers.
+	Total    int    `json:"total"`             // The total.
+	Text     string `json:"text"`              // The roll prettified, e.g. '2d6+1: 3 + 5 (+1) = 9'.
+}
+
+// OverlayState is everything an Overlay writes to its JSON file.
+type OverlayState struct {
+	Title   string        `json:"title,omitempty"`
+	Updated time.Time     `json:"updated"` // When the rolls were written, so overlays can tell a new roll from the same total again.
+	Rolls   []OverlayRoll `json:"rolls"`
+}
+
+/*
+ * Write replaces the overlay's JSON and HTML files with the given rolls. Writing no rolls clears the overlay.
+ * e.g. overlay.Write(rolls...)
+ */
+func (overlay Overlay) Write(rolls ...DiceRoll) error {
+	state := OverlayState{Title: overlay.Title, Updated: time.Now().UTC(), Rolls: make([]OverlayRoll, len(rolls))}
+
+	for i, roll := range rolls {
+		state.Rolls[i] = OverlayRoll{Roll: roll.DiscoveredRoll, Results: roll.Results, Dropped: roll.Dropped, Modifier: roll.Modifier,
+			Natural: roll.NaturalTotal, Total: roll.Total, Text: PrettifyOneFull(roll)}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := overlay.replace(OverlayJSONFile, append(data, '\n')); err != nil {
+		return err
+	}
+
+	refresh := overlay.Refresh
+	if refresh <= 0 {
+		refresh = time.Second
+	}
+
+	var page bytes.Buffer
+	if err := overlayTemplate.Execute(&page, struct {
+		OverlayState
+		Refresh float64
+	}{state, refresh.Seconds()}); err != nil {
+		return err
+	}
+
+	return overlay.replace(OverlayHTMLFile, page.Bytes())
+}
+
+/*
+ * replace writes the data to a temporary file in the overlay's directory, then renames it over the named file.
+ */
+func (overlay Overlay) replace(name string, data []byte) error {
+	file, err := os.CreateTemp(overlay.Dir, "."+name+".*")
+	if err != nil {
+		return fmt.Errorf("can't write overlay: %w", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	// CreateTemp makes the file private, but anything serving the overlay needs to read it.
+	if err := file.Chmod(0o644); err != nil {
+		file.Close()
+		return fmt.Errorf("can't write overlay: %w", err)
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("can't write overlay: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("can't write overlay: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), filepath.Join(overlay.Dir, name)); err != nil {
+		return fmt.Errorf("can't write overlay: %w", err)
+	}
+
+	return nil
+}
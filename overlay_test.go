@@ -0,0 +1,99 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOverlayWrite calls diceroller.Overlay.Write, checking the JSON and HTML files are written, and replaced by later rolls.
+func TestOverlayWrite(t *testing.T) {
+	overlay := Overlay{Dir: t.TempDir(), Title: "Grog <the Mighty>", Refresh: 2 * time.Second}
+	rolls := []DiceRoll{
+		{DiscoveredRoll: "2d6+1", Faces: 6, Rolls: 2, Modifier: 1, Results: []int{3, 5}, Total: 9, NaturalTotal: 8},
+		{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Results: []int{1, 4, 6, 2}, Dropped: []int{0}, Total: 12, NaturalTotal: 12},
+	}
+
+	before := time.Now().UTC()
+	if err := overlay.Write(rolls...); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	var state OverlayState
+	data, err := os.ReadFile(filepath.Join(overlay.Dir, OverlayJSONFile))
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	want := []OverlayRoll{
+		{Roll: "2d6+1", Results: []int{3, 5}, Modifier: 1, Natural: 8, Total: 9, Text: PrettifyOneFull(rolls[0])},
+		{Roll: "4d6kh3", Results: []int{1, 4, 6, 2}, Dropped: []int{0}, Natural: 12, Total: 12, Text: PrettifyOneFull(rolls[1])},
+	}
+
+	if !reflect.DeepEqual(state.Rolls, want) || state.Title != overlay.Title || state.Updated.Before(before.Truncate(time.Second)) {
+		t.Errorf("have %+v, wanted %+v titled %q updated after %v", state, want, overlay.Title, before)
+	}
+
+	page, err := os.ReadFile(filepath.Join(overlay.Dir, OverlayHTMLFile))
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	for _, want := range []string{`content="2"`, "Grog &lt;the Mighty&gt;", "4d6kh3:", "= 12"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("have %q, wanted it to contain %q", page, want)
+		}
+	}
+
+	if err := overlay.Write(); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(overlay.Dir, OverlayJSONFile)); !strings.Contains(string(data), `"rolls": []`) {
+		t.Errorf("have %s, wanted the rolls cleared", data)
+	}
+
+	if entries, _ := os.ReadDir(overlay.Dir); len(entries) != 2 {
+		t.Errorf("have %d files, wanted only the 2 overlay files left behind", len(entries))
+	}
+
+	if err := (Overlay{Dir: filepath.Join(overlay.Dir, "missing")}).Write(rolls...); err == nil {
+		t.Error("have no error, wanted one writing to a missing directory")
+	}
+}
+
+// BenchmarkOverlayWrite benchmarks diceroller.Overlay.Write.
+func BenchmarkOverlayWrite(b *testing.B) {
+	overlay := Overlay{Dir: b.TempDir()}
+	roll := DiceRoll{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Results: []int{3, 5}, Total: 8}
+
+	for i := 0; i < b.N; i++ {
+		_ = overlay.Write(roll)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// PbtAOutcome is how a Powered by the Apocalypse move turned out.
+type PbtAOutcome int
+
+const (
+	PbtAMiss      PbtAOutcome = iota // 6 or less.
+	PbtAWeakHit                      // 7 to 9.
+	PbtAStrongHit                    // 10 or more.
+)
+
+/*
+ * String returns the outcome in words.
+ * e.g. "weak hit"
+ */
+func (outcome PbtAOutcome) String() string {
+	switch outcome {
+	case PbtAWeakHit:
+		return "weak hit"
+	case PbtAStrongHit:
+		return "strong hit"
+	default:
+		return "miss"
+	}
+}
+
+// PbtARoll holds the details of a Powered by the Apocalypse move.
+type PbtARoll struct {
+	Roll    DiceRoll    // The 2d6+stat roll.
+	Outcome PbtAOutcome // How it turned out.
+}
+
+/*
+ * RollPbtA rolls 2d6 plus a stat and buckets the total into a miss (6 or less), weak hit (7 to 9) or strong hit (10 or more).
+ * e.g. RollPbtA(1) // {{2d6+1 6 2 1 [4 3] 8 ...} weak hit}
+ */
+func RollPbtA(stat int) (output PbtARoll, err error) {
+	return defaultRoller.RollPbtA(stat)
+}
+
+/*
+ * RollPbtA is like the package's RollPbtA, but rolls with the Roller's random source.
+ * e.g. roller.RollPbtA(1)
+ */
+func (roller *Roller) RollPbtA(stat int) (output PbtARoll, err error) {
+	if output.Roll, err = roller.rollParsed(newRollSpec(2, DieSpec{Faces: 6}, stat)); err != nil {
+		return
+	}
+
+	output.Roll, err = postProcess(output.Roll)
+	if err != nil {
+		return
+	}
+
+	output.Outcome = pbtaOutcome(output.Roll.Total)
+
+	return
+}
+
+/*
+ * pbtaOutcome buckets a 2d6+stat total.
+ */
+func pbtaOutcome(total int) PbtAOutcome {
+	switch {
+	case total >= 10:
+		return PbtAStrongHit
+	case total >= 7:
+		return PbtAWeakHit
+	default:
+		return PbtAMiss
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+var pbtaOutcomeTests = map[int]PbtAOutcome{
+	-1: PbtAMiss,
+	6:  PbtAMiss,
+	7:  PbtAWeakHit,
+	9:  PbtAWeakHit,
+	10: PbtAStrongHit,
+	15: PbtAStrongHit,
+}
+
+// TestPbtAOutcome calls diceroller.pbtaOutcome with many totals, checking for valid return values.
+func TestPbtAOutcome(t *testing.T) {
+	for total, want := range pbtaOutcomeTests {
+		if output := pbtaOutcome(total); output != want {
+			t.Errorf("have %v, wanted %v for %d", output, want, total)
+		}
+	}
+}
+
+type rollPbtATest struct {
+	stat int
+	want PbtARoll
+}
+
+var rollPbtATests = []rollPbtATest{
+	{1, PbtARoll{DiceRoll{DiscoveredRoll: "2d6+1", Faces: 6, Rolls: 2, Modifier: 1, Results: []int{6, 3}, Total: 10, NaturalTotal: 9}, PbtAStrongHit}},
+	{0, PbtARoll{DiceRoll{DiscoveredRoll: "2d6", Faces: 6, Rolls: 2, Modifier: 0, Results: []int{6, 4}, Total: 10, NaturalTotal: 10}, PbtAStrongHit}},
+	{-2, PbtARoll{DiceRoll{DiscoveredRoll: "2d6-2", Faces: 6, Rolls: 2, Modifier: -2, Results: []int{6, 1}, Total: 5, NaturalTotal: 7}, PbtAMiss}},
+}
+
+// TestRollPbtA calls diceroller.RollPbtA with many stats, checking for valid return values.
+func TestRollPbtA(t *testing.T) {
+	reseed()
+
+	for _, test := range rollPbtATests {
+		output, err := RollPbtA(test.stat)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+// TestPbtAOutcomeString calls diceroller.PbtAOutcome.String, checking for valid return values.
+func TestPbtAOutcomeString(t *testing.T) {
+	for outcome, want := range map[PbtAOutcome]string{PbtAMiss: "miss", PbtAWeakHit: "weak hit", PbtAStrongHit: "strong hit"} {
+		if output := outcome.String(); output != want {
+			t.Errorf("have %q, wanted %q", output, want)
+		}
+	}
+}
+
+// BenchmarkRollPbtA benchmarks diceroller.RollPbtA.
+func BenchmarkRollPbtA(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollPbtA(1)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The most coins which can be flipped at once, the same as the most dice which can be rolled.
+const maxCoins = 99_999
+
+// ErrNothingToPick is returned when picking more from a slice than it holds.
+var ErrNothingToPick = errors.New("not enough to pick from")
+
+// CoinFlips is the result of flipping some coins.
+type CoinFlips struct {
+	Flips []bool // Each flip in the order flipped: true for heads, false for tails.
+	Heads int    // How many came up heads.
+	Tails int    // How many came up tails.
+}
+
+/*
+ * String returns each flip as 'H' or 'T', in the order flipped.
+ * e.g. "HTTH"
+ */
+func (flips CoinFlips) String() string {
+	var output strings.Builder
+
+	for _, heads := range flips.Flips {
+		if heads {
+			output.WriteByte('H')
+		} else {
+			output.WriteByte('T')
+		}
+	}
+
+	return output.String()
+}
+
+/*
+ * FlipCoins flips the given number of coins, from the same random source as the dice, and returns the flips and how many of each.
+ * e.g. FlipCoins(4) // {[true false false true] 2 2}
+ */
+func FlipCoins(count int) (output CoinFlips, err error) {
+	return defaultRoller.FlipCoins(count)
+}
+
+/*
+ * FlipCoins is like the package's FlipCoins, but flips with the Roller's random source.
+ * e.g. roller.FlipCoins(4) // {[true false false true] 2 2}
+ */
+func (roller *Roller) FlipCoins(count int) (output CoinFlips, err error) {
+	if count < 1 || count > maxCoins {
+		return CoinFlips{}, fmt.Errorf("can't flip %d coins: from 1 to %d can be flipped", count, maxCoins)
+	}
+
+	output.Flips = make([]bool, count)
+	source := roller.source()
+
+	for i := range output.Flips {
+		output.Flips[i] = source.IntN(2) == 0
+		if output.Flips[i] {
+			output.Heads++
+		}
+	}
+
+	output.Tails = count - output.Heads
+
+	return output, nil
+}
+
+/*
+ * PickOne picks one item from the slice at random, from the same random source as the dice, or returns ErrNothingToPick if it's empty.
+ * e.g. PickOne([]string{"goblin", "orc", "troll"}) // "orc"
+ */
+func PickOne[T any](items []T) (output T, err error) {
+	return PickOneFrom(defaultRoller, items)
+}
+
+/*
+ * PickOneFrom is like PickOne, but picks with the Roller's random source, e.g. a seeded or cryptographic one.
+ * e.g. PickOneFrom(roller, []string{"goblin", "orc", "troll"}) // "orc"
+ */
+func PickOneFrom[T any](roller *Roller, items []T) (output T, err error) {
+	if len(items) == 0 {
+		return output, fmt.Errorf("%w: picking 1 from 0", ErrNothingToPick)
+	}
+
+	return items[roller.source().IntN(len(items))], nil
+}
+
+/*
+ * PickN picks the given number of different items from the slice at random, without replacement, in the order picked. It returns
+ *   ErrNothingToPick if the slice doesn't have that many. The slice isn't changed.
+ * e.g. PickN([]string{"goblin", "orc", "troll"}, 2) // ["troll", "goblin"]
+ */
+func PickN[T any](items []T, count int) ([]T, error) {
+	return PickNFrom(defaultRoller, items, count)
+}
+
+/*
+ * PickNFrom is like PickN, but picks with the Roller's random source.
+ * e.g. PickNFrom(roller, []string{"goblin", "orc", "troll"}, 2) // ["troll", "goblin"]
+ */
+func PickNFrom[T any](roller *Roller, items []T, count int) ([]T, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("can't pick %d: the count can't be negative", count)
+	}
+
+	if count > len(items) {
+		return nil, fmt.Errorf("%w: picking %d from %d", ErrNothingToPick, count, len(items))
+	}
+
+	// A partial Fisher-Yates shuffle of a copy: only as many swaps as there are picks.
+	var (
+		pool   = slices.Clone(items)
+		source = roller.source()
+	)
+
+	for i := range count {
+		j := i + source.IntN(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+
+	return pool[:count:count], nil
+}
@@ -0,0 +1,139 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestFlipCoins calls diceroller.FlipCoins, checking for valid return values.
+func TestFlipCoins(t *testing.T) {
+	reseed()
+
+	flips, err := FlipCoins(8)
+	if flips.String() != "TTHTTHTH" || flips.Heads != 3 || flips.Tails != 5 || err != nil {
+		t.Errorf("have %v with %d heads and %d tails, wanted TTHTTHTH with 3 and 5, err %v", flips, flips.Heads, flips.Tails, err)
+	}
+
+	for _, count := range []int{-1, 0, maxCoins + 1} {
+		if _, err := FlipCoins(count); err == nil {
+			t.Errorf("have no error, wanted one flipping %d coins", count)
+		}
+	}
+}
+
+// TestRollerFlipCoins calls diceroller.Roller.FlipCoins, checking the coins are flipped with the Roller's source.
+func TestRollerFlipCoins(t *testing.T) {
+	for range 2 {
+		random.IntN(6)
+
+		flips, err := NewRoller(WithSeed(42, 1024)).FlipCoins(8)
+		if flips.String() != "TTHTTHTH" || err != nil {
+			t.Errorf("have %v, wanted TTHTTHTH, err %v", flips, err)
+		}
+	}
+
+	if _, err := NewRoller().FlipCoins(0); err == nil {
+		t.Errorf("have no error, wanted one flipping 0 coins")
+	}
+}
+
+// TestPick calls diceroller.PickOne and diceroller.PickN, checking for valid return values and errors.
+func TestPick(t *testing.T) {
+	reseed()
+
+	items := []string{"goblin", "orc", "troll", "ogre", "dragon"}
+
+	if output, err := PickOne(items); output != "dragon" || err != nil {
+		t.Errorf("have %q, wanted %q, err %v", output, "dragon", err)
+	}
+
+	if output, err := PickN(items, 3); !reflect.DeepEqual(output, []string{"troll", "ogre", "orc"}) || err != nil {
+		t.Errorf("have %v, wanted [troll ogre orc], err %v", output, err)
+	}
+
+	if want := []string{"goblin", "orc", "troll", "ogre", "dragon"}; !reflect.DeepEqual(items, want) {
+		t.Errorf("have %v, wanted the slice unchanged as %v", items, want)
+	}
+
+	if output, err := PickN(items, 0); len(output) != 0 || err != nil {
+		t.Errorf("have %v, wanted nothing, err %v", output, err)
+	}
+
+	all, _ := PickN(items, len(items))
+	seen := map[string]bool{}
+	for _, item := range all {
+		seen[item] = true
+	}
+
+	if len(seen) != len(items) {
+		t.Errorf("have %v, wanted every item once", all)
+	}
+
+	if _, err := PickOne([]int{}); !errors.Is(err, ErrNothingToPick) {
+		t.Errorf("have err %v, wanted ErrNothingToPick", err)
+	}
+
+	if _, err := PickN(items, 6); !errors.Is(err, ErrNothingToPick) {
+		t.Errorf("have err %v, wanted ErrNothingToPick", err)
+	}
+
+	if _, err := PickN(items, -1); err == nil {
+		t.Error("have no error, wanted one picking -1")
+	}
+}
+
+// TestPickFrom calls diceroller.PickOneFrom and diceroller.PickNFrom, checking items are picked with the Roller's source.
+func TestPickFrom(t *testing.T) {
+	items := []string{"goblin", "orc", "troll", "ogre", "dragon"}
+
+	for range 2 {
+		random.IntN(6)
+		roller := NewRoller(WithSeed(42, 1024))
+
+		if output, err := PickOneFrom(roller, items); output != "dragon" || err != nil {
+			t.Errorf("have %q, wanted %q, err %v", output, "dragon", err)
+		}
+
+		if output, err := PickNFrom(roller, items, 3); !reflect.DeepEqual(output, []string{"troll", "ogre", "orc"}) || err != nil {
+			t.Errorf("have %v, wanted [troll ogre orc], err %v", output, err)
+		}
+	}
+
+	roller := NewRoller(WithCryptoRand())
+
+	if _, err := PickOneFrom(roller, []int{}); !errors.Is(err, ErrNothingToPick) {
+		t.Errorf("have err %v, wanted ErrNothingToPick", err)
+	}
+
+	if output, err := PickNFrom(roller, items, len(items)); len(output) != len(items) || err != nil {
+		t.Errorf("have %v, wanted all %d items, err %v", output, len(items), err)
+	}
+}
+
+// BenchmarkPickN benchmarks diceroller.PickN.
+func BenchmarkPickN(b *testing.B) {
+	items := make([]int, 100)
+
+	for i := 0; i < b.N; i++ {
+		_, _ = PickN(items, 10)
+	}
+}
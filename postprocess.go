@@ -0,0 +1,107 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PostProcessor transforms a finished DiceRoll before it's returned, e.g. to apply a house rule. Returning an error fails the roll.
+type PostProcessor func(roll DiceRoll) (DiceRoll, error)
+
+type namedPostProcessor struct {
+	name      string
+	processor PostProcessor
+}
+
+var (
+	postProcessorsMu sync.RWMutex
+	postProcessors   []namedPostProcessor
+)
+
+/*
+ * AddPostProcessor registers a PostProcessor under a name, to run on every roll after the ones already registered.
+ *   Adding one with a name already in use replaces that one, keeping its place in the order.
+ * e.g. AddPostProcessor("minimum-1", func(roll DiceRoll) (DiceRoll, error) { roll.Total = max(roll.Total, 1); return roll, nil })
+ */
+func AddPostProcessor(name string, processor PostProcessor) {
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+
+	// Copy rather than change in place, as rolls in progress may be using the old slice.
+	processors := append([]namedPostProcessor(nil), postProcessors...)
+
+	for i := range processors {
+		if processors[i].name == name {
+			processors[i].processor = processor
+			postProcessors = processors
+
+			return
+		}
+	}
+
+	postProcessors = append(processors, namedPostProcessor{name, processor})
+}
+
+/*
+ * RemovePostProcessor unregisters the PostProcessor with a name, if there is one.
+ */
+func RemovePostProcessor(name string) {
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+
+	for i := range postProcessors {
+		if postProcessors[i].name == name {
+			postProcessors = append(postProcessors[:i:i], postProcessors[i+1:]...)
+			return
+		}
+	}
+}
+
+/*
+ * ClearPostProcessors unregisters every PostProcessor.
+ */
+func ClearPostProcessors() {
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+
+	postProcessors = nil
+}
+
+/*
+ * postProcess runs a roll through each registered PostProcessor in order, stopping at the first error. The NaturalTotal is filled
+ *   in first, as whatever made the roll may have changed the dice, e.g. by exploding them.
+ */
+func postProcess(input DiceRoll) (output DiceRoll, err error) {
+	postProcessorsMu.RLock()
+	processors := postProcessors
+	postProcessorsMu.RUnlock()
+
+	output = input
+	sumNatural(&output)
+
+	for _, named := range processors {
+		if output, err = named.processor(output); err != nil {
+			return DiceRoll{}, fmt.Errorf("post-processor %q: %w", named.name, err)
+		}
+	}
+
+	return
+}
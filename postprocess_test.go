@@ -0,0 +1,81 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestPostProcessors registers post-processors and rolls, checking they run in order, can be replaced and removed, and can fail a roll.
+func TestPostProcessors(t *testing.T) {
+	t.Cleanup(ClearPostProcessors)
+
+	var order []string
+
+	AddPostProcessor("double", func(roll DiceRoll) (DiceRoll, error) {
+		order = append(order, "double")
+		roll.Total *= 2
+
+		return roll, nil
+	})
+	AddPostProcessor("plus-one", func(roll DiceRoll) (DiceRoll, error) {
+		order = append(order, "plus-one")
+		roll.Total++
+
+		return roll, nil
+	})
+
+	output, err := RollDetails("1d1+1")
+	if err != nil || output[0].Total != 5 || len(order) != 2 || order[0] != "double" {
+		t.Errorf("have %d %v, err %v, wanted 5 [double plus-one]", output[0].Total, order, err)
+	}
+
+	// Replacing keeps the place in the order.
+	AddPostProcessor("double", func(roll DiceRoll) (DiceRoll, error) {
+		roll.Total *= 3
+
+		return roll, nil
+	})
+
+	if output, _ := RollDie(DieSpec{Faces: 1}, 2, 0); output.Total != 7 {
+		t.Errorf("have %d, wanted 7", output.Total)
+	}
+
+	RemovePostProcessor("double")
+
+	if total, _ := RollOne("1d1"); total != 2 {
+		t.Errorf("have %d, wanted 2", total)
+	}
+
+	errNope := errors.New("nope")
+	AddPostProcessor("fail", func(roll DiceRoll) (DiceRoll, error) {
+		return roll, errNope
+	})
+
+	if _, err := RollDetails("1d6"); !errors.Is(err, errNope) || err.Error() != `post-processor "fail": nope` {
+		t.Errorf("have err %v, wanted %v", err, errNope)
+	}
+
+	ClearPostProcessors()
+
+	if total, _ := RollOne("1d1"); total != 1 {
+		t.Errorf("have %d, wanted 1", total)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RulesProfile bundles a group's rules into one shareable JSON document, to load at startup or per room.
+type RulesProfile struct {
+	Name         string            `json:"name,omitempty"`          // What the profile is called, e.g. 'Thursday night 5e'.
+	HouseRules   HouseRules        `json:"house_rules"`             // The house rules for checks, attacks and damage.
+	CriticalDice []int             `json:"critical_dice,omitempty"` // The dice sizes which get NaturalMax and NaturalMin filled in. Empty means d20s.
+	Macros       map[string]string `json:"macros,omitempty"`        // Named rolls, by name.
+}
+
+/*
+ * LoadRulesProfile reads a RulesProfile from JSON, rejecting unknown fields so typos don't go unnoticed, and checking any macros are valid.
+ * e.g. LoadRulesProfile(strings.NewReader(`{"name": "gritty", "house_rules": {"crit_range": 19}}`))
+ */
+func LoadRulesProfile(r io.Reader) (output RulesProfile, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(&output); err != nil {
+		return RulesProfile{}, fmt.Errorf("reading rules profile: %w", err)
+	}
+
+	for name, roll := range output.Macros {
+		if _, err = newMacro(name, roll, "", MacroScope{}); err != nil {
+			return RulesProfile{}, fmt.Errorf("rules profile macro %q: %w", name, err)
+		}
+	}
+
+	return
+}
+
+/*
+ * Save writes the RulesProfile as indented JSON, ready to share.
+ */
+func (profile RulesProfile) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(profile)
+}
+
+/*
+ * Apply sets the package-wide parts of the profile, currently the critical dice, for use at startup. The house rules are used by calling
+ *   their methods, and the macros with MacroStore, so they can differ per room.
+ */
+func (profile RulesProfile) Apply() {
+	if len(profile.CriticalDice) == 0 {
+		SetCriticalDice(20)
+		return
+	}
+
+	SetCriticalDice(profile.CriticalDice...)
+}
+
+/*
+ * MacroStore returns a new MacroStore holding the profile's macros in the global scope.
+ */
+func (profile RulesProfile) MacroStore() (*MacroStore, error) {
+	store := NewMacroStore()
+
+	for name, roll := range profile.Macros {
+		if err := store.Set(name, roll); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
@@ -0,0 +1,95 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const rulesProfile = `{
+  "name": "Thursday night",
+  "house_rules": {
+    "crit_range": 19,
+    "max_damage_crits": true,
+    "minimum_total": 1
+  },
+  "critical_dice": [
+    20,
+    100
+  ],
+  "macros": {
+    "attack": "1d20+7, @damage",
+    "damage": "2d6+4"
+  }
+}
+`
+
+// TestLoadRulesProfile calls diceroller.LoadRulesProfile with a profile, checking it's read, saves the same way, and applies.
+func TestLoadRulesProfile(t *testing.T) {
+	t.Cleanup(func() { SetCriticalDice(20) })
+
+	profile, err := LoadRulesProfile(strings.NewReader(rulesProfile))
+	if err != nil {
+		t.Fatalf("have err %v, wanted nil", err)
+	}
+
+	want := RulesProfile{
+		Name:         "Thursday night",
+		HouseRules:   HouseRules{CritRange: 19, MaxDamageCrits: true, MinimumTotal: 1},
+		CriticalDice: []int{20, 100},
+		Macros:       map[string]string{"attack": "1d20+7, @damage", "damage": "2d6+4"},
+	}
+
+	if !reflect.DeepEqual(profile, want) {
+		t.Errorf("have %+v, wanted %+v", profile, want)
+	}
+
+	var saved bytes.Buffer
+	if err := profile.Save(&saved); err != nil || saved.String() != rulesProfile {
+		t.Errorf("have %s, wanted %s, err %v", saved.String(), rulesProfile, err)
+	}
+
+	profile.Apply()
+
+	if !reflect.DeepEqual(criticalDice, []int{20, 100}) {
+		t.Errorf("have %v, wanted %v", criticalDice, []int{20, 100})
+	}
+
+	store, err := profile.MacroStore()
+	if rolls, _ := store.Expand("", "", "attack"); !reflect.DeepEqual(rolls, []string{"1d20+7", "2d6+4"}) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", rolls, []string{"1d20+7", "2d6+4"}, err)
+	}
+}
+
+// TestLoadRulesProfileErrors calls diceroller.LoadRulesProfile with bad profiles, checking for errors.
+func TestLoadRulesProfileErrors(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`{"name": "typo", "house_rule": {}}`,
+		`{"house_rules": {"crit_range": "nineteen"}}`,
+		`{"macros": {"fireball": "lots"}}`,
+	} {
+		if _, err := LoadRulesProfile(strings.NewReader(input)); err == nil {
+			t.Errorf("have nil error for %s, wanted an error", input)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Strictness is how much of an input has to be a valid roll, for RollRecover.
+type Strictness int
+
+const (
+	StrictnessLenient Strictness = iota // Roll the first roll found, silently ignoring anything around it, as RollDetails does.
+	StrictnessRecover                   // Roll the first roll found, with a Diagnostic for anything around it which was ignored.
+	StrictnessStrict                    // The whole input has to be one roll, or it's an error, with the Diagnostics saying why.
+)
+
+/*
+ * String returns the strictness's name, e.g. 'recover'.
+ */
+func (strictness Strictness) String() string {
+	switch strictness {
+	case StrictnessLenient:
+		return "lenient"
+	case StrictnessRecover:
+		return "recover"
+	case StrictnessStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("Strictness(%d)", int(strictness))
+	}
+}
+
+// Diagnostic is part of an input which wasn't part of the roll, e.g. the '+foo' in '2d6+foo'.
+type Diagnostic struct {
+	Offset  int    // Where the ignored text starts in the input, in bytes.
+	Text    string // The ignored text, without any white space around it.
+	Message string // What was wrong, e.g. 'ignored "+foo" after the roll'.
+}
+
+/*
+ * String returns the diagnostic's message and where it is.
+ * e.g. "ignored \"+foo\" after the roll, at 3"
+ */
+func (diagnostic Diagnostic) String() string {
+	return fmt.Sprintf("%s, at %d", diagnostic.Message, diagnostic.Offset)
+}
+
+/*
+ * ParseStrict is like Parse, but each input has to be exactly one roll, ignoring white space, or it returns ErrNotJustRoll, so
+ *   e.g. '2d6/2' fails rather than being read as '2d6'. It returns each roll as it will be rolled.
+ * e.g. ParseStrict("2d6 + 3", "4d6kh3") // []string{"2d6+3", "4d6kh3"}
+ */
+func ParseStrict(input ...string) (output []string, err error) {
+	for _, in := range input {
+		spec, err := parseWholeRoll(in)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, spec.discovered)
+	}
+
+	return output, nil
+}
+
+/*
+ * RollStrict is like RollDetails, but each input has to be exactly one roll, ignoring white space, or it returns ErrNotJustRoll
+ *   saying what was in the way. It's the same as RollRecover with StrictnessStrict for each input.
+ * e.g. RollStrict("2d6/2") // nil, "\"2d6/2\" is not just a dice roll: ignored \"/2\" after the roll, did you mean 2d6+2?"
+ */
+func RollStrict(input ...string) (output []DiceRoll, err error) {
+	for _, in := range input {
+		dr, _, err := RollRecover(StrictnessStrict, in)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, dr)
+	}
+
+	return output, nil
+}
+
+/*
+ * RollRecover rolls the first roll in the input, and with StrictnessRecover or StrictnessStrict, returns a Diagnostic for any text
+ *   before or after it which isn't part of it. White space is taken out as Parse does. With StrictnessStrict, any diagnostics are
+ *   also an error, and nothing is rolled; errors end with what Suggest suggests, if anything. With StrictnessLenient, it's the same
+ *   as RollDetails.
+ * e.g. RollRecover(StrictnessRecover, "2d6+foo") // {2d6 6 2 0 [6 3] 9 ...}, [{3 +foo ignored "+foo" after the roll}], nil
+ */
+func RollRecover(strictness Strictness, input string) (output DiceRoll, diagnostics []Diagnostic, err error) {
+	if strictness == StrictnessLenient {
+		output, err = roll(input)
+		return
+	}
+
+	// Take the white space out, as Parse does, remembering where each byte left came from.
+	stripped, offsets := stripSpaceOffsets(input)
+
+	matches := matchRolls(stripped, 1)
+	if len(matches) == 0 {
+		err = fmt.Errorf("%w in %q", ErrNoRollFound, input)
+		if suggestion, ok := Suggest(input); ok {
+			err = fmt.Errorf("%w in %q, %s", ErrNoRollFound, input, suggestion)
+		}
+
+		return
+	}
+
+	match := matches[0]
+	if match[0] > 0 {
+		diagnostics = append(diagnostics, newDiagnostic(input, offsets[0], offsets[match[0]], "before"))
+	}
+
+	if match[1] < len(stripped) {
+		diagnostics = append(diagnostics, newDiagnostic(input, offsets[match[1]], len(input), "after"))
+	}
+
+	if strictness == StrictnessStrict && len(diagnostics) > 0 {
+		err = fmt.Errorf("%q is %w: %s", input, ErrNotJustRoll, diagnostics[0].Message)
+		if suggestion, ok := Suggest(input); ok {
+			err = fmt.Errorf("%q is %w: %s, %s", input, ErrNotJustRoll, diagnostics[0].Message, suggestion)
+		}
+
+		return
+	}
+
+	output, err = roll(stripped[match[0]:match[1]])
+
+	return
+}
+
+/*
+ * newDiagnostic returns a Diagnostic for the part of the input between start and end, which was before or after the roll.
+ */
+func newDiagnostic(input string, start, end int, where string) Diagnostic {
+	text := strings.TrimRightFunc(input[start:end], unicode.IsSpace)
+
+	return Diagnostic{Offset: start, Text: text, Message: fmt.Sprintf("ignored %q %s the roll", text, where)}
+}
@@ -0,0 +1,140 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type rollRecoverTest struct {
+	strictness      Strictness
+	input           string
+	wantRoll        string // The discovered roll, or empty if there shouldn't be one.
+	wantDiagnostics []Diagnostic
+	wantErr         bool
+}
+
+var rollRecoverTests = []rollRecoverTest{
+	{StrictnessLenient, "2d6+foo", "2d6", nil, false},
+	{StrictnessLenient, "2d6 + 3", "2d6", nil, false},
+	{StrictnessRecover, "2d6+3", "2d6+3", nil, false},
+	{StrictnessRecover, " 2d6 + 3 ", "2d6+3", nil, false},
+	{StrictnessRecover, "2d6+foo", "2d6", []Diagnostic{{3, "+foo", `ignored "+foo" after the roll`}}, false},
+	{StrictnessRecover, "2d6/2", "2d6", []Diagnostic{{3, "/2", `ignored "/2" after the roll`}}, false},
+	{StrictnessRecover, "roll  4d6kh3 for STR", "4d6kh3", []Diagnostic{{0, "roll", `ignored "roll" before the roll`}, {13, "for STR", `ignored "for STR" after the roll`}}, false},
+	{StrictnessRecover, "4d6s and", "4d6s", []Diagnostic{{5, "and", `ignored "and" after the roll`}}, false},
+	{StrictnessRecover, "2d6 swords", "2d6", []Diagnostic{{4, "swords", `ignored "swords" after the roll`}}, false},
+	{StrictnessRecover, "nothing", "", nil, true},
+	{StrictnessStrict, " 2d6 + 3 ", "2d6+3", nil, false},
+	{StrictnessStrict, "2d6+foo", "", []Diagnostic{{3, "+foo", `ignored "+foo" after the roll`}}, true},
+	{StrictnessStrict, "x1d20", "", []Diagnostic{{0, "x", `ignored "x" before the roll`}}, true},
+	{StrictnessStrict, "", "", nil, true},
+}
+
+// TestRollRecover calls diceroller.RollRecover with each strictness, checking the roll, the diagnostics, and errors.
+func TestRollRecover(t *testing.T) {
+	for _, test := range rollRecoverTests {
+		output, diagnostics, err := RollRecover(test.strictness, test.input)
+		if output.DiscoveredRoll != test.wantRoll || !reflect.DeepEqual(diagnostics, test.wantDiagnostics) || (err != nil) != test.wantErr {
+			t.Errorf("have %q, %v, err %v, wanted %q, %v, error %t for %s %q", output.DiscoveredRoll, diagnostics, err, test.wantRoll,
+				test.wantDiagnostics, test.wantErr, test.strictness, test.input)
+		}
+	}
+}
+
+// TestRollRecoverParse calls diceroller.RollRecover and diceroller.Parse with the same inputs, checking they find the same roll.
+func TestRollRecoverParse(t *testing.T) {
+	for _, input := range []string{"4d6s and", "4d6 s", "2d6 swords", "2d6 + 3", "roll  4d6kh3 for STR", "1d20\tdisadvantage", " 3d8 sd "} {
+		parsed, _ := Parse(input)
+		output, _, err := RollRecover(StrictnessRecover, input)
+		if len(parsed) == 0 || output.DiscoveredRoll != parsed[0] || err != nil {
+			t.Errorf("have %q, err %v, wanted %v for %q", output.DiscoveredRoll, err, parsed, input)
+		}
+	}
+}
+
+// TestStrictnessString calls diceroller.Strictness.String, checking for valid return values.
+func TestStrictnessString(t *testing.T) {
+	for strictness, want := range map[Strictness]string{StrictnessLenient: "lenient", StrictnessRecover: "recover", StrictnessStrict: "strict", 9: "Strictness(9)"} {
+		if strictness.String() != want {
+			t.Errorf("have %q, wanted %q", strictness.String(), want)
+		}
+	}
+
+	if want := `ignored "+foo" after the roll, at 3`; rollRecoverTests[4].wantDiagnostics[0].String() != want {
+		t.Errorf("have %q, wanted %q", rollRecoverTests[4].wantDiagnostics[0].String(), want)
+	}
+}
+
+type strictTest struct {
+	input []string
+	want  []string
+	err   error
+}
+
+var strictTests = []strictTest{
+	{[]string{"2d6"}, []string{"2d6"}, nil},
+	{[]string{" 2d6 + 3 ", "4d6kh3"}, []string{"2d6+3", "4d6kh3"}, nil},
+	{[]string{"2d6/2"}, nil, ErrNotJustRoll},
+	{[]string{"1d20", "roll 1d20"}, nil, ErrNotJustRoll},
+	{[]string{"nothing"}, nil, ErrNoRollFound},
+	{[]string{"0d6"}, nil, ErrZeroDice},
+}
+
+// TestParseStrict calls diceroller.ParseStrict, checking for valid return values and the right errors.
+func TestParseStrict(t *testing.T) {
+	for _, test := range strictTests {
+		output, err := ParseStrict(test.input...)
+		if !reflect.DeepEqual(output, test.want) || !errors.Is(err, test.err) {
+			t.Errorf("have %q, err %v, wanted %q, error %v for %q", output, err, test.want, test.err, test.input)
+		}
+	}
+}
+
+// TestRollStrict calls diceroller.RollStrict, checking the discovered rolls and the right errors.
+func TestRollStrict(t *testing.T) {
+	for _, test := range strictTests {
+		output, err := RollStrict(test.input...)
+
+		var rolls []string
+		for _, dr := range output {
+			rolls = append(rolls, dr.DiscoveredRoll)
+		}
+
+		if !reflect.DeepEqual(rolls, test.want) || !errors.Is(err, test.err) {
+			t.Errorf("have %q, err %v, wanted %q, error %v for %q", rolls, err, test.want, test.err, test.input)
+		}
+	}
+}
+
+// BenchmarkRollStrict benchmarks diceroller.RollStrict.
+func BenchmarkRollStrict(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollStrict("4d6kh3")
+	}
+}
+
+// BenchmarkRollRecover benchmarks diceroller.RollRecover.
+func BenchmarkRollRecover(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _, _ = RollRecover(StrictnessRecover, "roll 4d6kh3 for STR")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RepeatedRoll is a roll and how many times it was found in the same input, as ParseRepeats finds them.
+type RepeatedRoll struct {
+	Roll  string // The roll, as first found.
+	Count int    // How many times it was found.
+}
+
+/*
+ * String returns the roll, labelled with how many times it was found if it was found more than once.
+ * e.g. "2d6 (x3)"
+ */
+func (roll RepeatedRoll) String() string {
+	if roll.Count == 1 {
+		return roll.Roll
+	}
+
+	return fmt.Sprintf("%s (x%d)", roll.Roll, roll.Count)
+}
+
+/*
+ * ParseRepeats is like Parse, but returns each distinct roll once, in the order it was first found, along with how many times it was
+ *   found. Rolls which differ only by case or white space, e.g. '2d6' and '2 D 6', are the same roll.
+ * e.g. ParseRepeats("2d6, 1d20 and 2D6, 2d6") // [{2d6 3} {1d20 1}]
+ */
+func ParseRepeats(input ...string) (output []RepeatedRoll, err error) {
+	rolls, err := Parse(input...)
+	if err != nil {
+		return
+	}
+
+	seen := map[string]int{}
+
+	for _, roll := range rolls {
+		key := strings.ToLower(roll)
+
+		if i, ok := seen[key]; ok {
+			output[i].Count++
+			continue
+		}
+
+		seen[key] = len(output)
+		output = append(output, RepeatedRoll{Roll: roll, Count: 1})
+	}
+
+	return
+}
+
+/*
+ * ParseUnique is like Parse, but returns each distinct roll once, in the order it was first found, as ParseRepeats does.
+ * e.g. ParseUnique("2d6, 1d20 and 2D6, 2d6") // ["2d6", "1d20"]
+ */
+func ParseUnique(input ...string) (output []string, err error) {
+	repeats, err := ParseRepeats(input...)
+	if err != nil {
+		return
+	}
+
+	for _, repeat := range repeats {
+		output = append(output, repeat.Roll)
+	}
+
+	return
+}
+
+/*
+ * PrettifyRepeats is like PrettifyFull, but puts rolls of the same dice together on one line, labelled with how many there were, in
+ *   the order each was first rolled.
+ * e.g. []string{"2d6 (x3): 3 + 4 = 7, 2 + 2 = 4, 6 + 6 = 12", "1d20: 17"}
+ */
+func PrettifyRepeats(input []DiceRoll) (output []string) {
+	var (
+		groups [][]DiceRoll
+		seen   = map[string]int{}
+	)
+
+	for _, in := range input {
+		key := strings.ToLower(in.DiscoveredRoll)
+
+		if i, ok := seen[key]; ok {
+			groups[i] = append(groups[i], in)
+			continue
+		}
+
+		seen[key] = len(groups)
+		groups = append(groups, []DiceRoll{in})
+	}
+
+	output = make([]string, len(groups))
+
+	for i, group := range groups {
+		if len(group) == 1 {
+			output[i] = prettify(group[0], true)
+			continue
+		}
+
+		label := RepeatedRoll{Roll: strings.ToLower(group[0].DiscoveredRoll), Count: len(group)}
+		output[i] = label.String() + ": " + strings.Join(Prettify(group), ", ")
+	}
+
+	return
+}
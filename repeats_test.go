@@ -0,0 +1,88 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type parseRepeatsTest struct {
+	got        []string
+	want       []RepeatedRoll
+	wantUnique []string
+	wantLabels []string
+}
+
+var parseRepeatsTests = []parseRepeatsTest{
+	{[]string{"2d6, 1d20 and 2D6, 2d6"}, []RepeatedRoll{{"2d6", 3}, {"1d20", 1}}, []string{"2d6", "1d20"}, []string{"2d6 (x3)", "1d20"}},
+	{[]string{"2 d 6", "and 2d6+1", "then 2d6"}, []RepeatedRoll{{"2d6", 2}, {"2d6+1", 1}}, []string{"2d6", "2d6+1"}, []string{"2d6 (x2)", "2d6+1"}},
+	{[]string{"1d4, 1d6, 1d8"}, []RepeatedRoll{{"1d4", 1}, {"1d6", 1}, {"1d8", 1}}, []string{"1d4", "1d6", "1d8"}, []string{"1d4", "1d6", "1d8"}},
+	{[]string{"nothing to roll"}, nil, nil, nil},
+}
+
+// TestParseRepeats calls diceroller.ParseRepeats and diceroller.ParseUnique with many inputs, checking for valid return values.
+func TestParseRepeats(t *testing.T) {
+	for _, test := range parseRepeatsTests {
+		output, err := ParseRepeats(test.got...)
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v for %q, err %v", output, test.want, test.got, err)
+		}
+
+		var labels []string
+		for _, repeat := range output {
+			labels = append(labels, repeat.String())
+		}
+
+		if !reflect.DeepEqual(labels, test.wantLabels) {
+			t.Errorf("have %q, wanted %q for %q", labels, test.wantLabels, test.got)
+		}
+
+		if unique, err := ParseUnique(test.got...); !reflect.DeepEqual(unique, test.wantUnique) || err != nil {
+			t.Errorf("have %q, wanted %q for %q, err %v", unique, test.wantUnique, test.got, err)
+		}
+	}
+}
+
+// TestPrettifyRepeats calls diceroller.PrettifyRepeats, checking repeated rolls are put together and labelled.
+func TestPrettifyRepeats(t *testing.T) {
+	input := []DiceRoll{
+		{DiscoveredRoll: "2d6", Results: []int{6, 3}, Total: 9},
+		{DiscoveredRoll: "1d20", Results: []int{19}, Total: 19},
+		{DiscoveredRoll: "2D6", Results: []int{4, 6}, Total: 10},
+		{DiscoveredRoll: "2d6", Results: []int{1, 3}, Total: 4},
+		{DiscoveredRoll: "4d6kh3", Results: []int{4, 2, 2, 1}, Dropped: []int{3}, Total: 8},
+	}
+
+	want := []string{"2d6 (x3): 6 + 3 = 9, 4 + 6 = 10, 1 + 3 = 4", "1d20: 19", "4d6kh3: 4 + 2 + 2 + ~~1~~ = 8"}
+	if output := PrettifyRepeats(input); !reflect.DeepEqual(output, want) {
+		t.Errorf("have %q, wanted %q", output, want)
+	}
+
+	if output := PrettifyRepeats(nil); len(output) != 0 {
+		t.Errorf("have %q, wanted nothing", output)
+	}
+}
+
+// BenchmarkParseRepeats benchmarks diceroller.ParseRepeats.
+func BenchmarkParseRepeats(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseRepeats(parseRepeatsTests[0].got...)
+	}
+}
@@ -0,0 +1,346 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// The regex used to locate Roll20 rolls, e.g. d20, 2d20kh1, 4d6dl1, 2d6ro<2, 1d20cs>19, 8d6sd+2. The number of dice is optional,
+	//   and the options can come in any order.
+	roll20Regex = regexp.MustCompile(
+		`(?P<rolls>\d{1,5})?[dD](?P<faces>\d{1,5})` + // The dice, e.g. 2d20 or d20.
+			`(?P<options>(?i:[kd][hl]?\d{1,5}|ro?[<>=]?\d{1,5}|c[sf][<>=]?\d{1,5}|s[ad]?)*)` + // Keeping, dropping, rerolling, criticals and sorting.
+			`(?P<modifier>[\+-]\d{1,5})?`, // A modifier, e.g. 2d6+2.
+	)
+
+	// The regex used to split a Roll20 roll's options up, e.g. 'kh1ro<2' into 'kh1' and 'ro<2'.
+	roll20OptionRegex = regexp.MustCompile(`(?i)([kd][hl]?|ro?|c[sf]|s[ad]?)([<>=]?)(\d{0,5})`)
+
+	// The regex used to read what comes after a Roll20 group, e.g. the 'kh1+2' of '{1d20+5,1d20+8}kh1+2'.
+	roll20GroupRegex = regexp.MustCompile(`^(?:(?i:([kd][hl]?))(\d{1,5}))?([\+-]\d{1,5})?`)
+)
+
+/*
+ * WithRoll20 makes a Roller understand Roll20's dice notation instead of its own, so macros from Roll20 don't have to be rewritten:
+ *   inline rolls ('[[1d20+5]]'), optional numbers of dice ('d20'), keeping and dropping dice ('kh1', 'kl1', 'dl1', 'dh1'),
+ *   rerolling once ('ro<2'), critical ranges ('cs>19', 'cf<2', counted in NaturalMax and NaturalMin), sorting ('sa', 'sd'), and
+ *   groups of rolls keeping the highest or lowest totals ('{1d20+5,1d20+8}kh1'), whose Results are the total of each roll.
+ *   Rerolling more than once ('r<2'), and rerolling or criticals on one face which isn't the lowest or highest, are errors.
+ * e.g. NewRoller(WithRoll20()).RollOne("[[2d20kh1+5]]") // 22
+ */
+func WithRoll20() RollerOption {
+	return func(roller *Roller) {
+		roller.notation = &notation{roll: (*Roller).rollRoll20, dice: (*Roller).countRoll20Dice}
+	}
+}
+
+/*
+ * rollRoll20 rolls the first roll in a string in Roll20's notation with the Roller's random source, without post-processing it. An
+ *   inline roll is rolled on its own, ignoring the text around it.
+ */
+func (roller *Roller) rollRoll20(input string) (output DiceRoll, err error) {
+	input, group := roll20Input(input)
+	if group {
+		return roller.rollRoll20Group(input)
+	}
+
+	spec, err := roller.parseWith(input, parseRoll20)
+	if err != nil {
+		return
+	}
+
+	return roller.rollParsed(spec)
+}
+
+/*
+ * countRoll20Dice counts how many dice rollRoll20 would roll, across all the rolls of a group.
+ */
+func (roller *Roller) countRoll20Dice(input string) (total int, err error) {
+	input, group := roll20Input(input)
+	if !group {
+		spec, err := roller.parseWith(input, parseRoll20)
+
+		return spec.rolls, err
+	}
+
+	translated, _ := roller.translate(input)
+	specs, _, _, err := parseRoll20Group(translated)
+	for _, spec := range specs {
+		total += spec.rolls
+	}
+
+	return
+}
+
+/*
+ * roll20Input returns the part of a string rollRoll20 rolls: an inline roll on its own, and from a group's '{' if it comes before any
+ *   other roll, when it also returns true.
+ */
+func roll20Input(input string) (string, bool) {
+	input = inputReplacer.Replace(input)
+
+	if _, inline, found := strings.Cut(input, "[["); found {
+		input, _, _ = strings.Cut(inline, "]]")
+	}
+
+	// A group is only rolled if it comes before any other roll.
+	if open := strings.IndexByte(input, '{'); open >= 0 {
+		if match := roll20Regex.FindStringIndex(input); match == nil || open < match[0] {
+			return input[open:], true
+		}
+	}
+
+	return input, false
+}
+
+/*
+ * rollRoll20Group rolls a group of Roll20 rolls starting with its '{', keeping the highest or lowest totals if it asks to. The dice
+ *   of all its rolls count towards WithMaxTotalDice, and none are rolled if they're over it.
+ */
+func (roller *Roller) rollRoll20Group(input string) (output DiceRoll, err error) {
+	translated, written := roller.translate(input)
+
+	specs, discovered, result, err := parseRoll20Group(translated)
+	if err != nil {
+		return
+	}
+
+	var dice int
+	for _, spec := range specs {
+		dice += spec.rolls
+		if err = roller.limits.checkTotalDice(dice); err != nil {
+			return
+		}
+	}
+
+	output.DiscoveredRoll = written(discovered)
+
+	var rolls []DiceRoll
+
+	for _, spec := range specs {
+		roll, err := roller.rollParsed(spec)
+		if err != nil {
+			return DiceRoll{}, err
+		}
+
+		rolls = append(rolls, roll)
+		output.Results = append(output.Results, roll.Total)
+
+		if output.Total, err = checkedAdd(output.Total, roll.Total); err != nil {
+			return DiceRoll{}, err
+		}
+	}
+
+	output.Rolls = len(rolls)
+
+	if result[1] != "" {
+		count, err := strconv.Atoi(result[2])
+		if err != nil {
+			return DiceRoll{}, err
+		}
+
+		keep, keepCount := roll20Keep(strings.ToLower(result[1]), count, output.Rolls)
+		keepDice(&output, keep, keepCount)
+	}
+
+	if result[3] != "" {
+		if output.Modifier, err = strconv.Atoi(result[3]); err != nil {
+			return DiceRoll{}, err
+		}
+
+		output.Total += output.Modifier
+	}
+
+	sumNatural(&output)
+
+	// The group's naturals are those of the rolls which were kept.
+	for i, roll := range rolls {
+		if !slices.Contains(output.Dropped, i) {
+			output.NaturalMax += roll.NaturalMax
+			output.NaturalMin += roll.NaturalMin
+		}
+	}
+
+	return
+}
+
+/*
+ * parseRoll20Group breaks down the rolls of a group of Roll20 rolls starting with its '{', each of which has to be a roll and nothing
+ *   else. It also returns the group as discovered, and what comes after it, e.g. the 'kh1' and '+2' of '{1d20+5,1d20+8}kh1+2'.
+ */
+func parseRoll20Group(input string) (specs []rollSpec, discovered string, result []string, err error) {
+	body, rest, found := strings.Cut(input[1:], "}")
+	if !found {
+		err = fmt.Errorf("%w in %q: the group has no closing '}'", ErrNoRollFound, input)
+		return
+	}
+
+	result = roll20GroupRegex.FindStringSubmatch(rest)
+	discovered = "{" + body + "}" + result[0]
+
+	for _, part := range strings.Split(body, ",") {
+		spec, err := parseRoll20(part)
+		if err != nil {
+			return nil, discovered, result, err
+		}
+
+		if spec.discovered != part {
+			return nil, discovered, result, fmt.Errorf("%q in %q is %w", part, discovered, ErrNotJustRoll)
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return
+}
+
+/*
+ * parseRoll20 finds the first roll in Roll20's notation in a string and breaks it down into a rollSpec, checking it can be rolled.
+ */
+func parseRoll20(input string) (spec rollSpec, err error) {
+	result := roll20Regex.FindStringSubmatch(input)
+	if result == nil {
+		err = fmt.Errorf("%w in %q", ErrNoRollFound, input)
+		return
+	}
+
+	spec.discovered = result[0]
+
+	// Unlike this package's notation, the number of dice is optional.
+	spec.rolls = 1
+	if rolls := result[roll20Regex.SubexpIndex("rolls")]; len(rolls) > 0 {
+		if spec.rolls, err = strconv.Atoi(rolls); err != nil {
+			return
+		}
+	}
+
+	if spec.die.Faces, err = strconv.Atoi(result[roll20Regex.SubexpIndex("faces")]); err != nil {
+		return
+	}
+
+	for _, option := range roll20OptionRegex.FindAllStringSubmatch(result[roll20Regex.SubexpIndex("options")], -1) {
+		if spec, err = parseRoll20Option(spec, strings.ToLower(option[1]), option[2], option[3]); err != nil {
+			return
+		}
+	}
+
+	if modifier := result[roll20Regex.SubexpIndex("modifier")]; len(modifier) > 0 {
+		if spec.modifier, err = strconv.Atoi(modifier); err != nil {
+			return
+		}
+	}
+
+	err = spec.validate()
+
+	return
+}
+
+/*
+ * parseRoll20Option fills in the part of a rollSpec one Roll20 option asks for, e.g. 'kh1' or 'ro<2'. In Roll20, '<' and '>' include
+ *   the number, so 'ro<2' rerolls 1s and 2s.
+ */
+func parseRoll20Option(spec rollSpec, option, compare, number string) (rollSpec, error) {
+	if option == "s" || option == "sa" || option == "sd" {
+		spec.sort = strings.TrimSuffix(option, "a")
+		return spec, nil
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return spec, err
+	}
+
+	switch option {
+	case "r":
+		return spec, fmt.Errorf("%q rerolls more than once, but only rerolling once, e.g. 'ro<2', is supported", spec.discovered)
+	case "ro":
+		if compare != "<" && (compare == ">" || n != 1) {
+			return spec, fmt.Errorf("%q can only reroll dice at or under a number, e.g. 'ro<2'", spec.discovered)
+		}
+
+		spec.reroll = n
+	case "cs":
+		if compare != ">" && (compare == "<" || n != spec.die.Faces) {
+			return spec, fmt.Errorf("%q can only count critical successes at or over a number, e.g. 'cs>19'", spec.discovered)
+		}
+
+		spec.criticalAt = n
+	case "cf":
+		if compare != "<" && (compare == ">" || n != 1) {
+			return spec, fmt.Errorf("%q can only count critical failures at or under a number, e.g. 'cf<2'", spec.discovered)
+		}
+
+		spec.fumbleAt = n
+	default:
+		if spec.keep != "" {
+			return spec, fmt.Errorf("%q can only keep or drop dice once", spec.discovered)
+		}
+
+		spec.keep, spec.keepCount = roll20Keep(option, n, spec.rolls)
+	}
+
+	return spec, nil
+}
+
+/*
+ * roll20Keep turns Roll20's keeping and dropping ('k', 'kh', 'kl', 'd', 'dl' and 'dh') into this package's keeping, given how many
+ *   dice are kept or dropped, and how many there are.
+ */
+func roll20Keep(option string, count, rolls int) (keep string, keepCount int) {
+	switch option {
+	case "k", "kh":
+		return "kh", count
+	case "kl":
+		return "kl", count
+	case "d", "dl":
+		return "kh", max(rolls-count, 0)
+	default:
+		return "kl", max(rolls-count, 0)
+	}
+}
+
+/*
+ * countThresholds counts how many kept dice came up at or over criticalAt and at or under fumbleAt, for Roll20's 'cs>n' and 'cf<n',
+ *   replacing NaturalMax and NaturalMin. Either can be 0 to leave it alone.
+ */
+func countThresholds(output *DiceRoll, criticalAt, fumbleAt int) {
+	if criticalAt > 0 {
+		output.NaturalMax = 0
+	}
+
+	if fumbleAt > 0 {
+		output.NaturalMin = 0
+	}
+
+	for _, v := range keptResults(*output) {
+		if criticalAt > 0 && v >= criticalAt {
+			output.NaturalMax++
+		}
+
+		if fumbleAt > 0 && v <= fumbleAt {
+			output.NaturalMin++
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type roll20Test struct {
+	input          string
+	wantDiscovered string
+	wantResults    []int
+	wantDropped    []int
+	wantTotal      int
+	wantNaturalMax int
+}
+
+// With WithSeed(42, 1024), d6s come up 6, 3, 6, 4, 6, 1, 3, 4 and d20s come up 19, 10, 19.
+var roll20Tests = []roll20Test{
+	{"[[2d20kh1+5]]", "2d20kh1+5", []int{19, 10}, []int{1}, 24, 0},
+	{"Attack: [[ d20 + 5 ]] to hit", "d20+5", []int{19}, nil, 24, 0},
+	{"d6+2", "d6+2", []int{6}, nil, 8, 0},
+	{"4d6k3", "4d6k3", []int{6, 3, 6, 4}, []int{1}, 16, 0},
+	{"4d6kl1", "4d6kl1", []int{6, 3, 6, 4}, []int{0, 2, 3}, 3, 0},
+	{"4d6dl1", "4d6dl1", []int{6, 3, 6, 4}, []int{1}, 16, 0},
+	{"4d6dh1", "4d6dh1", []int{6, 3, 6, 4}, []int{2}, 13, 0},
+	{"2d6ro<2", "2d6ro<2", []int{6, 3}, nil, 9, 0},
+	{"3d20cs>19", "3d20cs>19", []int{19, 10, 19}, nil, 48, 2},
+	{"3d20cs>19kh1", "3d20cs>19kh1", []int{19, 10, 19}, []int{0, 1}, 19, 1},
+	{"8d6sa", "8d6sa", []int{1, 3, 3, 4, 4, 6, 6, 6}, nil, 33, 0},
+	{"8d6sd+2", "8d6sd+2", []int{6, 6, 6, 4, 4, 3, 3, 1}, nil, 35, 0},
+	{"{1d20+5, 1d20+8}kh1", "{1d20+5,1d20+8}kh1", []int{24, 18}, []int{1}, 24, 0},
+	{"{2d6,2d6,2d6}dl1+2", "{2d6,2d6,2d6}dl1+2", []int{9, 10, 7}, []int{2}, 21, 0},
+	{"Save: [[{4d6}]]", "{4d6}", []int{19}, nil, 19, 0},
+}
+
+// TestRoll20 rolls Roll20's notation with a diceroller.Roller made with WithRoll20, checking for valid return values.
+func TestRoll20(t *testing.T) {
+	for _, test := range roll20Tests {
+		output, err := NewRoller(WithSeed(42, 1024), WithRoll20()).RollDetails(test.input)
+		if err != nil {
+			t.Fatalf("have err %v for %q", err, test.input)
+		}
+
+		roll := output[0]
+		if roll.DiscoveredRoll != test.wantDiscovered || !reflect.DeepEqual(roll.Results, test.wantResults) ||
+			!reflect.DeepEqual(roll.Dropped, test.wantDropped) || roll.Total != test.wantTotal || roll.NaturalMax != test.wantNaturalMax {
+			t.Errorf("have %+v, wanted %q %v dropping %v totalling %d with %d naturals", roll, test.wantDiscovered, test.wantResults,
+				test.wantDropped, test.wantTotal, test.wantNaturalMax)
+		}
+	}
+}
+
+// TestRoll20Thresholds prettifies Roll20 rolls with critical and fumble ranges, checking dice counted only by the range are called
+// crits and fumbles, not natural 20s and 1s.
+func TestRoll20Thresholds(t *testing.T) {
+	for input, want := range map[string]string{
+		"1d20cs>19": "19 (crit)",
+		"3d20cs>19": "19 + 10 + 19 = 48 (crit x2)",
+		"4d6cf<3":   "6 + 3 + 6 + 4 = 19 (fumble)",
+		"1d20cs>10": "19 (crit)",
+		"1d20":      "19",
+	} {
+		output, _ := NewRoller(WithSeed(42, 1024), WithRoll20()).RollDetails(input)
+		if pretty := PrettifyOne(output[0]); pretty != want {
+			t.Errorf("have %q, wanted %q from %q", pretty, want, input)
+		}
+	}
+
+	// A natural 20 within the range is still a natural 20.
+	roll := DiceRoll{DiscoveredRoll: "2d20cs>19", Faces: 20, Rolls: 2, Results: []int{20, 20}, Total: 40, NaturalTotal: 40, NaturalMax: 2}
+	if pretty := PrettifyOne(roll); pretty != "20 + 20 = 40 (nat 20 x2)" {
+		t.Errorf("have %q, wanted natural 20s", pretty)
+	}
+}
+
+// TestRoll20Errors rolls bad and unsupported Roll20 notation, checking for errors.
+func TestRoll20Errors(t *testing.T) {
+	roller := NewRoller(WithRoll20(), WithMaxDice(10))
+
+	for input, want := range map[string]error{
+		"nothing":         ErrNoRollFound,
+		"0d6":             ErrZeroDice,
+		"2d0":             ErrZeroFaces,
+		"11d6":            ErrLimitExceeded,
+		"{1d20":           ErrNoRollFound,
+		"{1d20+1d4}":      ErrNotJustRoll,
+		"{1d20, nothing}": ErrNoRollFound,
+		"{1d20,11d6}kh1":  ErrLimitExceeded,
+	} {
+		if _, err := roller.RollOne(input); !errors.Is(err, want) {
+			t.Errorf("have err %v for %q, wanted %v", err, input, want)
+		}
+	}
+
+	for _, input := range []string{"2d6r<2", "2d6ro3", "2d6ro>3", "1d20cs19", "1d20cs<5", "1d20cf2", "4d6kh3dl1"} {
+		if _, err := roller.RollOne(input); err == nil {
+			t.Errorf("have nil error for %q, wanted an error", input)
+		}
+	}
+
+	// Without WithRoll20, the package's own notation is read as before.
+	if output, err := NewRoller().RollDetails("[[d20]]"); err == nil {
+		t.Errorf("have %v, wanted an error without WithRoll20", output)
+	}
+}
+
+// BenchmarkRoll20 benchmarks rolling Roll20's notation.
+func BenchmarkRoll20(b *testing.B) {
+	roller := NewRoller(WithRoll20())
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollOne("[[{2d20kh1+5, 1d20ro<2+8}kh1]]")
+	}
+}
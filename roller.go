@@ -0,0 +1,377 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// The Roller the package's Roll functions use, with the package's random source.
+var defaultRoller = &Roller{}
+
+// Roller rolls dice with its own random source, so rolls can be deterministic, or kept apart from everything else rolling dice,
+// e.g. one Roller per game, or per test. The package's Roll functions use a default Roller, with the package's random source.
+// Rolls still go through the post-processors. It's safe for concurrent use, but goroutines sharing a Roller take turns with its
+// source, so a busy server can give each goroutine its own.
+type Roller struct {
+	random  RandSource // Where the dice get their random numbers from, or nil for the package's random source.
+	limits  limits     // How big its rolls can be.
+	history *History   // The rolls it's made, if it was made with WithHistory.
+	letters []rune     // Other letters it takes for the 'd' in its rolls, if it was made with WithDiceLetters.
+
+	signingKey []byte // The key it signs rolls with, if it was made with WithSigningKey.
+
+	notation *notation // How it reads rolls in another notation, if it was made with e.g. WithRoll20, or nil for its own notation.
+}
+
+// notation is how a Roller reads and rolls rolls in a notation other than its own, e.g. Roll20's.
+type notation struct {
+	roll func(roller *Roller, input string) (DiceRoll, error) // Reads and rolls one roll, without post-processing it.
+	dice func(roller *Roller, input string) (int, error)      // Reads how many dice one roll rolls, for WithMaxTotalDice, if it can.
+}
+
+// RandSource is where a Roller gets its random numbers from, e.g. random.org, a hardware random number generator, or a recorded
+// sequence. IntN returns a number from 0 to n-1, which has to be in range: a die with n faces comes up IntN(n)+1. A *rand.Rand is
+// a RandSource, and NewRandSource adapts any math/rand/v2 source.
+type RandSource interface {
+	IntN(n int) int
+}
+
+// A RollerOption configures a Roller made with NewRoller.
+type RollerOption func(*Roller)
+
+// lockedSource makes a random source safe for concurrent use. rand.Rand keeps no state of its own, so a Rand made from one is
+// safe too.
+type lockedSource struct {
+	mu     sync.Mutex
+	source rand.Source
+}
+
+/*
+ * Uint64 returns the next random number from the source.
+ */
+func (locked *lockedSource) Uint64() uint64 {
+	locked.mu.Lock()
+	defer locked.mu.Unlock()
+
+	return locked.source.Uint64()
+}
+
+/*
+ * NewRandSource adapts a math/rand/v2 source, e.g. a rand.PCG or rand.ChaCha8, to a RandSource. It's safe for concurrent use, even if
+ *   the source isn't.
+ * e.g. NewRandSource(rand.NewChaCha8(seed))
+ */
+func NewRandSource(source rand.Source) RandSource {
+	return rand.New(&lockedSource{source: source})
+}
+
+/*
+ * set replaces the source.
+ */
+func (locked *lockedSource) set(source rand.Source) {
+	locked.mu.Lock()
+	defer locked.mu.Unlock()
+
+	locked.source = source
+}
+
+/*
+ * WithSource makes a Roller take its random numbers from the given source, e.g. a seeded rand.PCG, or a cryptographic source.
+ *   The source is locked, so it doesn't need to be safe for concurrent use itself.
+ * e.g. NewRoller(WithSource(rand.NewChaCha8(seed)))
+ */
+func WithSource(source rand.Source) RollerOption {
+	return WithRandSource(NewRandSource(source))
+}
+
+/*
+ * WithRandSource makes a Roller take its random numbers from the given RandSource. It's used as is, so if the Roller is shared
+ *   between goroutines, the RandSource has to be safe for concurrent use: a *rand.Rand made with NewRandSource is.
+ * e.g. NewRoller(WithRandSource(recorded))
+ */
+func WithRandSource(source RandSource) RollerOption {
+	return func(roller *Roller) {
+		roller.random = source
+	}
+}
+
+// cryptoSource is a random source backed by crypto/rand, which is already safe for concurrent use.
+type cryptoSource struct{}
+
+/*
+ * Uint64 returns a random number from crypto/rand. The operating system's random source failing is unrecoverable, so it panics.
+ */
+func (cryptoSource) Uint64() uint64 {
+	var buffer [8]byte
+
+	if _, err := cryptorand.Read(buffer[:]); err != nil {
+		panic("diceroller: can't read from crypto/rand: " + err.Error())
+	}
+
+	return binary.LittleEndian.Uint64(buffer[:])
+}
+
+/*
+ * WithCryptoRand makes a Roller take its random numbers from crypto/rand, so its rolls can't be predicted from earlier ones, e.g.
+ *   for competitive or wagered games. It's much slower than the default, and can't be seeded.
+ * e.g. NewRoller(WithCryptoRand())
+ */
+func WithCryptoRand() RollerOption {
+	return func(roller *Roller) {
+		roller.random = rand.New(cryptoSource{})
+	}
+}
+
+/*
+ * WithSeed makes a Roller deterministic: two Rollers with the same seeds roll the same dice, in the same order.
+ * e.g. NewRoller(WithSeed(42, 1024))
+ */
+func WithSeed(seed1, seed2 uint64) RollerOption {
+	return WithSource(rand.NewPCG(seed1, seed2))
+}
+
+/*
+ * NewRoller returns a Roller configured by the given options. Without a source, it gets its own, seeded from the time.
+ * e.g. NewRoller(WithSeed(42, 1024))
+ */
+func NewRoller(options ...RollerOption) *Roller {
+	roller := &Roller{}
+
+	for _, option := range options {
+		option(roller)
+	}
+
+	if roller.random == nil {
+		now := uint64(time.Now().UnixNano())
+		roller.random = rand.New(&lockedSource{source: rand.NewPCG(now, now)})
+	}
+
+	return roller
+}
+
+/*
+ * source returns the Roller's random source, or the package's if it has none of its own, as the default Roller doesn't.
+ */
+func (roller *Roller) source() RandSource {
+	if roller.random == nil {
+		return random
+	}
+
+	return roller.random
+}
+
+/*
+ * RollOne is like the package's RollOne, but rolls with the Roller's random source.
+ * e.g. roller.RollOne("2d6") // 7
+ */
+func (roller *Roller) RollOne(input string) (int, error) {
+	roll, err := roller.roll(input)
+
+	return roll.Total, err
+}
+
+/*
+ * Roll is like the package's Roll, but rolls with the Roller's random source.
+ * e.g. roller.Roll("2d6", "2d8") // []int{7, 12}
+ */
+func (roller *Roller) Roll(input ...string) (output []int, err error) {
+	if err = roller.limits.checkTotal(input, roller.countDice); err != nil {
+		return
+	}
+
+	var dr DiceRoll
+
+	for _, in := range input {
+		dr, err = roller.roll(in)
+		if err != nil {
+			return
+		}
+
+		output = append(output, dr.Total)
+	}
+
+	return
+}
+
+/*
+ * RollTotal is like the package's RollTotal, but rolls with the Roller's random source.
+ * e.g. roller.RollTotal("2d6", "2d8") // 19
+ */
+func (roller *Roller) RollTotal(input ...string) (output int, err error) {
+	if err = roller.limits.checkTotal(input, roller.countDice); err != nil {
+		return
+	}
+
+	var dr DiceRoll
+
+	for _, in := range input {
+		dr, err = roller.roll(in)
+		if err != nil {
+			return
+		}
+
+		if output, err = checkedAdd(output, dr.Total); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+/*
+ * RollDetails is like the package's RollDetails, but rolls with the Roller's random source.
+ * e.g. roller.RollDetails("2d6") // [{2d6 6 2 0 [5 2] 7 ...}]
+ */
+func (roller *Roller) RollDetails(input ...string) (output []DiceRoll, err error) {
+	if err = roller.limits.checkTotal(input, roller.countDice); err != nil {
+		return
+	}
+
+	var dr DiceRoll
+
+	for _, in := range input {
+		dr, err = roller.roll(in)
+		if err != nil {
+			return
+		}
+
+		output = append(output, dr)
+	}
+
+	return
+}
+
+/*
+ * RollDie is like the package's RollDie, but rolls with the Roller's random source.
+ * e.g. roller.RollDie(die, 2, 0) // {2d6 6 2 0 [6 6] 12 ...}
+ */
+func (roller *Roller) RollDie(die DieSpec, rolls, modifier int) (output DiceRoll, err error) {
+	spec := newRollSpec(rolls, die, modifier)
+	if err = spec.validate(); err != nil {
+		return
+	}
+
+	if err = roller.limits.check(spec); err != nil {
+		return
+	}
+
+	spec.random = roller.random
+
+	if output, err = postProcess(spec.roll()); err == nil {
+		roller.history.addDie(output, die)
+	}
+
+	return
+}
+
+/*
+ * RollOneWith is like RollOne, but the options override the Roller's for this roll only, e.g. to replay one roll with a known seed.
+ * e.g. roller.RollOneWith("2d6", WithSeed(42, 1024)) // 9, every time
+ */
+func (roller *Roller) RollOneWith(input string, options ...RollerOption) (int, error) {
+	return roller.with(options).RollOne(input)
+}
+
+/*
+ * RollDetailsWith is like RollDetails for one roll, but the options override the Roller's for this roll only.
+ * e.g. roller.RollDetailsWith("2d6", WithSeed(42, 1024)) // {2d6 6 2 0 [6 3] 9 ...}, every time
+ */
+func (roller *Roller) RollDetailsWith(input string, options ...RollerOption) (DiceRoll, error) {
+	return roller.with(options).roll(input)
+}
+
+/*
+ * with returns a copy of the Roller with the options applied. Unlike NewRoller, it keeps the Roller's source if none is given.
+ */
+func (roller *Roller) with(options []RollerOption) *Roller {
+	output := *roller
+
+	for _, option := range options {
+		option(&output)
+	}
+
+	return &output
+}
+
+/*
+ * roll takes one string in the 'nDn+n' format and rolls it with the Roller's random source.
+ */
+func (roller *Roller) roll(input string) (output DiceRoll, err error) {
+	return roller.rollLabelled("", input)
+}
+
+/*
+ * rollLabelled is like roll, but labels the roll in the Roller's History.
+ */
+func (roller *Roller) rollLabelled(label, input string) (output DiceRoll, err error) {
+	if roller.notation != nil {
+		output, err = roller.notation.roll(roller, input)
+	} else {
+		var spec rollSpec
+		if spec, err = roller.parseRoll(input); err == nil {
+			output, err = roller.rollParsed(spec)
+		}
+	}
+
+	if err != nil {
+		return
+	}
+
+	if output, err = postProcess(output); err == nil {
+		roller.history.add(output, label)
+	}
+
+	return
+}
+
+/*
+ * countDice reads how many dice one roll rolls, in the Roller's notation, without rolling it.
+ */
+func (roller *Roller) countDice(input string) (int, error) {
+	if roller.notation != nil {
+		if roller.notation.dice == nil {
+			return 0, fmt.Errorf("%w: %q can't be counted before it's rolled", ErrNoRollFound, input)
+		}
+
+		return roller.notation.dice(roller, input)
+	}
+
+	spec, err := roller.parseRoll(input)
+
+	return spec.rolls, err
+}
+
+/*
+ * rollParsed checks a parsed roll is within the Roller's limits and rolls it with its random source, without post-processing it.
+ */
+func (roller *Roller) rollParsed(spec rollSpec) (DiceRoll, error) {
+	if err := roller.limits.check(spec); err != nil {
+		return DiceRoll{}, err
+	}
+
+	spec.random = roller.random
+
+	return spec.roll(), nil
+}
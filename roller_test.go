@@ -0,0 +1,280 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestRoller calls diceroller.Roller's methods, checking seeded Rollers are deterministic and don't touch the package's random source.
+func TestRoller(t *testing.T) {
+	reseed()
+
+	want, _ := RollDetails("4d6kh3", "2d20+1", "1d100")
+
+	// The package's random source was seeded the same way, so a Roller seeded like it rolls the same dice.
+	roller := NewRoller(WithSeed(42, 1024))
+	if output, err := roller.RollDetails("4d6kh3", "2d20+1", "1d100"); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", output, want, err)
+	}
+
+	reseed()
+
+	next := random.Uint64()
+
+	reseed()
+
+	a, b := NewRoller(WithSeed(1, 2)), NewRoller(WithSource(rand.NewPCG(1, 2)))
+	for _, input := range []string{"1d6", "3d6+2", "10d10kh3"} {
+		totalA, errA := a.RollOne(input)
+		totalB, errB := b.RollOne(input)
+
+		if totalA != totalB || errA != nil || errB != nil {
+			t.Errorf("have %d and %d, wanted the same for %q, errs %v %v", totalA, totalB, input, errA, errB)
+		}
+	}
+
+	if totals, err := a.Roll("2d6", "2d6"); len(totals) != 2 || err != nil {
+		t.Errorf("have %v, wanted two totals, err %v", totals, err)
+	}
+
+	if _, err := a.RollTotal("2d6", "1d8"); err != nil {
+		t.Errorf("have err %v", err)
+	}
+
+	die, _ := NewWeightedDie(0, 0, 0, 0, 0, 1)
+	if output, err := a.RollDie(die, 2, 1); output.Total != 13 || err != nil {
+		t.Errorf("have %v, wanted a total of 13, err %v", output, err)
+	}
+
+	if random.Uint64() != next {
+		t.Error("have the package's random source used, wanted the Rollers to use their own")
+	}
+
+	for _, err := range []error{first(a.Roll("nothing")), first(a.RollTotal("2d6", "nothing")), first(a.RollDetails("")), first(a.RollDie(DieSpec{}, 1, 0))} {
+		if err == nil {
+			t.Error("have no error, wanted one")
+		}
+	}
+
+	if unseeded := NewRoller(); unseeded.random == nil {
+		t.Error("have no random source, wanted one seeded from the time")
+	}
+}
+
+// TestWithCryptoRand calls diceroller.WithCryptoRand, checking the rolls are valid and don't use the package's random source.
+func TestWithCryptoRand(t *testing.T) {
+	reseed()
+
+	next := random.Uint64()
+
+	reseed()
+
+	roller, seen := NewRoller(WithCryptoRand()), map[int]bool{}
+	for range 1000 {
+		total, err := roller.RollOne("1d6")
+		if total < 1 || total > 6 || err != nil {
+			t.Fatalf("have %d, wanted 1 to 6, err %v", total, err)
+		}
+
+		seen[total] = true
+	}
+
+	if len(seen) != 6 {
+		t.Errorf("have %v, wanted every face in 1000 rolls", seen)
+	}
+
+	if random.Uint64() != next {
+		t.Error("have the package's random source used, wanted crypto/rand")
+	}
+}
+
+// TestConcurrentRolling rolls from many goroutines at once, with the package's functions and a shared Roller. Run it with -race
+// to check nothing races.
+func TestConcurrentRolling(t *testing.T) {
+	var (
+		roller = NewRoller(WithSeed(1, 2))
+		wg     sync.WaitGroup
+	)
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				if _, err := RollDetails("4d6kh3", "d20adv+5"); err != nil {
+					t.Errorf("have err %v", err)
+				}
+
+				if _, err := roller.RollOne("3d6"); err != nil {
+					t.Errorf("have err %v", err)
+				}
+
+				SetCriticalDice(20)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+/*
+ * first returns just the error from a two-value return.
+ */
+func first[T any](_ T, err error) error {
+	return err
+}
+
+// The system rollers, each rolled with both the package and a Roller.
+var rollerSystems = map[string]struct {
+	pkg    func() (any, error)
+	roller func(*Roller) (any, error)
+}{
+	"savage":    {func() (any, error) { return RollSavage(8, 1) }, func(r *Roller) (any, error) { return r.RollSavage(8, 1) }},
+	"coc":       {func() (any, error) { return RollCoC(50, 2, 0) }, func(r *Roller) (any, error) { return r.RollCoC(50, 2, 0) }},
+	"wod":       {func() (any, error) { return RollWoD(8, 8) }, func(r *Roller) (any, error) { return r.RollWoD(8, 8) }},
+	"shadowrun": {func() (any, error) { return RollShadowrun(8, true) }, func(r *Roller) (any, error) { return r.RollShadowrun(8, true) }},
+	"gurps":     {func() (any, error) { return RollGURPS(12) }, func(r *Roller) (any, error) { return r.RollGURPS(12) }},
+	"wfrp":      {func() (any, error) { return RollWFRP(45) }, func(r *Roller) (any, error) { return r.RollWFRP(45) }},
+	"ironsworn": {func() (any, error) { return RollIronsworn(2) }, func(r *Roller) (any, error) { return r.RollIronsworn(2) }},
+	"pbta":      {func() (any, error) { return RollPbtA(1) }, func(r *Roller) (any, error) { return r.RollPbtA(1) }},
+	"narrative": {
+		func() (any, error) {
+			return RollNarrative(NarrativePool{Ability: 2, Proficiency: 1, Difficulty: 2, Boost: 1})
+		},
+		func(r *Roller) (any, error) {
+			return r.RollNarrative(NarrativePool{Ability: 2, Proficiency: 1, Difficulty: 2, Boost: 1})
+		},
+	},
+}
+
+// TestRollerSystems rolls each system roller with seeded Rollers, checking they roll with the Roller's source, not the package's: the
+// same as the package does with the same seed, however much the package's source has been used.
+func TestRollerSystems(t *testing.T) {
+	for name, system := range rollerSystems {
+		reseed()
+
+		want, err := system.pkg()
+		if err != nil {
+			t.Fatalf("have err %v rolling %s", err, name)
+		}
+
+		for range 2 {
+			random.IntN(6)
+
+			if output, err := system.roller(NewRoller(WithSeed(42, 1024))); !reflect.DeepEqual(output, want) || err != nil {
+				t.Errorf("have %v, wanted %v for %s, err %v", output, want, name, err)
+			}
+		}
+	}
+}
+
+// BenchmarkRollerCrypto benchmarks diceroller.Roller.RollDetails with crypto/rand.
+func BenchmarkRollerCrypto(b *testing.B) {
+	roller := NewRoller(WithCryptoRand())
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollDetails("4d6kh3")
+	}
+}
+
+// BenchmarkRoller benchmarks diceroller.Roller.RollDetails.
+func BenchmarkRoller(b *testing.B) {
+	roller := NewRoller(WithSeed(42, 1024))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollDetails("4d6kh3")
+	}
+}
+
+// recordedSource is a RandSource which plays back recorded numbers, as a test of plugging in a source of your own.
+type recordedSource struct {
+	numbers []int
+}
+
+/*
+ * IntN returns the next recorded number.
+ */
+func (recorded *recordedSource) IntN(n int) int {
+	number := recorded.numbers[0]
+	recorded.numbers = recorded.numbers[1:]
+
+	return number % n
+}
+
+// TestWithRandSource calls diceroller.WithRandSource and diceroller.NewRandSource, checking the dice come from the given source.
+func TestWithRandSource(t *testing.T) {
+	roller := NewRoller(WithRandSource(&recordedSource{[]int{5, 0, 2, 19}}))
+
+	if output, err := roller.RollDetails("3d6+1", "1d20"); len(output) != 2 || !reflect.DeepEqual(output[0].Results, []int{6, 1, 3}) ||
+		output[0].Total != 11 || output[1].Total != 20 || err != nil {
+		t.Errorf("have %v, wanted [6 1 3] for 11, then 20, err %v", output, err)
+	}
+
+	a, b := NewRoller(WithRandSource(NewRandSource(rand.NewPCG(3, 4)))), NewRoller(WithSeed(3, 4))
+	totalA, _ := a.RollTotal("10d10", "4d6kh3")
+	totalB, _ := b.RollTotal("10d10", "4d6kh3")
+
+	if totalA != totalB {
+		t.Errorf("have %d and %d, wanted NewRandSource to roll the same as WithSeed", totalA, totalB)
+	}
+}
+
+// TestRollWith calls diceroller.RollOneWith and diceroller.RollDetailsWith, checking the options apply to that roll only.
+func TestRollWith(t *testing.T) {
+	reseed()
+
+	next := random.Uint64()
+
+	reseed()
+
+	for range 2 {
+		if output, err := RollDetailsWith("2d6", WithSeed(42, 1024)); !reflect.DeepEqual(output.Results, []int{6, 3}) || err != nil {
+			t.Errorf("have %v, wanted [6 3], err %v", output, err)
+		}
+
+		if total, err := RollOneWith("2d6", WithSeed(42, 1024)); total != 9 || err != nil {
+			t.Errorf("have %d, wanted 9, err %v", total, err)
+		}
+	}
+
+	if random.Uint64() != next {
+		t.Error("have the package's random source used, wanted only the seed given")
+	}
+
+	roller, same := NewRoller(WithSeed(5, 6)), NewRoller(WithSeed(5, 6))
+	_, _ = roller.RollOneWith("10d10", WithSeed(1, 1))
+
+	// The Roller's own source wasn't touched, so it rolls the same as one which never rolled.
+	totalA, _ := roller.RollTotal("10d10")
+	totalB, _ := same.RollTotal("10d10")
+
+	if totalA != totalB {
+		t.Errorf("have %d and %d, wanted the Roller's own source left alone", totalA, totalB)
+	}
+
+	if _, err := roller.RollDetailsWith("nothing", WithSeed(1, 1)); err == nil {
+		t.Error("have no error, wanted one")
+	}
+}
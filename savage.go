@@ -0,0 +1,109 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "fmt"
+
+// The Savage Worlds target number for a trait roll, and how far over it each raise is.
+const savageTarget = 4
+
+// SavageRoll holds the details of a Savage Worlds trait roll.
+type SavageRoll struct {
+	Trait           DiceRoll // The trait die. It aces (explodes), so Results holds every throw, and Total includes the modifier.
+	Wild            DiceRoll // The wild d6, which also aces.
+	Total           int      // The higher of the two totals.
+	UsedWild        bool     // Whether the wild die's total was the higher.
+	Success         bool     // Whether the total reached the target number of 4.
+	Raises          int      // How many times the total beat the target number by another 4, if successful.
+	CriticalFailure bool     // Whether both dice came up 1 before any acing ('snake eyes').
+}
+
+/*
+ * RollSavage makes a Savage Worlds trait roll: the trait die (a d4, d6, d8, d10 or d12) and a wild d6 are rolled, both acing
+ *   (rolling again and adding on their highest face), the modifier is added to both, and the higher total is kept. The total
+ *   succeeds on 4 or more, with a raise for every 4 over that.
+ * e.g. RollSavage(12, -2) // {{1d12-2 12 1 -2 [12 1] 11 ...} {1d6-2 6 1 -2 [3] 1 ...} 11 false true 1 false}
+ */
+func RollSavage(traitFaces, modifier int) (output SavageRoll, err error) {
+	return defaultRoller.RollSavage(traitFaces, modifier)
+}
+
+/*
+ * RollSavage is like the package's RollSavage, but rolls with the Roller's random source.
+ * e.g. roller.RollSavage(8, 0)
+ */
+func (roller *Roller) RollSavage(traitFaces, modifier int) (output SavageRoll, err error) {
+	switch traitFaces {
+	case 4, 6, 8, 10, 12:
+	default:
+		err = fmt.Errorf("a Savage Worlds trait die is a d4, d6, d8, d10 or d12, not a d%d", traitFaces)
+		return
+	}
+
+	if output.Trait, err = postProcess(acingRoll(traitFaces, modifier, roller.source())); err != nil {
+		return
+	}
+
+	if output.Wild, err = postProcess(acingRoll(6, modifier, roller.source())); err != nil {
+		return
+	}
+
+	output.resolve()
+
+	return
+}
+
+/*
+ * resolve works out the total, success, raises and critical failure from the trait and wild dice.
+ */
+func (output *SavageRoll) resolve() {
+	output.Total = output.Trait.Total
+	if output.Wild.Total > output.Total {
+		output.Total = output.Wild.Total
+		output.UsedWild = true
+	}
+
+	output.Success = output.Total >= savageTarget
+	if output.Success {
+		output.Raises = (output.Total - savageTarget) / savageTarget
+	}
+
+	output.CriticalFailure = output.Trait.Results[0] == 1 && output.Wild.Results[0] == 1
+}
+
+/*
+ * acingRoll rolls one die which aces on its highest face, adding the modifier, using the given random source, and returns the details.
+ */
+func acingRoll(faces, modifier int, source RandSource) (output DiceRoll) {
+	output = DiceRoll{
+		DiscoveredRoll: newRollSpec(1, DieSpec{Faces: faces}, modifier).discovered,
+		Faces:          faces,
+		Rolls:          1,
+		Modifier:       modifier,
+		Results:        DieSpec{Faces: faces}.explodeFrom(faces, source),
+	}
+
+	for _, result := range output.Results {
+		output.Total += result
+	}
+
+	output.Total += modifier
+
+	return
+}
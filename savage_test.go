@@ -0,0 +1,107 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rollSavageTest struct {
+	faces, modifier int
+	want            SavageRoll
+}
+
+var rollSavageTests = []rollSavageTest{
+	{8, 1, SavageRoll{
+		DiceRoll{DiscoveredRoll: "1d8+1", Faces: 8, Rolls: 1, Modifier: 1, Results: []int{2}, Total: 3, NaturalTotal: 2},
+		DiceRoll{DiscoveredRoll: "1d6+1", Faces: 6, Rolls: 1, Modifier: 1, Results: []int{3}, Total: 4, NaturalTotal: 3},
+		4, true, true, 0, false,
+	}},
+	{4, 0, SavageRoll{
+		DiceRoll{DiscoveredRoll: "1d4", Faces: 4, Rolls: 1, Modifier: 0, Results: []int{3}, Total: 3, NaturalTotal: 3},
+		DiceRoll{DiscoveredRoll: "1d6", Faces: 6, Rolls: 1, Modifier: 0, Results: []int{4}, Total: 4, NaturalTotal: 4},
+		4, true, true, 0, false,
+	}},
+	{12, -2, SavageRoll{
+		DiceRoll{DiscoveredRoll: "1d12-2", Faces: 12, Rolls: 1, Modifier: -2, Results: []int{12, 1}, Total: 11, NaturalTotal: 13},
+		DiceRoll{DiscoveredRoll: "1d6-2", Faces: 6, Rolls: 1, Modifier: -2, Results: []int{3}, Total: 1, NaturalTotal: 3},
+		11, false, true, 1, false,
+	}},
+}
+
+// TestRollSavage calls diceroller.RollSavage with many trait dice and modifiers, checking for valid return values.
+func TestRollSavage(t *testing.T) {
+	reseed()
+
+	for _, test := range rollSavageTests {
+		output, err := RollSavage(test.faces, test.modifier)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+// TestRollSavageErrors calls diceroller.RollSavage with trait dice Savage Worlds doesn't use, checking for errors.
+func TestRollSavageErrors(t *testing.T) {
+	for _, faces := range []int{0, 1, 3, 20} {
+		if _, err := RollSavage(faces, 0); err == nil {
+			t.Errorf("have no err for d%d, wanted one", faces)
+		}
+	}
+}
+
+type savageResolveTest struct {
+	trait, wild           []int
+	traitTotal, wildTotal int
+	want                  SavageRoll
+}
+
+var savageResolveTests = []savageResolveTest{
+	{[]int{3}, []int{2}, 3, 2, SavageRoll{Total: 3}},
+	{[]int{1}, []int{1}, 1, 1, SavageRoll{Total: 1, CriticalFailure: true}},
+	{[]int{1}, []int{1}, 6, 6, SavageRoll{Total: 6, Success: true, CriticalFailure: true}},
+	{[]int{6, 6, 2}, []int{5}, 14, 5, SavageRoll{Total: 14, Success: true, Raises: 2}},
+	{[]int{2}, []int{6, 2}, 2, 8, SavageRoll{Total: 8, UsedWild: true, Success: true, Raises: 1}},
+	{[]int{4}, []int{4}, 4, 4, SavageRoll{Total: 4, Success: true}},
+}
+
+// TestSavageRollResolve calls diceroller.SavageRoll.resolve with many trait and wild dice, checking for valid outcomes.
+func TestSavageRollResolve(t *testing.T) {
+	for _, test := range savageResolveTests {
+		output := SavageRoll{
+			Trait: DiceRoll{Results: test.trait, Total: test.traitTotal},
+			Wild:  DiceRoll{Results: test.wild, Total: test.wildTotal},
+		}
+		output.resolve()
+
+		test.want.Trait, test.want.Wild = output.Trait, output.Wild
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %+v, wanted %+v", output, test.want)
+		}
+	}
+}
+
+// BenchmarkRollSavage benchmarks diceroller.RollSavage.
+func BenchmarkRollSavage(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollSavage(8, 1)
+	}
+}
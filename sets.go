@@ -0,0 +1,99 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MatchSet is a group of dice which came up the same, as used by One Roll Engine games such as Wild Talents.
+type MatchSet struct {
+	Width  int // How many dice matched.
+	Height int // The face they all came up on.
+}
+
+/*
+ * String returns the set in the usual 'width x height' format.
+ * e.g. "3x7"
+ */
+func (set MatchSet) String() string {
+	return fmt.Sprintf("%dx%d", set.Width, set.Height)
+}
+
+/*
+ * RollSets accepts one or more strings in the correct 'nDn+n' format and returns structs with details of the roll, including the sets of
+ *   matching dice, widest first.
+ * e.g. RollSets("10d10") // [{10d10 10 10 0 [7 2 7 5 2 7 1 9 4 3] 47 [] [] 0 [3x7 2x2]}]
+ */
+func RollSets(input ...string) (output []DiceRoll, err error) {
+	output, err = RollDetails(input...)
+
+	for i := range output {
+		output[i].Sets = FindSets(output[i])
+	}
+
+	return
+}
+
+/*
+ * FindSets takes in a DiceRoll and returns the sets of two or more matching dice, widest first and then highest first. Dropped dice don't count.
+ * e.g. FindSets(DiceRoll{Results: []int{7, 2, 7, 5, 2, 7}}) // [{3 7} {2 2}]
+ */
+func FindSets(input DiceRoll) (output []MatchSet) {
+	counts := map[int]int{}
+
+	for i, v := range input.Results {
+		if !slices.Contains(input.Dropped, i) {
+			counts[v]++
+		}
+	}
+
+	for height, width := range counts {
+		if width > 1 {
+			output = append(output, MatchSet{Width: width, Height: height})
+		}
+	}
+
+	sort.Slice(output, func(a, b int) bool {
+		if output[a].Width != output[b].Width {
+			return output[a].Width > output[b].Width
+		}
+
+		return output[a].Height > output[b].Height
+	})
+
+	return
+}
+
+/*
+ * PrettifySets takes in a slice of MatchSet structs and returns a string with them displayed nicely.
+ * e.g. "3x7, 2x2"
+ */
+func PrettifySets(input []MatchSet) string {
+	sets := make([]string, len(input))
+
+	for i, set := range input {
+		sets[i] = set.String()
+	}
+
+	return strings.Join(sets, ", ")
+}
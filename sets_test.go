@@ -0,0 +1,92 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type findSetsTest struct {
+	got  DiceRoll
+	want []MatchSet
+}
+
+var findSetsTests = []findSetsTest{
+	{DiceRoll{Results: []int{7, 2, 7, 5, 2, 7, 1, 9, 4, 3}}, []MatchSet{{3, 7}, {2, 2}}},
+	{DiceRoll{Results: []int{1, 1, 9, 9, 5, 5, 5}}, []MatchSet{{3, 5}, {2, 9}, {2, 1}}},
+	{DiceRoll{Results: []int{1, 2, 3}}, nil},
+	{DiceRoll{Results: []int{4, 4, 6}, Dropped: []int{1}}, nil},
+}
+
+// TestFindSets calls diceroller.FindSets with many DiceRoll structs, checking for valid return values.
+func TestFindSets(t *testing.T) {
+	for _, test := range findSetsTests {
+		output := FindSets(test.got)
+
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %v, wanted %v", output, test.want)
+		}
+	}
+}
+
+// BenchmarkFindSets benchmarks diceroller.FindSets.
+func BenchmarkFindSets(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FindSets(findSetsTests[0].got)
+	}
+}
+
+var rollSetsTests = []rollDetailsTest{
+	{[]string{"10d10"}, []DiceRoll{
+		{DiscoveredRoll: "10d10", Faces: 10, Rolls: 10, Results: []int{10, 5, 10, 6, 10, 1, 4, 7, 3, 2}, Total: 58, NaturalTotal: 58, Sets: []MatchSet{{3, 10}}},
+	}},
+}
+
+// TestRollSets calls diceroller.RollSets with valid dice roll strings, checking for valid return values.
+func TestRollSets(t *testing.T) {
+	reseed()
+
+	for _, test := range rollSetsTests {
+		output, err := RollSets(test.got...)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+}
+
+type prettifySetsTest struct {
+	got  []MatchSet
+	want string
+}
+
+var prettifySetsTests = []prettifySetsTest{
+	{[]MatchSet{{3, 7}, {2, 2}}, "3x7, 2x2"},
+	{nil, ""},
+}
+
+// TestPrettifySets calls diceroller.PrettifySets with slices of MatchSet structs, checking for valid return values.
+func TestPrettifySets(t *testing.T) {
+	for _, test := range prettifySetsTests {
+		if output := PrettifySets(test.got); output != test.want {
+			t.Errorf("have %q, wanted %q", output, test.want)
+		}
+	}
+}
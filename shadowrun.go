@@ -0,0 +1,84 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "errors"
+
+// ShadowrunRoll holds the details of a Shadowrun dice pool roll.
+type ShadowrunRoll struct {
+	Roll           DiceRoll // The pool of d6s. With Edge, Results also holds the extra dice rolled for 6s.
+	Hits           int      // How many dice came up 5 or 6.
+	Ones           int      // How many dice came up 1.
+	Glitch         bool     // Whether half or more of the dice came up 1.
+	CriticalGlitch bool     // Whether it glitched with no hits.
+}
+
+/*
+ * RollShadowrun rolls a pool of d6s, counting 5s and 6s as hits, and spotting glitches (half or more of the dice coming up 1)
+ *   and critical glitches (a glitch with no hits). With Edge, the rule of six applies: every 6 is rolled again, adding to the pool.
+ * e.g. RollShadowrun(6, false) // {{6d6 6 6 0 [6 3 6 4 6 1] 26 ...} 3 1 false false}
+ */
+func RollShadowrun(pool int, edge bool) (output ShadowrunRoll, err error) {
+	return defaultRoller.RollShadowrun(pool, edge)
+}
+
+/*
+ * RollShadowrun is like the package's RollShadowrun, but rolls with the Roller's random source.
+ * e.g. roller.RollShadowrun(6, true)
+ */
+func (roller *Roller) RollShadowrun(pool int, edge bool) (output ShadowrunRoll, err error) {
+	if pool < 1 {
+		err = errors.New("a Shadowrun dice pool needs at least one die")
+		return
+	}
+
+	dr, err := roller.rollParsed(newRollSpec(pool, DieSpec{Faces: 6}, 0))
+	if err != nil {
+		return
+	}
+
+	if edge {
+		explodeResultsFrom(&dr, DieSpec{Faces: 6}, 6, roller.source())
+	}
+
+	if output.Roll, err = postProcess(dr); err != nil {
+		return
+	}
+
+	output.evaluate()
+
+	return
+}
+
+/*
+ * evaluate counts the hits and ones, and works out whether the roll glitched.
+ */
+func (output *ShadowrunRoll) evaluate() {
+	for _, result := range output.Roll.Results {
+		switch {
+		case result >= 5:
+			output.Hits++
+		case result == 1:
+			output.Ones++
+		}
+	}
+
+	output.Glitch = output.Ones*2 >= len(output.Roll.Results)
+	output.CriticalGlitch = output.Glitch && output.Hits == 0
+}
@@ -0,0 +1,88 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rollShadowrunTest struct {
+	pool int
+	edge bool
+	want ShadowrunRoll
+}
+
+var rollShadowrunTests = []rollShadowrunTest{
+	{6, false, ShadowrunRoll{DiceRoll{DiscoveredRoll: "6d6", Faces: 6, Rolls: 6, Results: []int{6, 3, 6, 4, 6, 1}, Total: 26, NaturalTotal: 26}, 3, 1, false, false}},
+	{8, true, ShadowrunRoll{DiceRoll{DiscoveredRoll: "8d6", Faces: 6, Rolls: 8, Results: []int{3, 4, 2, 2, 1, 6, 5, 3, 6, 2}, Total: 34, NaturalTotal: 34}, 3, 1, false, false}},
+}
+
+// TestRollShadowrun calls diceroller.RollShadowrun with many pools, with and without Edge, checking for valid return values.
+func TestRollShadowrun(t *testing.T) {
+	reseed()
+
+	for _, test := range rollShadowrunTests {
+		output, err := RollShadowrun(test.pool, test.edge)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	if _, err := RollShadowrun(0, false); err == nil {
+		t.Errorf("have nil error for an empty pool, wanted an error")
+	}
+}
+
+type shadowrunEvaluateTest struct {
+	results            []int
+	wantHits, wantOnes int
+	wantGlitch         bool
+	wantCriticalGlitch bool
+}
+
+var shadowrunEvaluateTests = []shadowrunEvaluateTest{
+	{[]int{5, 6, 2, 3}, 2, 0, false, false},
+	{[]int{1, 1, 5, 6}, 2, 2, true, false},
+	{[]int{1, 1, 1, 2}, 0, 3, true, true},
+	{[]int{1, 2, 3}, 0, 1, false, false},
+	{[]int{1}, 0, 1, true, true},
+	{[]int{6, 6, 5, 1, 1}, 3, 2, false, false},
+}
+
+// TestShadowrunRollEvaluate calls diceroller.ShadowrunRoll.evaluate with many pools, checking the hits, ones and glitches.
+func TestShadowrunRollEvaluate(t *testing.T) {
+	for _, test := range shadowrunEvaluateTests {
+		output := ShadowrunRoll{Roll: DiceRoll{Results: test.results}}
+		output.evaluate()
+
+		if output.Hits != test.wantHits || output.Ones != test.wantOnes || output.Glitch != test.wantGlitch ||
+			output.CriticalGlitch != test.wantCriticalGlitch {
+			t.Errorf("have %+v, wanted %d %d %v %v for %v", output, test.wantHits, test.wantOnes, test.wantGlitch, test.wantCriticalGlitch, test.results)
+		}
+	}
+}
+
+// BenchmarkRollShadowrun benchmarks diceroller.RollShadowrun.
+func BenchmarkRollShadowrun(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollShadowrun(12, true)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// What a signature's message starts with, so it can't be mistaken for any other HMAC made with the same key, and can change.
+const signaturePrefix = "diceroller-signed-v2"
+
+// SignedRoll is a roll with when it was made, signed with a key only the server has, so anyone given the roll later, e.g. a
+// tournament log, can check with the key that it hasn't been changed. The signature covers every field of the roll, as its JSON
+// encoding has them, and the time.
+type SignedRoll struct {
+	Roll      DiceRoll  `json:"roll"`      // The roll.
+	Time      time.Time `json:"time"`      // When the roll was made.
+	Signature string    `json:"signature"` // The HMAC-SHA256 of the roll and the time, in hex.
+}
+
+/*
+ * WithSigningKey gives a Roller a key to sign rolls with, for Roller.RollSigned. The key is copied, and should be kept secret, and
+ *   as long as a SHA-256 hash (32 bytes) or longer.
+ * e.g. NewRoller(WithSigningKey(key))
+ */
+func WithSigningKey(key []byte) RollerOption {
+	return func(roller *Roller) {
+		roller.signingKey = slices.Clone(key)
+	}
+}
+
+/*
+ * RollSigned is like RollDetails for one roll, but the roll is signed with the Roller's key, made now.
+ * e.g. roller.RollSigned("1d20+5") // {{1d20+5 20 1 5 [14] 19 ...} 2024-06-01 20:15:00 ... "3f1c..."}
+ */
+func (roller *Roller) RollSigned(input string) (SignedRoll, error) {
+	if len(roller.signingKey) == 0 {
+		return SignedRoll{}, errors.New("rolls can only be signed by a Roller made with WithSigningKey")
+	}
+
+	output, err := roller.roll(input)
+	if err != nil {
+		return SignedRoll{}, err
+	}
+
+	return SignRoll(roller.signingKey, output, time.Now()), nil
+}
+
+/*
+ * SignRoll signs a roll made at the given time with the key, e.g. for rolls made some other way than with RollSigned.
+ * e.g. SignRoll(key, roll, time.Now())
+ */
+func SignRoll(key []byte, roll DiceRoll, at time.Time) SignedRoll {
+	signed := SignedRoll{Roll: roll, Time: at}
+	signed.Signature = hex.EncodeToString(signed.mac(key))
+
+	return signed
+}
+
+/*
+ * VerifySignature checks a SignedRoll was signed with the key, and neither the roll nor the time have changed since. It returns
+ *   ErrBadSignature if they have.
+ * e.g. VerifySignature(key, signed) // nil
+ */
+func VerifySignature(key []byte, signed SignedRoll) error {
+	signature, err := hex.DecodeString(signed.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %q isn't hex", ErrBadSignature, signed.Signature)
+	}
+
+	if !hmac.Equal(signature, signed.mac(key)) {
+		return fmt.Errorf("%w for %s at %s", ErrBadSignature, FormatCanonical(signed.Roll), signed.Time.Format(time.RFC3339Nano))
+	}
+
+	return nil
+}
+
+/*
+ * mac returns the HMAC-SHA256 of the roll and the time with the key. The roll is in its JSON encoding, which has every field and
+ *   is stable, and the time is in UTC, so the same roll at the same moment signs the same wherever it's checked.
+ */
+func (signed SignedRoll) mac(key []byte) []byte {
+	roll, _ := json.Marshal(signed.Roll) // A DiceRoll is only numbers and strings, so always encodes.
+
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%s", signaturePrefix, signed.Time.UTC().Format(time.RFC3339Nano), roll)
+
+	return mac.Sum(nil)
+}
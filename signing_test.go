@@ -0,0 +1,108 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSignedRoll signs rolls, checking they verify with the key, even through JSON, and not once changed or with another key.
+func TestSignedRoll(t *testing.T) {
+	key := []byte("a key only the server knows, 32+")
+
+	roller := NewRoller(WithSeed(42, 1024), WithSigningKey(key))
+
+	signed, err := roller.RollSigned("4d6kh3+1")
+	if err != nil || signed.Roll.DiscoveredRoll != "4d6kh3+1" || signed.Time.IsZero() || len(signed.Signature) != 64 {
+		t.Fatalf("have %+v, err %v, wanted a signed 4d6kh3+1", signed, err)
+	}
+
+	encoded, _ := json.Marshal(signed)
+
+	var decoded SignedRoll
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if err := VerifySignature(key, decoded); err != nil {
+		t.Errorf("have err %v, wanted the roll to verify", err)
+	}
+
+	// The same moment in another time zone is the same moment.
+	decoded.Time = decoded.Time.In(time.FixedZone("somewhere", 5*60*60))
+	if err := VerifySignature(key, decoded); err != nil {
+		t.Errorf("have err %v, wanted the roll to verify in another time zone", err)
+	}
+
+	for name, tamper := range map[string]func(*SignedRoll){
+		"total":     func(s *SignedRoll) { s.Roll.Total++ },
+		"die":       func(s *SignedRoll) { s.Roll.Results[0] = 7 - s.Roll.Results[0] },
+		"dropped":   func(s *SignedRoll) { s.Roll.Dropped = nil },
+		"roll":      func(s *SignedRoll) { s.Roll.DiscoveredRoll = "4d6kh3+2" },
+		"faces":     func(s *SignedRoll) { s.Roll.Faces = 20 },
+		"rolls":     func(s *SignedRoll) { s.Roll.Rolls++ },
+		"modifier":  func(s *SignedRoll) { s.Roll.Modifier++ },
+		"natural":   func(s *SignedRoll) { s.Roll.NaturalTotal++ },
+		"per die":   func(s *SignedRoll) { s.Roll.DieModifier = 1 },
+		"unsorted":  func(s *SignedRoll) { s.Roll.Unsorted = []int{1, 2, 3, 4} },
+		"sets":      func(s *SignedRoll) { s.Roll.Sets = []MatchSet{{Width: 2, Height: 6}} },
+		"nat max":   func(s *SignedRoll) { s.Roll.NaturalMax = 1 },
+		"nat min":   func(s *SignedRoll) { s.Roll.NaturalMin = 1 },
+		"time":      func(s *SignedRoll) { s.Time = s.Time.Add(time.Nanosecond) },
+		"signature": func(s *SignedRoll) { s.Signature = "not hex" },
+	} {
+		var tampered SignedRoll
+		_ = json.Unmarshal(encoded, &tampered)
+		tamper(&tampered)
+
+		if err := VerifySignature(key, tampered); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("have err %v, wanted %v for a changed %s", err, ErrBadSignature, name)
+		}
+	}
+
+	if err := VerifySignature([]byte("another key"), signed); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("have err %v, wanted %v for another key", err, ErrBadSignature)
+	}
+
+	at := time.Date(2024, 6, 1, 20, 15, 0, 0, time.UTC)
+	if a, b := SignRoll(key, signed.Roll, at), SignRoll(key, signed.Roll, at); a.Signature != b.Signature || VerifySignature(key, a) != nil {
+		t.Errorf("have %q and %q, wanted the same signature, which verifies", a.Signature, b.Signature)
+	}
+
+	if _, err := NewRoller().RollSigned("1d20"); err == nil {
+		t.Error("have no error, wanted one for a Roller without a key")
+	}
+
+	if _, err := roller.RollSigned("nothing"); err == nil {
+		t.Error("have no error, wanted one for a bad roll")
+	}
+}
+
+// BenchmarkVerifySignature benchmarks diceroller.VerifySignature.
+func BenchmarkVerifySignature(b *testing.B) {
+	key := []byte("a key only the server knows, 32+")
+	signed, _ := NewRoller(WithSigningKey(key)).RollSigned("4d6kh3+1")
+
+	for i := 0; i < b.N; i++ {
+		_ = VerifySignature(key, signed)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+)
+
+// How many rolls each simulation worker makes at a time, each batch with its own random source, so the results don't depend on how
+// many workers there are.
+const simulationBatch = 10_000
+
+// Simulation holds the results of rolling a roll many times, as Simulate does.
+type Simulation struct {
+	Roll   string  // The roll, as discovered, e.g. '3d6'.
+	Rolls  int     // How many times it was rolled.
+	Min    int     // The lowest total rolled.
+	Max    int     // The highest total rolled.
+	Mean   float64 // The average total rolled.
+	StdDev float64 // The standard deviation of the totals rolled.
+	Counts []int   // How many times each total came up, starting with Min.
+}
+
+/*
+ * Simulate rolls one roll in the 'nDn+n' format many times, spread across every CPU, and returns how often each total came up. It's for
+ *   quick experiments, so the rolls don't go through any post-processors.
+ * e.g. Simulate("3d6", 1_000_000) // {3d6 1000000 3 18 10.5 2.96 [4610 13904 ...]}
+ */
+func Simulate(input string, rolls int) (output Simulation, err error) {
+	return SimulateContext(context.Background(), input, rolls)
+}
+
+/*
+ * SimulateContext is like Simulate, but stops early if the context is cancelled or its deadline passes, e.g. for a server which
+ *   doesn't want one request rolling forever, returning the context's error. It checks between batches of rolls.
+ * e.g. SimulateContext(ctx, "3d6", 1_000_000_000) // {}, context.DeadlineExceeded
+ */
+func SimulateContext(ctx context.Context, input string, rolls int) (output Simulation, err error) {
+	if rolls < 1 {
+		return Simulation{}, errors.New("a simulation needs at least one roll")
+	}
+
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return
+	}
+
+	// Seed each batch up front, in order, so the same package random source always gives the same results.
+	batches := make([]*rand.Rand, (rolls+simulationBatch-1)/simulationBatch)
+	for i := range batches {
+		batches[i] = rand.New(rand.NewPCG(random.Uint64(), random.Uint64()))
+	}
+
+	var (
+		next   = make(chan int)
+		counts = make([]map[int]int, len(batches))
+		wg     sync.WaitGroup
+	)
+
+	for range min(runtime.GOMAXPROCS(0), len(batches)) {
+		wg.Add(1)
+
+		go func(spec rollSpec) {
+			defer wg.Done()
+
+			for batch := range next {
+				if ctx.Err() != nil {
+					continue
+				}
+
+				spec.random = batches[batch]
+				counts[batch] = make(map[int]int)
+
+				for range min(simulationBatch, rolls-batch*simulationBatch) {
+					counts[batch][spec.roll().Total]++
+				}
+			}
+		}(spec)
+	}
+
+feed:
+	for batch := range batches {
+		select {
+		case next <- batch:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(next)
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return Simulation{}, err
+	}
+
+	return newSimulation(spec.discovered, rolls, counts), nil
+}
+
+/*
+ * newSimulation adds up each batch's counts of each total, and works out the statistics.
+ */
+func newSimulation(discovered string, rolls int, counts []map[int]int) (output Simulation) {
+	output = Simulation{Roll: discovered, Rolls: rolls, Min: math.MaxInt, Max: math.MinInt}
+
+	for _, batch := range counts {
+		for total := range batch {
+			output.Min = min(output.Min, total)
+			output.Max = max(output.Max, total)
+		}
+	}
+
+	output.Counts = make([]int, output.Max-output.Min+1)
+	for _, batch := range counts {
+		for total, count := range batch {
+			output.Counts[total-output.Min] += count
+			output.Mean += float64(total) * float64(count)
+		}
+	}
+
+	output.Mean /= float64(rolls)
+
+	var variance float64
+	for i, count := range output.Counts {
+		diff := float64(output.Min+i) - output.Mean
+		variance += diff * diff * float64(count)
+	}
+
+	output.StdDev = math.Sqrt(variance / float64(rolls))
+
+	return
+}
+
+/*
+ * Frequency returns how often a total came up, from 0 to 1.
+ */
+func (output Simulation) Frequency(total int) float64 {
+	if total < output.Min || total > output.Max || output.Rolls == 0 {
+		return 0
+	}
+
+	return float64(output.Counts[total-output.Min]) / float64(output.Rolls)
+}
@@ -0,0 +1,104 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type simulateTest struct {
+	got        string
+	rolls      int
+	wantMin    int
+	wantMax    int
+	wantMean   float64
+	wantCounts []int
+}
+
+var simulateTests = []simulateTest{
+	{"2d6", 25_000, 2, 12, 6.97928, []int{717, 1412, 2029, 2867, 3452, 4123, 3513, 2800, 2109, 1342, 636}},
+	{"4d6kh3+1", 5, 8, 14, 12.4, []int{1, 0, 0, 0, 1, 0, 3}},
+}
+
+// TestSimulate calls diceroller.Simulate with many rolls, checking for valid return values.
+func TestSimulate(t *testing.T) {
+	for _, test := range simulateTests {
+		reseed()
+
+		output, err := Simulate(test.got, test.rolls)
+		if output.Roll != test.got || output.Rolls != test.rolls || output.Min != test.wantMin || output.Max != test.wantMax ||
+			math.Abs(output.Mean-test.wantMean) > 1e-9 || !slices.Equal(output.Counts, test.wantCounts) || err != nil {
+			t.Errorf("have %v, wanted %s %d %d %d %v %v, err %v", output, test.got, test.rolls, test.wantMin, test.wantMax, test.wantMean,
+				test.wantCounts, err)
+		}
+	}
+
+	reseed()
+
+	output, _ := Simulate("2d6", 25_000)
+	if math.Abs(output.Frequency(7)-4123.0/25_000) > 1e-9 || output.Frequency(1) != 0 || output.Frequency(13) != 0 {
+		t.Errorf("have %v %v %v, wanted %v 0 0", output.Frequency(7), output.Frequency(1), output.Frequency(13), 4123.0/25_000)
+	}
+
+	if math.Abs(output.StdDev-2.4062690376597544) > 1e-9 {
+		t.Errorf("have %v, wanted 2.4062690376597544", output.StdDev)
+	}
+
+	for _, input := range []string{"", "2d6 please", "2d0"} {
+		if output, err := Simulate(input, 10); err == nil || !reflect.DeepEqual(output, Simulation{}) {
+			t.Errorf("have %v, nil error for %q, wanted an error", output, input)
+		}
+	}
+
+	if output, err := Simulate("2d6", 0); err == nil {
+		t.Errorf("have %v, nil error for no rolls, wanted an error", output)
+	}
+}
+
+// TestSimulateContext calls diceroller.SimulateContext, checking it simulates the same as Simulate, and stops when cancelled.
+func TestSimulateContext(t *testing.T) {
+	reseed()
+
+	want, _ := Simulate("3d6", 50_000)
+
+	reseed()
+
+	if output, err := SimulateContext(context.Background(), "3d6", 50_000); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, err %v, wanted %v", output, err, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := SimulateContext(ctx, "3d6", 1_000_000_000); !errors.Is(err, context.Canceled) {
+		t.Errorf("have err %v, wanted %v", err, context.Canceled)
+	}
+}
+
+// BenchmarkSimulate benchmarks diceroller.Simulate.
+func BenchmarkSimulate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Simulate("4d6kh3", simulationBatch*4)
+	}
+}
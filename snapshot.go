@@ -0,0 +1,153 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// SnapshotEntry is one recorded roll: what was rolled, the seed it was rolled with, and what came out. Rolling the same input with
+// the same seed should always give the same output, so a change means the library now rolls or reads it differently.
+type SnapshotEntry struct {
+	Input  string `json:"input"`
+	Seed   uint64 `json:"seed"`
+	Output string `json:"output,omitempty"` // The roll in FormatCanonical's format, e.g. '4d6kh3=[3,1x,6,4]=13'.
+	Error  string `json:"error,omitempty"`  // The error the input gave, if it's not a valid roll.
+}
+
+// SnapshotChange is a recorded roll which came out differently when it was replayed.
+type SnapshotChange struct {
+	Was SnapshotEntry // What was recorded.
+	Now SnapshotEntry // What it rolls now.
+}
+
+/*
+ * String describes what changed.
+ * e.g. "\"2d6+1\" (seed 42): was 2d6+1=[3,5]=9, now 2d6+1=[3,5]=8"
+ */
+func (change SnapshotChange) String() string {
+	return fmt.Sprintf("%q (seed %d): was %s, now %s", change.Was.Input, change.Was.Seed, change.Was.describe(), change.Now.describe())
+}
+
+/*
+ * describe returns the entry's output, or its error if it had one.
+ */
+func (entry SnapshotEntry) describe() string {
+	if entry.Error != "" {
+		return "error " + entry.Error
+	}
+
+	return entry.Output
+}
+
+/*
+ * RecordSnapshot rolls each input with its own seed, worked out from the given one, and returns what came out, to save with
+ *   SaveSnapshot and replay after upgrading. Inputs which aren't valid rolls are recorded with their error. The rolls don't use the
+ *   package's random source, and don't go through any post-processors, so only the library's own behaviour is recorded.
+ * e.g. RecordSnapshot(42, "2d6", "4d6kh3") // [{2d6 11423875981923235010 2d6=[1,3]=4 } {4d6kh3 6939021390147428351 4d6kh3=[3x,5,3,5]=13 }]
+ */
+func RecordSnapshot(seed uint64, inputs ...string) []SnapshotEntry {
+	var (
+		seeds  = rand.New(rand.NewPCG(seed, seed))
+		output = make([]SnapshotEntry, len(inputs))
+	)
+
+	for i, input := range inputs {
+		output[i] = rollSnapshot(input, seeds.Uint64())
+	}
+
+	return output
+}
+
+/*
+ * ReplaySnapshot rolls each recorded entry again with its seed, and returns any whose output or error has changed, in order.
+ * e.g. ReplaySnapshot(entries) // [] if nothing has changed
+ */
+func ReplaySnapshot(entries []SnapshotEntry) (output []SnapshotChange) {
+	for _, entry := range entries {
+		if now := rollSnapshot(entry.Input, entry.Seed); now != entry {
+			output = append(output, SnapshotChange{Was: entry, Now: now})
+		}
+	}
+
+	return
+}
+
+/*
+ * rollSnapshot rolls one input with a random source seeded only by the given seed.
+ */
+func rollSnapshot(input string, seed uint64) SnapshotEntry {
+	output := SnapshotEntry{Input: input, Seed: seed}
+
+	dr, err := rollSeeded(input, seed)
+	if err != nil {
+		output.Error = err.Error()
+		return output
+	}
+
+	output.Output = FormatCanonical(dr)
+
+	return output
+}
+
+/*
+ * rollSeeded rolls one input with a PCG source seeded with the seed twice, and nothing else: no post-processors, and not the
+ *   package's random source.
+ */
+func rollSeeded(input string, seed uint64) (DiceRoll, error) {
+	spec, err := parseRoll(input)
+	if err != nil {
+		return DiceRoll{}, err
+	}
+
+	spec.random = rand.New(rand.NewPCG(seed, seed))
+
+	return spec.roll(), nil
+}
+
+/*
+ * SaveSnapshot writes recorded entries as indented JSON.
+ */
+func SaveSnapshot(w io.Writer, entries []SnapshotEntry) error {
+	if entries == nil {
+		entries = []SnapshotEntry{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(entries)
+}
+
+/*
+ * LoadSnapshot reads entries written by SaveSnapshot. Unknown fields are an error, in case the file isn't a snapshot.
+ */
+func LoadSnapshot(r io.Reader) (output []SnapshotEntry, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(&output); err != nil {
+		return nil, fmt.Errorf("can't load snapshot: %w", err)
+	}
+
+	return output, nil
+}
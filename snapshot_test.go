@@ -0,0 +1,130 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// A corpus of rolls covering every part of the notation, and some which aren't valid, recorded in testdata/snapshot.json.
+var snapshotInputs = []string{
+	"1d6", "3D6+2", "1d20-1", "4d6kh3", "4d6kl1", "5d8km3+1", "d20adv+5", "2d20dis", "4d6s++1", "3d8sd--1-2", "1d100", "10d10",
+	"Roll 2d6+3 to hit", "2d6/2", "2d0", "nothing",
+}
+
+// TestSnapshot replays the recorded corpus, checking nothing rolls differently. Run 'go test -run TestSnapshot -update' to record it
+// again after an intended change.
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join("testdata", "snapshot.json")
+
+	if *updateGolden {
+		var recorded bytes.Buffer
+		if err := SaveSnapshot(&recorded, RecordSnapshot(42, snapshotInputs...)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, recorded.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("have err %v, wanted the snapshot (run with -update to create it)", err)
+	}
+	defer file.Close()
+
+	entries, err := LoadSnapshot(file)
+	if err != nil || len(entries) != len(snapshotInputs) {
+		t.Fatalf("have %d entries, wanted %d, err %v", len(entries), len(snapshotInputs), err)
+	}
+
+	for _, change := range ReplaySnapshot(entries) {
+		t.Error(change)
+	}
+}
+
+// TestReplaySnapshot calls diceroller.ReplaySnapshot with changed entries, checking each change is reported.
+func TestReplaySnapshot(t *testing.T) {
+	entries := RecordSnapshot(42, "2d6", "4d6kh3", "2d0")
+	if entries[0].Output != "2d6=[1,3]=4" || entries[2].Error == "" {
+		t.Fatalf("have %+v, wanted 2d6 recorded as 2d6=[1,3]=4 and 2d0 as an error", entries)
+	}
+
+	if changes := ReplaySnapshot(entries); len(changes) != 0 {
+		t.Errorf("have %v, wanted no changes", changes)
+	}
+
+	changed := []SnapshotEntry{entries[0], entries[1], entries[2]}
+	changed[0].Output = "2d6=[1,3]=5"
+	changed[2].Error = ""
+	changed[2].Output = "2d0=[]=0"
+
+	changes := ReplaySnapshot(changed)
+	if want := []SnapshotChange{{changed[0], entries[0]}, {changed[2], entries[2]}}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("have %v, wanted %v", changes, want)
+	}
+
+	if want := `"2d6" (seed 11423875981923235010): was 2d6=[1,3]=5, now 2d6=[1,3]=4`; changes[0].String() != want {
+		t.Errorf("have %q, wanted %q", changes[0].String(), want)
+	}
+
+	if !strings.HasSuffix(changes[1].String(), "now error a die needs at least one face") {
+		t.Errorf("have %q, wanted it to end with the error", changes[1].String())
+	}
+}
+
+// TestSaveLoadSnapshot calls diceroller.SaveSnapshot and diceroller.LoadSnapshot, checking entries survive the round trip.
+func TestSaveLoadSnapshot(t *testing.T) {
+	entries := RecordSnapshot(7, "2d6", "nothing")
+
+	var saved bytes.Buffer
+	if err := SaveSnapshot(&saved, entries); err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	if loaded, err := LoadSnapshot(&saved); !reflect.DeepEqual(loaded, entries) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", loaded, entries, err)
+	}
+
+	saved.Reset()
+	if err := SaveSnapshot(&saved, nil); err != nil || saved.String() != "[]\n" {
+		t.Errorf("have %q, wanted an empty array, err %v", saved.String(), err)
+	}
+
+	for _, input := range []string{`[{"input": "2d6", "bogus": 1}]`, `{"input": "2d6"}`, `nonsense`} {
+		if _, err := LoadSnapshot(strings.NewReader(input)); err == nil {
+			t.Errorf("have no error, wanted one loading %q", input)
+		}
+	}
+}
+
+// BenchmarkReplaySnapshot benchmarks diceroller.ReplaySnapshot.
+func BenchmarkReplaySnapshot(b *testing.B) {
+	entries := RecordSnapshot(42, snapshotInputs...)
+
+	for i := 0; i < b.N; i++ {
+		_ = ReplaySnapshot(entries)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RollSpec is a dice roll which has been parsed, but not rolled, e.g. so a UI can check and show a roll before it's rolled, or store
+// it to roll later. Advantage and disadvantage are kept as what they're short for, so 'd20adv' is two dice keeping the highest one.
+type RollSpec struct {
+	DiscoveredRoll string // The 'nDn+n'-format string we've discovered, and would roll.
+	Rolls          int    // How many dice to roll.
+	Faces          int    // How many faces each die has.
+	Modifier       int    // A '+n' or '-n' modifier to add to the total, or 0.
+	Keep           string // 'kh', 'kl' or 'km' to keep the highest, lowest or middle dice, or empty to keep them all.
+	KeepCount      int    // How many dice to keep, if Keep is set.
+	Sort           string // 's' or 'sd' to sort the results ascending or descending, or empty to leave them alone.
+	DieModifier    int    // A '++n' or '--n' modifier to add to every die which counts towards the total, or 0.
+}
+
+/*
+ * ParseDetails is like RollDetails, finding the first roll in each input, but breaks them down into RollSpecs instead of rolling
+ *   them. Rolls which couldn't be rolled are errors, too.
+ * e.g. ParseDetails("4d6kh3+2") // [{4d6kh3+2 4 6 2 kh 3  0}]
+ */
+func ParseDetails(input ...string) (output []RollSpec, err error) {
+	for _, in := range input {
+		spec, err := parseRoll(in)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, spec.export())
+	}
+
+	return output, nil
+}
+
+/*
+ * Roll rolls a RollSpec with the package's random source, the same as RollDetails would have rolled the roll it was parsed from.
+ *   A Roller has RollSpec to roll it with the Roller's.
+ * e.g. spec.Roll() // {4d6kh3+2 6 4 2 [6 3 6 4] 18 ...}
+ */
+func (spec RollSpec) Roll() (DiceRoll, error) {
+	return defaultRoller.RollSpec(spec)
+}
+
+/*
+ * RollSpec rolls a RollSpec with the Roller's random source, within its limits. It's checked the same way as a parsed roll, and
+ *   Keep and Sort have to be ones the notation has.
+ * e.g. roller.RollSpec(spec) // {4d6kh3+2 6 4 2 [6 3 6 4] 18 ...}
+ */
+func (roller *Roller) RollSpec(spec RollSpec) (output DiceRoll, err error) {
+	if !slices.Contains([]string{"", "kh", "kl", "km"}, spec.Keep) {
+		return output, fmt.Errorf("%q can't keep %q dice: it has to be 'kh', 'kl', 'km', or empty", spec.DiscoveredRoll, spec.Keep)
+	}
+
+	if !slices.Contains([]string{"", "s", "sd"}, spec.Sort) {
+		return output, fmt.Errorf("%q can't sort %q: it has to be 's', 'sd', or empty", spec.DiscoveredRoll, spec.Sort)
+	}
+
+	unexported := spec.unexport()
+
+	if err = unexported.validate(); err != nil {
+		return
+	}
+
+	if err = roller.limits.check(unexported); err != nil {
+		return
+	}
+
+	unexported.random = roller.random
+
+	if output, err = postProcess(unexported.roll()); err == nil {
+		roller.history.add(output, "")
+	}
+
+	return
+}
+
+/*
+ * export turns a rollSpec into a RollSpec.
+ */
+func (spec rollSpec) export() RollSpec {
+	return RollSpec{
+		DiscoveredRoll: spec.discovered,
+		Rolls:          spec.rolls,
+		Faces:          spec.die.Faces,
+		Modifier:       spec.modifier,
+		Keep:           spec.keep,
+		KeepCount:      spec.keepCount,
+		Sort:           spec.sort,
+		DieModifier:    spec.dieModifier,
+	}
+}
+
+/*
+ * unexport turns a RollSpec back into a rollSpec.
+ */
+func (spec RollSpec) unexport() rollSpec {
+	return rollSpec{
+		discovered:  spec.DiscoveredRoll,
+		rolls:       spec.Rolls,
+		die:         DieSpec{Faces: spec.Faces},
+		modifier:    spec.Modifier,
+		keep:        spec.Keep,
+		keepCount:   spec.KeepCount,
+		sort:        spec.Sort,
+		dieModifier: spec.DieModifier,
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type parseDetailsTest struct {
+	input   string
+	want    RollSpec
+	wantErr bool
+}
+
+var parseDetailsTests = []parseDetailsTest{
+	{"2d6", RollSpec{"2d6", 2, 6, 0, "", 0, "", 0}, false},
+	{"4d6kh3+2", RollSpec{"4d6kh3+2", 4, 6, 2, "kh", 3, "", 0}, false},
+	{"d20adv", RollSpec{"d20adv", 2, 20, 0, "kh", 1, "", 0}, false},
+	{"roll 3d8sd++1-1 for damage", RollSpec{"3d8sd++1-1", 3, 8, -1, "", 0, "sd", 1}, false},
+	{"nothing", RollSpec{}, true},
+	{"2d0", RollSpec{}, true},
+}
+
+// TestParseDetails calls diceroller.ParseDetails, checking for valid return values, and that nothing is rolled.
+func TestParseDetails(t *testing.T) {
+	reseed()
+
+	next := random.Uint64()
+
+	reseed()
+
+	for _, test := range parseDetailsTests {
+		output, err := ParseDetails(test.input)
+		if (err != nil) != test.wantErr || (err == nil && !reflect.DeepEqual(output, []RollSpec{test.want})) {
+			t.Errorf("have %v, err %v, wanted %v, error %t for %q", output, err, test.want, test.wantErr, test.input)
+		}
+	}
+
+	if random.Uint64() != next {
+		t.Error("have the package's random source used, wanted nothing rolled")
+	}
+}
+
+// TestRollSpecRoll calls diceroller.RollSpec.Roll, checking it rolls the same as RollDetails, and bad RollSpecs are errors.
+func TestRollSpecRoll(t *testing.T) {
+	reseed()
+
+	want, _ := RollDetails("4d6kh3+2", "3d8sd++1-1")
+
+	reseed()
+
+	specs, _ := ParseDetails("4d6kh3+2", "3d8sd++1-1")
+	for i, spec := range specs {
+		if output, err := spec.Roll(); !reflect.DeepEqual(output, want[i]) || err != nil {
+			t.Errorf("have %v, err %v, wanted %v", output, err, want[i])
+		}
+	}
+
+	for _, spec := range []RollSpec{{Rolls: 0, Faces: 6}, {Rolls: 1, Faces: 0}, {Rolls: 2, Faces: 6, Keep: "kx"}, {Rolls: 2, Faces: 6, Sort: "x"}} {
+		if _, err := spec.Roll(); err == nil {
+			t.Errorf("have no error, wanted one for %v", spec)
+		}
+	}
+
+	if _, err := NewRoller(WithMaxDice(3)).RollSpec(specs[0]); err == nil {
+		t.Errorf("have no error, wanted one for %v over the limit", specs[0])
+	}
+}
+
+// BenchmarkParseDetails benchmarks diceroller.ParseDetails.
+func BenchmarkParseDetails(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseDetails("4d6kh3+2")
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+ * Value stores a DiceRoll in a database as its JSON (see MarshalJSON), e.g. in a Postgres jsonb or a SQLite text column, so it
+ *   can be passed straight to database/sql.
+ * e.g. db.Exec("INSERT INTO rolls (roll) VALUES ($1)", roll)
+ */
+func (roll DiceRoll) Value() (driver.Value, error) {
+	return json.Marshal(roll)
+}
+
+/*
+ * Scan reads a DiceRoll stored by Value back from a database, from JSON as bytes or a string. A NULL gives an empty DiceRoll.
+ * e.g. db.QueryRow("SELECT roll FROM rolls WHERE id = $1", id).Scan(&roll)
+ */
+func (roll *DiceRoll) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*roll = DiceRoll{}
+
+		return nil
+	case []byte:
+		return json.Unmarshal(src, roll)
+	case string:
+		return json.Unmarshal([]byte(src), roll)
+	default:
+		return fmt.Errorf("can't scan a dice roll from %T, only JSON as []byte or string", src)
+	}
+}
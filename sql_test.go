@@ -0,0 +1,69 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDiceRollSQL stores rolls with Value, checking they scan back to the same rolls from bytes and strings, that a NULL gives an
+// empty roll, and that anything else is an error.
+func TestDiceRollSQL(t *testing.T) {
+	reseed()
+
+	rolls, _ := RollDetails("4d6kh3", "2d20kl1s++1-2", "d20adv", "8d6")
+
+	for _, roll := range rolls {
+		value, err := roll.Value()
+		if err != nil {
+			t.Fatalf("have err %v", err)
+		}
+
+		encoded, _ := value.([]byte)
+
+		for _, src := range []any{encoded, string(encoded)} {
+			var scanned DiceRoll
+			if err := scanned.Scan(src); err != nil || !reflect.DeepEqual(scanned, roll) {
+				t.Errorf("have %#v, err %v, wanted %#v from %T", scanned, err, roll, src)
+			}
+		}
+	}
+
+	scanned := rolls[0]
+	if err := scanned.Scan(nil); err != nil || !reflect.DeepEqual(scanned, DiceRoll{}) {
+		t.Errorf("have %#v, err %v, wanted an empty roll", scanned, err)
+	}
+
+	for _, src := range []any{42, 1.5, true, []byte(`{"schema":2}`), "nonsense"} {
+		if err := scanned.Scan(src); err == nil {
+			t.Errorf("have nil error for %#v, wanted an error", src)
+		}
+	}
+}
+
+// BenchmarkDiceRollSQL benchmarks storing and scanning a diceroller.DiceRoll.
+func BenchmarkDiceRollSQL(b *testing.B) {
+	rolls, _ := RollDetails("4d6kh3+1")
+
+	for i := 0; i < b.N; i++ {
+		value, _ := rolls[0].Value()
+		_ = rolls[0].Scan(value)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RerollAdvice is the expected-value-optimal choice of which dice in a roll to reroll, alongside the roll itself.
+type RerollAdvice struct {
+	Roll     DiceRoll // The roll the advice is about.
+	Reroll   []int    // Indexes into Roll.Results of the dice worth rerolling. Every other die should be kept.
+	Expected float64  // The expected total, modifier included, if the advice is followed.
+	Gain     float64  // How much following the advice improves the expected total over keeping every die.
+}
+
+/*
+ * AdviseRerolls takes in a DiceRoll and the number of dice which may be rerolled, and works out which dice to reroll to get the best expected total.
+ * Rolls which keep some of their dice, e.g. '4d6kh3', count only what would be kept after the reroll, so a die is only worth
+ *   rerolling if it's expected to make the kept total higher, and fewer than maxRerolls dice may be suggested. It's an error if
+ *   which dice a roll keeps can't be told from its DiscoveredRoll.
+ * e.g. AdviseRerolls(DiceRoll{Faces: 6, Rolls: 3, Results: []int{1, 5, 3}, Total: 9}, 2) // {... [0 2] 12 3}, nil
+ */
+func AdviseRerolls(roll DiceRoll, maxRerolls int) (RerollAdvice, error) {
+	return AdviseRerollsDie(roll, DieSpec{Faces: roll.Faces}, maxRerolls)
+}
+
+/*
+ * AdviseRerollsDie is like AdviseRerolls, but for a roll of the given die, e.g. a weighted one rolled with RollDie.
+ * e.g. AdviseRerollsDie(roll, DieSpec{Faces: 3, Weights: []int{1, 1, 4}}, 1)
+ */
+func AdviseRerollsDie(roll DiceRoll, die DieSpec, maxRerolls int) (output RerollAdvice, err error) {
+	output.Roll = roll
+	output.Expected = float64(roll.Total)
+
+	if err = die.validate(); err != nil {
+		return
+	}
+
+	low, high, err := keptRanks(roll)
+	if err != nil {
+		return
+	}
+
+	// Look at the dice lowest first: a kept total never goes down when a die goes up, so rerolling the lowest dice is always best.
+	order := make([]int, len(roll.Results))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		return roll.Results[order[a]] < roll.Results[order[b]]
+	})
+
+	expected, err := expectedKept(roll.Results, order, dieDistribution(die), low, high, min(max(maxRerolls, 0), len(order)))
+	if err != nil {
+		return
+	}
+
+	// Reroll however many of the lowest dice gets the best expected total, preferring fewer rerolls when it's the same.
+	best := 0
+	for rerolls := range expected {
+		if expected[rerolls] > expected[best]+1e-9 {
+			best = rerolls
+		}
+	}
+
+	if best > 0 {
+		output.Reroll = append(output.Reroll, order[:best]...)
+		sort.Ints(output.Reroll)
+	}
+
+	output.Gain = expected[best] - expected[0]
+	output.Expected += output.Gain
+
+	return
+}
+
+/*
+ * keptRanks returns which dice of a roll count towards its total, as ranks among the dice lowest first, from low up to but not
+ *   including high, e.g. 1 to 4 for '4d6kh3'. It works out which from the roll's keep, e.g. 'kh3', checking it drops as many dice
+ *   as the roll did.
+ */
+func keptRanks(roll DiceRoll) (low, high int, err error) {
+	var (
+		dice       = len(roll.Results)
+		spec, perr = parseRoll(roll.DiscoveredRoll)
+	)
+
+	if perr != nil || !spec.drops() {
+		if len(roll.Dropped) > 0 {
+			return 0, 0, fmt.Errorf("can't tell which dice %q keeps, to advise rerolling them", roll.DiscoveredRoll)
+		}
+
+		return 0, dice, nil
+	}
+
+	if len(dropIndexes(roll.Results, spec.keep, spec.keepCount)) != len(roll.Dropped) || spec.rolls != dice {
+		return 0, 0, fmt.Errorf("%q doesn't keep the dice its roll did, to advise rerolling them", roll.DiscoveredRoll)
+	}
+
+	switch drop := dice - spec.keepCount; spec.keep {
+	case "kh":
+		return drop, dice, nil
+	case "kl":
+		return 0, spec.keepCount, nil
+	default:
+		// Keeping the middle dice drops any extra die from the bottom, as dropIndexes does.
+		low = drop - drop/2
+		return low, low + spec.keepCount, nil
+	}
+}
+
+/*
+ * expectedKept returns the expected total of the kept dice, ranks low to high, if the lowest 0, 1, ... maxRerolls of the results
+ *   (in order, lowest first) are rerolled with a die of the given distribution. The kept total is the sum, over each face t, of how
+ *   many kept dice are t or more, and how many dice are t or more is the dice kept as they are plus a binomial of the rerolled ones.
+ */
+func expectedKept(results, order []int, die distribution, low, high, maxRerolls int) ([]float64, error) {
+	var (
+		faces    = len(die.probs)
+		dropped  = len(results) - high // How many dice rank above the kept ones.
+		expected = make([]float64, maxRerolls+1)
+		atLeast  = 1.0 // The chance a rerolled die comes up t or more.
+	)
+
+	// Keeping every die, each rerolled die is worth the die's average instead of what it came up.
+	if low == 0 && dropped == 0 {
+		mean := die.mean()
+
+		for _, v := range results {
+			expected[0] += float64(v)
+		}
+
+		for rerolls := 1; rerolls <= maxRerolls; rerolls++ {
+			expected[rerolls] = expected[rerolls-1] + mean - float64(results[order[rerolls-1]])
+		}
+
+		return expected, nil
+	}
+
+	if work := float64(faces) * float64(maxRerolls+1) * float64(maxRerolls+1); work > maxDistributionWork {
+		return nil, fmt.Errorf("%w: rerolling %d of %d dice", ErrTooComplex, maxRerolls, len(results))
+	}
+
+	for t := 1; t <= faces; t++ {
+		// How many of the dice are t or more, before any are rerolled.
+		var count int
+		for _, v := range results {
+			if v >= t {
+				count++
+			}
+		}
+
+		// The chances of each number of rerolled dice coming up t or more, built up one rerolled die at a time.
+		chances := []float64{1}
+
+		for rerolls := 0; rerolls <= maxRerolls; rerolls++ {
+			if rerolls > 0 {
+				if results[order[rerolls-1]] >= t {
+					count--
+				}
+
+				next := make([]float64, rerolls+1)
+				for j, chance := range chances {
+					next[j] += chance * (1 - atLeast)
+					next[j+1] += chance * atLeast
+				}
+
+				chances = next
+			}
+
+			for j, chance := range chances {
+				expected[rerolls] += chance * float64(min(max(count+j-dropped, 0), high-low))
+			}
+		}
+
+		atLeast -= die.probs[t-1]
+	}
+
+	return expected, nil
+}
@@ -0,0 +1,148 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"math/bits"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type adviseRerollsTest struct {
+	got        DiceRoll
+	maxRerolls int
+	want       RerollAdvice
+}
+
+var adviseRerollsTests = []adviseRerollsTest{
+	{DiceRoll{DiscoveredRoll: "3d6", Faces: 6, Rolls: 3, Modifier: 0, Results: []int{1, 5, 3}, Total: 9}, 2, RerollAdvice{DiceRoll{DiscoveredRoll: "3d6", Faces: 6, Rolls: 3, Modifier: 0, Results: []int{1, 5, 3}, Total: 9}, []int{0, 2}, 12, 3}},
+	{DiceRoll{DiscoveredRoll: "3d6", Faces: 6, Rolls: 3, Modifier: 0, Results: []int{1, 5, 3}, Total: 9}, 1, RerollAdvice{DiceRoll{DiscoveredRoll: "3d6", Faces: 6, Rolls: 3, Modifier: 0, Results: []int{1, 5, 3}, Total: 9}, []int{0}, 11.5, 2.5}},
+	{DiceRoll{DiscoveredRoll: "2d6+2", Faces: 6, Rolls: 2, Modifier: 2, Results: []int{4, 6}, Total: 12}, 2, RerollAdvice{DiceRoll{DiscoveredRoll: "2d6+2", Faces: 6, Rolls: 2, Modifier: 2, Results: []int{4, 6}, Total: 12}, nil, 12, 0}},
+	{DiceRoll{DiscoveredRoll: "3d5", Faces: 5, Rolls: 3, Modifier: 0, Results: []int{3, 2, 3}, Total: 8}, 3, RerollAdvice{DiceRoll{DiscoveredRoll: "3d5", Faces: 5, Rolls: 3, Modifier: 0, Results: []int{3, 2, 3}, Total: 8}, []int{1}, 9, 1}},
+	{DiceRoll{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{6, 3, 6, 4}, Total: 16, NaturalTotal: 16, Dropped: []int{1}}, 2, RerollAdvice{DiceRoll{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Modifier: 0, Results: []int{6, 3, 6, 4}, Total: 16, NaturalTotal: 16, Dropped: []int{1}}, []int{1}, 16.5, 0.5}},
+	{DiceRoll{DiscoveredRoll: "2d20kh1", Faces: 20, Rolls: 2, Modifier: 0, Results: []int{19, 3}, Total: 19, NaturalTotal: 19, Dropped: []int{1}}, 1, RerollAdvice{DiceRoll{DiscoveredRoll: "2d20kh1", Faces: 20, Rolls: 2, Modifier: 0, Results: []int{19, 3}, Total: 19, NaturalTotal: 19, Dropped: []int{1}}, []int{1}, 19.05, 0.05}},
+	{DiceRoll{DiscoveredRoll: "2d20kh1+2", Faces: 20, Rolls: 2, Modifier: 2, Results: []int{4, 3}, Total: 6, NaturalTotal: 4, Dropped: []int{1}}, 2, RerollAdvice{DiceRoll{DiscoveredRoll: "2d20kh1+2", Faces: 20, Rolls: 2, Modifier: 2, Results: []int{4, 3}, Total: 6, NaturalTotal: 4, Dropped: []int{1}}, []int{0, 1}, 15.825, 9.825}},
+	{DiceRoll{DiscoveredRoll: "2d20kl1", Faces: 20, Rolls: 2, Modifier: 0, Results: []int{4, 15}, Total: 4, NaturalTotal: 4, Dropped: []int{1}}, 2, RerollAdvice{DiceRoll{DiscoveredRoll: "2d20kl1", Faces: 20, Rolls: 2, Modifier: 0, Results: []int{4, 15}, Total: 4, NaturalTotal: 4, Dropped: []int{1}}, []int{0}, 9.75, 5.75}},
+}
+
+// TestAdviseRerolls calls diceroller.AdviseRerolls with DiceRoll structs, checking for valid return values.
+func TestAdviseRerolls(t *testing.T) {
+	for _, test := range adviseRerollsTests {
+		output, err := AdviseRerolls(test.got, test.maxRerolls)
+
+		// Expected values with dropped dice are sums of fractions, so only have to be close.
+		close := math.Abs(output.Expected-test.want.Expected) < 1e-9 && math.Abs(output.Gain-test.want.Gain) < 1e-9
+		output.Expected, output.Gain = test.want.Expected, test.want.Gain
+
+		if !reflect.DeepEqual(output, test.want) || !close || err != nil {
+			t.Errorf("have %v, err %v, wanted %v", output, err, test.want)
+		}
+	}
+}
+
+// TestAdviseRerollsOptimal checks diceroller.AdviseRerolls against every possible reroll of 4d6kh3 and 2d20kh1 rolls, by trying
+// every outcome of every choice of dice.
+func TestAdviseRerollsOptimal(t *testing.T) {
+	for _, roll := range []DiceRoll{
+		{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Results: []int{1, 2, 6, 2}, Total: 10, Dropped: []int{0}},
+		{DiscoveredRoll: "4d6kh3", Faces: 6, Rolls: 4, Results: []int{5, 3, 4, 5}, Total: 14, Dropped: []int{1}},
+		{DiscoveredRoll: "3d6km1", Faces: 6, Rolls: 3, Results: []int{1, 2, 6}, Total: 2, Dropped: []int{0, 2}},
+		{DiscoveredRoll: "2d20kh1", Faces: 20, Rolls: 2, Results: []int{11, 9}, Total: 11, Dropped: []int{1}},
+	} {
+		spec, _ := parseRoll(roll.DiscoveredRoll)
+
+		for maxRerolls := 1; maxRerolls <= len(roll.Results); maxRerolls++ {
+			best := 0.0
+
+			for set := 0; set < 1<<len(roll.Results); set++ {
+				if bits.OnesCount(uint(set)) <= maxRerolls {
+					best = max(best, bruteForceExpected(roll.Results, set, spec))
+				}
+			}
+
+			advice, err := AdviseRerolls(roll, maxRerolls)
+			if math.Abs(advice.Expected-best) > 1e-9 || len(advice.Reroll) > maxRerolls || err != nil {
+				t.Errorf("have %v, err %v, wanted an expected %f rerolling up to %d of %v", advice, err, best, maxRerolls, roll.Results)
+			}
+		}
+	}
+}
+
+/*
+ * bruteForceExpected returns the expected kept total of rerolling the dice in a set of bits, by trying every outcome.
+ */
+func bruteForceExpected(results []int, set int, spec rollSpec) (output float64) {
+	var (
+		rerolled []int
+		outcomes = 1
+	)
+
+	for i := range results {
+		if set&(1<<i) != 0 {
+			rerolled = append(rerolled, i)
+			outcomes *= spec.die.Faces
+		}
+	}
+
+	for outcome := range outcomes {
+		values := slices.Clone(results)
+		for _, i := range rerolled {
+			values[i] = outcome%spec.die.Faces + 1
+			outcome /= spec.die.Faces
+		}
+
+		total := 0
+		for _, v := range keptResults(DiceRoll{Results: values, Dropped: dropIndexes(values, spec.keep, spec.keepCount)}) {
+			total += v
+		}
+
+		output += float64(total) / float64(outcomes)
+	}
+
+	return
+}
+
+// TestAdviseRerollsDie calls diceroller.AdviseRerollsDie with a weighted die, and diceroller.AdviseRerolls with rolls it can't
+// advise on, checking for valid return values and errors.
+func TestAdviseRerollsDie(t *testing.T) {
+	// The weighted d3 averages 2.5, so the 2 is worth rerolling too.
+	roll := DiceRoll{DiscoveredRoll: "2d3", Faces: 3, Rolls: 2, Results: []int{1, 2}, Total: 3}
+	if advice, err := AdviseRerollsDie(roll, DieSpec{Faces: 3, Weights: []int{1, 1, 4}}, 2); !reflect.DeepEqual(advice.Reroll, []int{0, 1}) || advice.Expected != 5 || err != nil {
+		t.Errorf("have %v, err %v, wanted [0 1] for an expected 5", advice, err)
+	}
+
+	for name, roll := range map[string]DiceRoll{
+		"other notation": {DiscoveredRoll: "4d6k3", Faces: 6, Rolls: 4, Results: []int{1, 2, 6, 2}, Total: 10, Dropped: []int{0}},
+		"different drop": {DiscoveredRoll: "4d6kh2", Faces: 6, Rolls: 4, Results: []int{1, 2, 6, 2}, Total: 10, Dropped: []int{0}},
+		"no faces":       {DiscoveredRoll: "{1d6,1d8}kh1", Results: []int{3, 5}, Total: 5, Dropped: []int{0}},
+	} {
+		if advice, err := AdviseRerolls(roll, 2); err == nil || advice.Reroll != nil {
+			t.Errorf("have %v, nil error for %s, wanted an error", advice, name)
+		}
+	}
+}
+
+// BenchmarkAdviseRerolls benchmarks diceroller.AdviseRerolls.
+func BenchmarkAdviseRerolls(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = AdviseRerolls(adviseRerollsTests[0].got, adviseRerollsTests[0].maxRerolls)
+	}
+}
@@ -0,0 +1,328 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// How finely a t-digest keeps the distribution: more is more accurate, and keeps more centroids. A few hundred centroids at most.
+const tDigestCompression = 100
+
+// SimulationStats holds running statistics of rolling a roll many times, as SimulateStream works out, without keeping every total.
+type SimulationStats struct {
+	Roll   string  // The roll, as discovered, e.g. '3d6'.
+	Rolls  int     // How many times it was rolled.
+	Min    int     // The lowest total rolled.
+	Max    int     // The highest total rolled.
+	Mean   float64 // The average total rolled.
+	StdDev float64 // The standard deviation of the totals rolled.
+	digest *tDigest
+}
+
+/*
+ * SimulateStream rolls one roll in the 'nDn+n' format many times, like Simulate, but keeps running statistics rather than counting
+ *   every total, so it runs in the same memory however many rolls, or different totals, there are. The mean and standard deviation
+ *   are worked out as it goes (Welford's method), and the percentiles are estimated with a t-digest. As with Simulate, the same
+ *   package random source always gives the same results, and the rolls don't go through any post-processors.
+ * e.g. SimulateStream("3d6", 1_000_000_000) // {3d6 1000000000 3 18 10.5 2.96 ...}
+ */
+func SimulateStream(input string, rolls int) (output SimulationStats, err error) {
+	if rolls < 1 {
+		return SimulationStats{}, errors.New("a simulation needs at least one roll")
+	}
+
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return
+	}
+
+	var (
+		batches = (rolls + simulationBatch - 1) / simulationBatch
+		workers = min(runtime.GOMAXPROCS(0), batches)
+		seed1   = random.Uint64()
+		seed2   = random.Uint64()
+		next    = make(chan int)
+		done    = make(chan batchStats)
+		tokens  = make(chan struct{}, 2*workers) // Limits how many batches can be waiting to be merged.
+		wg      sync.WaitGroup
+	)
+
+	for range workers {
+		wg.Add(1)
+
+		go func(spec rollSpec) {
+			defer wg.Done()
+
+			for batch := range next {
+				// Each batch is seeded from its number, so there's nothing to keep per batch up front.
+				spec.random = rand.New(rand.NewPCG(seed1, seed2+uint64(batch)))
+				counts := make(map[int]int)
+
+				for range min(simulationBatch, rolls-batch*simulationBatch) {
+					counts[spec.roll().Total]++
+				}
+
+				done <- batchStats{batch, newRunningStats(counts)}
+			}
+		}(spec)
+	}
+
+	go func() {
+		for batch := range batches {
+			tokens <- struct{}{}
+			next <- batch
+		}
+
+		close(next)
+		wg.Wait()
+		close(done)
+	}()
+
+	// Merge the batches in order, whichever order they finish in, so the floating point sums always come out the same.
+	var (
+		total   = newRunningStats(nil)
+		pending = make(map[int]*runningStats)
+		merged  = 0
+	)
+
+	for finished := range done {
+		pending[finished.batch] = finished.stats
+
+		for stats, ok := pending[merged]; ok; stats, ok = pending[merged] {
+			total.merge(stats)
+			delete(pending, merged)
+			merged++
+			<-tokens
+		}
+	}
+
+	return SimulationStats{
+		Roll:   spec.discovered,
+		Rolls:  rolls,
+		Min:    total.min,
+		Max:    total.max,
+		Mean:   total.mean,
+		StdDev: math.Sqrt(total.m2 / float64(total.count)),
+		digest: total.digest,
+	}, nil
+}
+
+/*
+ * Percentile returns an estimate of the total which the given percentage of rolls came in at or under, from 0 to 100.
+ * e.g. stats.Percentile(50) // 10.5, the median
+ */
+func (stats SimulationStats) Percentile(percent float64) float64 {
+	if stats.digest == nil {
+		return 0
+	}
+
+	return stats.digest.quantile(min(max(percent, 0), 100)/100, float64(stats.Min), float64(stats.Max))
+}
+
+// batchStats is one batch's statistics, and which batch it was.
+type batchStats struct {
+	batch int
+	stats *runningStats
+}
+
+// runningStats is Welford's running mean and variance, with the lowest and highest values and a t-digest of them all.
+type runningStats struct {
+	count    int
+	mean, m2 float64 // The mean, and the sum of the squared differences from it.
+	min, max int
+	digest   *tDigest
+}
+
+/*
+ * newRunningStats returns running statistics of the given counts of each value.
+ */
+func newRunningStats(counts map[int]int) *runningStats {
+	stats := &runningStats{min: math.MaxInt, max: math.MinInt, digest: &tDigest{}}
+
+	// Add the values in order, so the same counts always give the same sums.
+	values := make([]int, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+
+	slices.Sort(values)
+
+	for _, value := range values {
+		stats.merge(&runningStats{count: counts[value], mean: float64(value), min: value, max: value})
+		stats.digest.add(float64(value), float64(counts[value]))
+	}
+
+	return stats
+}
+
+/*
+ * merge adds another set of running statistics to these ones, using Chan et al.'s parallel version of Welford's method.
+ */
+func (stats *runningStats) merge(other *runningStats) {
+	if other.count == 0 {
+		return
+	}
+
+	count := stats.count + other.count
+	delta := other.mean - stats.mean
+
+	stats.mean += delta * float64(other.count) / float64(count)
+	stats.m2 += other.m2 + delta*delta*float64(stats.count)*float64(other.count)/float64(count)
+	stats.count = count
+	stats.min = min(stats.min, other.min)
+	stats.max = max(stats.max, other.max)
+
+	if other.digest != nil {
+		stats.digest.merge(other.digest)
+	}
+}
+
+// centroid is a cluster of values in a t-digest: their mean, and how many there are.
+type centroid struct {
+	mean, weight float64
+}
+
+// tDigest estimates percentiles by keeping clusters of values, small ones at the ends of the distribution and bigger ones in the
+// middle, as in Dunning's merging t-digest. New values are buffered, and merged in when the buffer fills up.
+type tDigest struct {
+	centroids []centroid
+	buffer    []centroid
+	weight    float64
+}
+
+/*
+ * add adds a value to the digest, as many times as the weight.
+ */
+func (digest *tDigest) add(value, weight float64) {
+	digest.buffer = append(digest.buffer, centroid{value, weight})
+	digest.weight += weight
+
+	if len(digest.buffer) >= 5*tDigestCompression {
+		digest.compress()
+	}
+}
+
+/*
+ * merge adds every value in another digest to this one.
+ */
+func (digest *tDigest) merge(other *tDigest) {
+	other.compress()
+
+	for _, c := range other.centroids {
+		digest.add(c.mean, c.weight)
+	}
+}
+
+/*
+ * compress merges the buffer into the centroids, keeping each centroid within the size the scale function allows for where it is.
+ */
+func (digest *tDigest) compress() {
+	if len(digest.buffer) == 0 {
+		return
+	}
+
+	all := append(digest.centroids, digest.buffer...)
+	slices.SortStableFunc(all, func(a, b centroid) int {
+		switch {
+		case a.mean < b.mean:
+			return -1
+		case a.mean > b.mean:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	var (
+		merged = []centroid{all[0]}
+		before = 0.0 // The weight of the centroids before the last one.
+		kLeft  = tDigestScale(0)
+	)
+
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+
+		if tDigestScale((before+last.weight+c.weight)/digest.weight)-kLeft <= 1 {
+			last.mean += (c.mean - last.mean) * c.weight / (last.weight + c.weight)
+			last.weight += c.weight
+
+			continue
+		}
+
+		before += last.weight
+		kLeft = tDigestScale(before / digest.weight)
+		merged = append(merged, c)
+	}
+
+	digest.centroids = merged
+	digest.buffer = digest.buffer[:0]
+}
+
+/*
+ * tDigestScale is the t-digest's k1 scale function, which keeps centroids small near 0 and 1, where percentiles need more detail.
+ */
+func tDigestScale(q float64) float64 {
+	return tDigestCompression / (2 * math.Pi) * math.Asin(2*min(max(q, 0), 1)-1)
+}
+
+/*
+ * quantile estimates the value at the given quantile, from 0 to 1, interpolating between the centroids' middles, and out to the
+ *   lowest and highest values at the ends.
+ */
+func (digest *tDigest) quantile(q, lowest, highest float64) float64 {
+	digest.compress()
+
+	if len(digest.centroids) == 0 {
+		return 0
+	}
+
+	var (
+		target = q * digest.weight
+		before = 0.0    // The weight before the current centroid.
+		left   = lowest // The value at the previous point, and...
+		leftAt = 0.0    // ...the weight at it.
+	)
+
+	for _, c := range digest.centroids {
+		middle := before + c.weight/2
+
+		if target <= middle {
+			if middle == leftAt {
+				return c.mean
+			}
+
+			return left + (c.mean-left)*(target-leftAt)/(middle-leftAt)
+		}
+
+		left, leftAt = c.mean, middle
+		before += c.weight
+	}
+
+	if digest.weight == leftAt {
+		return highest
+	}
+
+	return left + (highest-left)*(target-leftAt)/(digest.weight-leftAt)
+}
@@ -0,0 +1,130 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+type simulateStreamTest struct {
+	got            string
+	rolls          int
+	wantMin        int
+	wantMax        int
+	wantMean       float64
+	wantStdDev     float64
+	wantPercentile map[float64]float64
+}
+
+var simulateStreamTests = []simulateStreamTest{
+	{"2d6", 25_000, 2, 12, 6.98088, 2.419990583783334, map[float64]float64{0: 2, 25: 5, 50: 7, 75: 9, 100: 12}},
+	{"1d1+1", 3, 2, 2, 2, 0, map[float64]float64{0: 2, 50: 2, 100: 2}},
+}
+
+// TestSimulateStream calls diceroller.SimulateStream with many rolls, checking for valid return values, and that the same seed
+// always gives the same results.
+func TestSimulateStream(t *testing.T) {
+	for _, test := range simulateStreamTests {
+		reseed()
+
+		output, err := SimulateStream(test.got, test.rolls)
+		if output.Roll != test.got || output.Rolls != test.rolls || output.Min != test.wantMin || output.Max != test.wantMax ||
+			math.Abs(output.Mean-test.wantMean) > 1e-9 || math.Abs(output.StdDev-test.wantStdDev) > 1e-9 || err != nil {
+			t.Errorf("have %+v, wanted %s %d %d %d %v %v, err %v", output, test.got, test.rolls, test.wantMin, test.wantMax,
+				test.wantMean, test.wantStdDev, err)
+		}
+
+		for percent, want := range test.wantPercentile {
+			if have := output.Percentile(percent); math.Abs(have-want) > 1e-9 {
+				t.Errorf("have %v, wanted %v for the %vth percentile of %s", have, want, percent, test.got)
+			}
+		}
+
+		reseed()
+
+		again, _ := SimulateStream(test.got, test.rolls)
+		if again.Mean != output.Mean || again.StdDev != output.StdDev || again.Percentile(37) != output.Percentile(37) {
+			t.Errorf("have %+v, wanted the same as %+v", again, output)
+		}
+	}
+
+	for _, input := range []string{"", "2d6 please", "2d0"} {
+		if output, err := SimulateStream(input, 10); err == nil || output.Rolls != 0 {
+			t.Errorf("have %v, nil error for %q, wanted an error", output, input)
+		}
+	}
+
+	if output, err := SimulateStream("2d6", 0); err == nil {
+		t.Errorf("have %v, nil error for no rolls, wanted an error", output)
+	}
+
+	if (SimulationStats{}).Percentile(50) != 0 {
+		t.Error("have a percentile, wanted 0 with no rolls")
+	}
+}
+
+// TestRunningStats checks merging running statistics gives the same mean and variance as working them out directly.
+func TestRunningStats(t *testing.T) {
+	var (
+		values = []int{4, 8, 15, 16, 23, 42, 4, 4}
+		stats  = newRunningStats(nil)
+		sum    float64
+	)
+
+	for _, value := range values {
+		stats.merge(newRunningStats(map[int]int{value: 1}))
+		sum += float64(value)
+	}
+
+	mean, squares := sum/float64(len(values)), 0.0
+	for _, value := range values {
+		squares += (float64(value) - mean) * (float64(value) - mean)
+	}
+
+	if stats.count != len(values) || math.Abs(stats.mean-mean) > 1e-9 || math.Abs(stats.m2-squares) > 1e-9 || stats.min != 4 ||
+		stats.max != 42 {
+		t.Errorf("have %+v, wanted count %d, mean %v, m2 %v, 4 to 42", stats, len(values), mean, squares)
+	}
+}
+
+// TestTDigest checks a t-digest of many different values estimates their percentiles closely, with far fewer centroids.
+func TestTDigest(t *testing.T) {
+	digest := &tDigest{}
+	for value := 1; value <= 100_000; value++ {
+		digest.add(float64(value), 1)
+	}
+
+	for _, q := range []float64{0.001, 0.01, 0.25, 0.5, 0.75, 0.99, 0.999} {
+		if have, want := digest.quantile(q, 1, 100_000), q*100_000; math.Abs(have-want) > 100_000*0.002 {
+			t.Errorf("have %v, wanted about %v at %v", have, want, q)
+		}
+	}
+
+	if len(digest.centroids) > 2*tDigestCompression {
+		t.Errorf("have %d centroids, wanted at most %d", len(digest.centroids), 2*tDigestCompression)
+	}
+}
+
+// BenchmarkSimulateStream benchmarks diceroller.SimulateStream.
+func BenchmarkSimulateStream(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = SimulateStream("4d6kh3", simulationBatch*4)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Suggestion is a roll which an input that isn't one was probably meant to be, e.g. '2d6+2' for '2d6/2', and why, so a bot can ask
+// the player "did you mean 2d6+2?" rather than roll something they didn't ask for.
+type Suggestion struct {
+	Input   string   // The input, as given.
+	Roll    string   // The roll it was probably meant to be, e.g. '2d6+2'.
+	Reasons []string // What looked wrong, in the order it was fixed, e.g. "\"/\" isn't a modifier, so it's probably '+'".
+}
+
+// typoFix is one typo Suggest looks for, and how to fix it.
+type typoFix struct {
+	pattern *regexp.Regexp
+	replace string
+	reason  string // Why it's fixed, with the first typo in place of any %q.
+}
+
+// The typos Suggest fixes, in the order it fixes them.
+var typoFixes = []typoFix{
+	{regexp.MustCompile(`(\d)o`), "${1}0", "an 'o' in a number is probably a '0'"},
+	{regexp.MustCompile(`^(\d*)(?:dd+|[a-ce-z])(\d)`), "${1}d${2}", "the letter between the numbers is probably a 'd'"},
+	{regexp.MustCompile(`^d(\d{1,5})([+\-=/\\*#,.;:&_]|$)`), "1d${1}${2}", "a roll needs a number of dice, so it's probably one"},
+	{regexp.MustCompile(`[=/\\*#,.;:&]`), "+", "%q isn't a modifier, so it's probably '+'"},
+	{regexp.MustCompile(`_`), "-", "'_' isn't a modifier, so it's probably '-'"},
+	{regexp.MustCompile(`\+-|-\+`), "-", "a modifier with two signs is probably '-'"},
+	{regexp.MustCompile(`[+-]+$`), "", "there's nothing after the last '+' or '-'"},
+}
+
+/*
+ * Suggest returns the roll an input which isn't quite one was probably meant to be, e.g. '2d6+2' for '2d6/2', '2d6' for '2b6', or
+ *   '1d20' for 'd 20 +', and why, so a bot can ask the player rather than roll something else. It returns false if the input is
+ *   already a roll, or there's no roll it's close to.
+ * e.g. Suggest("2d6/2") // {2d6/2 2d6+2 ["\"/\" isn't a modifier, so it's probably '+'"]}, true
+ */
+func Suggest(input string) (suggestion Suggestion, ok bool) {
+	if _, err := parseWholeRoll(input); err == nil {
+		return Suggestion{}, false
+	}
+
+	roll := strings.ToLower(inputReplacer.Replace(input))
+	suggestion.Input = input
+
+	for _, fix := range typoFixes {
+		fixed := fix.pattern.ReplaceAllString(roll, fix.replace)
+		if fixed == roll {
+			continue
+		}
+
+		reason := fix.reason
+		if strings.Contains(reason, "%q") {
+			reason = fmt.Sprintf(reason, fix.pattern.FindString(roll))
+		}
+
+		roll = fixed
+		suggestion.Reasons = append(suggestion.Reasons, reason)
+	}
+
+	if len(suggestion.Reasons) == 0 {
+		return Suggestion{}, false
+	}
+
+	if _, err := parseWholeRoll(roll); err != nil {
+		return Suggestion{}, false
+	}
+
+	suggestion.Roll = roll
+
+	return suggestion, true
+}
+
+/*
+ * String asks about the suggestion as a bot would.
+ * e.g. "did you mean 2d6+2?"
+ */
+func (suggestion Suggestion) String() string {
+	return fmt.Sprintf("did you mean %s?", suggestion.Roll)
+}
@@ -0,0 +1,78 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var suggestTests = map[string]string{
+	"2d6/2":    "2d6+2",
+	"2b6":      "2d6",
+	"3x8+1":    "3d8+1",
+	"2dd6":     "2d6",
+	"d 20 +":   "1d20",
+	"d20":      "1d20",
+	"2D6=3":    "2d6+3",
+	"1d2o":     "1d20",
+	"2d6+-1":   "2d6-1",
+	"2d6_1":    "2d6-1",
+	"2d6++":    "2d6",
+	"4d6kh3*2": "4d6kh3+2",
+	"d20adv/5": "d20adv+5",
+}
+
+// TestSuggest calls diceroller.Suggest with near-misses, checking for valid return values, and that rolls and things which aren't
+// near a roll get no suggestion.
+func TestSuggest(t *testing.T) {
+	for input, want := range suggestTests {
+		suggestion, ok := Suggest(input)
+		if suggestion.Roll != want || suggestion.Input != input || len(suggestion.Reasons) == 0 || !ok {
+			t.Errorf("have %+v, %t, wanted %q from %q", suggestion, ok, want, input)
+		}
+	}
+
+	for _, input := range []string{"2d6", "2d6 + 3", "d20adv", "2d6 to hit", "nothing", "", "2d0/2"} {
+		if suggestion, ok := Suggest(input); ok {
+			t.Errorf("have %+v, wanted no suggestion for %q", suggestion, input)
+		}
+	}
+
+	suggestion, _ := Suggest("2d6/2")
+	if want := `"/" isn't a modifier, so it's probably '+'`; suggestion.Reasons[0] != want || suggestion.String() != "did you mean 2d6+2?" {
+		t.Errorf("have %q and %q, wanted %q", suggestion.Reasons, suggestion, want)
+	}
+
+	// RollRecover's errors say what Suggest suggests.
+	for input, want := range map[string]error{"2d6/2": ErrNotJustRoll, "2b6": ErrNoRollFound} {
+		suggestion, _ := Suggest(input)
+		if _, _, err := RollRecover(StrictnessStrict, input); !errors.Is(err, want) || !strings.HasSuffix(err.Error(), suggestion.String()) {
+			t.Errorf("have err %v for %q, wanted %v ending %q", err, input, want, suggestion)
+		}
+	}
+}
+
+// BenchmarkSuggest benchmarks diceroller.Suggest.
+func BenchmarkSuggest(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Suggest("d 20 /5")
+	}
+}
@@ -0,0 +1,263 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// How deeply tables can be nested inside each other's entries.
+const maxTableDepth = 8
+
+// ErrNoTableEntry is returned when a table is rolled and no entry covers the total.
+var ErrNoTableEntry = errors.New("no table entry for that total")
+
+// Table is a random table, such as treasure or random encounters, rolled with any roll, e.g. '1d100' or '2d6'. It has JSON tags.
+type Table struct {
+	Name    string       `json:"name"`
+	Dice    string       `json:"roll"` // What to roll on the table, e.g. '1d100'.
+	Entries []TableEntry `json:"entries"`
+}
+
+// TableEntry is one line of a Table: the totals it covers, from Min to Max, what it says, and optionally a table to roll on next.
+type TableEntry struct {
+	Min    int    `json:"min"`
+	Max    int    `json:"max"`
+	Result string `json:"result"`
+	Table  *Table `json:"table,omitempty"`
+}
+
+// TableResult holds the outcome of rolling on a table, and on any table nested inside it.
+type TableResult struct {
+	Table  string       // The name of the table rolled on.
+	Roll   DiceRoll     // The roll made on the table.
+	Result string       // The entry's result.
+	Next   *TableResult // The outcome of rolling on the entry's nested table, if it had one.
+}
+
+/*
+ * LoadTableJSON reads a Table from JSON, rejecting unknown fields so typos don't go unnoticed, and checks it's valid.
+ * e.g. LoadTableJSON(strings.NewReader(`{"name": "Loot", "roll": "1d6", "entries": [{"min": 1, "max": 6, "result": "a rat"}]}`))
+ */
+func LoadTableJSON(r io.Reader) (output Table, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(&output); err != nil {
+		return Table{}, fmt.Errorf("reading table: %w", err)
+	}
+
+	if err = output.Validate(); err != nil {
+		return Table{}, err
+	}
+
+	return
+}
+
+/*
+ * LoadTableCSV reads a Table's entries from CSV, one per line, as a range and a result, e.g. '01-50,nothing' and '51-00,a gem'. The
+ *   range can be one number, '00' is 100, and a first line whose range isn't a range (a header) is skipped. CSV can't nest tables.
+ */
+func LoadTableCSV(r io.Reader, name, dice string) (output Table, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Table{}, fmt.Errorf("reading table: %w", err)
+	}
+
+	output = Table{Name: name, Dice: dice}
+
+	for line, record := range records {
+		if len(record) < 2 {
+			return Table{}, fmt.Errorf("reading table: line %d needs a range and a result", line+1)
+		}
+
+		entry := TableEntry{Result: strings.TrimSpace(strings.Join(record[1:], ","))}
+		if entry.Min, entry.Max, err = parseTableRange(record[0]); err != nil {
+			if line == 0 {
+				continue
+			}
+
+			return Table{}, fmt.Errorf("reading table: line %d: %w", line+1, err)
+		}
+
+		output.Entries = append(output.Entries, entry)
+	}
+
+	if err = output.Validate(); err != nil {
+		return Table{}, err
+	}
+
+	return
+}
+
+/*
+ * parseTableRange returns the lowest and highest totals of a range such as '01-50', '51–00' or '17', where '00' is 100.
+ */
+func parseTableRange(input string) (low, high int, err error) {
+	input = strings.ReplaceAll(strings.TrimSpace(input), "–", "-")
+
+	lowStr, highStr, found := strings.Cut(input, "-")
+	if !found {
+		highStr = lowStr
+	}
+
+	for _, part := range []struct {
+		str   string
+		value *int
+	}{{lowStr, &low}, {highStr, &high}} {
+		if *part.value, err = strconv.Atoi(strings.TrimSpace(part.str)); err != nil || part.str == "" {
+			return 0, 0, fmt.Errorf("%q isn't a range, e.g. '01-50'", input)
+		}
+
+		if strings.TrimSpace(part.str) == "00" {
+			*part.value = 100
+		}
+	}
+
+	return
+}
+
+/*
+ * Validate checks the table's roll can be rolled, its entries don't overlap, and any nested tables are valid too.
+ */
+func (table Table) Validate() error {
+	return table.validate(1)
+}
+
+/*
+ * validate checks the table, knowing how deeply it's nested.
+ */
+func (table Table) validate(depth int) error {
+	if depth > maxTableDepth {
+		return fmt.Errorf("table %q: tables are nested more than %d deep", table.Name, maxTableDepth)
+	}
+
+	if _, err := parseWholeRoll(table.Dice); err != nil {
+		return fmt.Errorf("table %q: %w", table.Name, err)
+	}
+
+	if len(table.Entries) == 0 {
+		return fmt.Errorf("table %q has no entries", table.Name)
+	}
+
+	for i, entry := range table.Entries {
+		if entry.Min > entry.Max {
+			return fmt.Errorf("table %q: entry %q runs from %d down to %d", table.Name, entry.Result, entry.Min, entry.Max)
+		}
+
+		for _, other := range table.Entries[:i] {
+			if entry.Min <= other.Max && other.Min <= entry.Max {
+				return fmt.Errorf("table %q: entries %q and %q overlap", table.Name, other.Result, entry.Result)
+			}
+		}
+
+		if entry.Table != nil {
+			if err := entry.Table.validate(depth + 1); err != nil {
+				return fmt.Errorf("table %q: %w", table.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+ * Lookup returns the entry which covers a total, or ErrNoTableEntry.
+ */
+func (table Table) Lookup(total int) (TableEntry, error) {
+	for _, entry := range table.Entries {
+		if total >= entry.Min && total <= entry.Max {
+			return entry, nil
+		}
+	}
+
+	return TableEntry{}, fmt.Errorf("table %q, %d: %w", table.Name, total, ErrNoTableEntry)
+}
+
+/*
+ * Roll rolls on the table, and on any table nested in the entry rolled, and returns the outcome.
+ * e.g. treasure.Roll() // {Treasure {1d100 ...} a gem {Gems {1d6 ...} a ruby <nil>}}
+ */
+func (table Table) Roll() (output TableResult, err error) {
+	if err = table.Validate(); err != nil {
+		return
+	}
+
+	return table.roll()
+}
+
+/*
+ * roll rolls on a table which has already been validated.
+ */
+func (table Table) roll() (output TableResult, err error) {
+	output.Table = table.Name
+
+	if output.Roll, err = roll(table.Dice); err != nil {
+		return TableResult{}, err
+	}
+
+	entry, err := table.Lookup(output.Roll.Total)
+	if err != nil {
+		return TableResult{}, err
+	}
+
+	output.Result = entry.Result
+
+	if entry.Table != nil {
+		next, err := entry.Table.roll()
+		if err != nil {
+			return TableResult{}, err
+		}
+
+		output.Next = &next
+	}
+
+	return
+}
+
+/*
+ * Results returns the result from each table rolled on, outermost first.
+ */
+func (output TableResult) Results() (results []string) {
+	for result := &output; result != nil; result = result.Next {
+		if result.Result != "" {
+			results = append(results, result.Result)
+		}
+	}
+
+	return
+}
+
+/*
+ * String returns the results from each table rolled on, outermost first.
+ * e.g. "a gem: a ruby"
+ */
+func (output TableResult) String() string {
+	return strings.Join(output.Results(), ": ")
+}
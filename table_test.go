@@ -0,0 +1,123 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const treasureJSON = `{
+	"name": "Treasure",
+	"roll": "1d100",
+	"entries": [
+		{"min": 1, "max": 50, "result": "nothing"},
+		{"min": 51, "max": 100, "result": "a gem", "table": {
+			"name": "Gems",
+			"roll": "1d6",
+			"entries": [
+				{"min": 1, "max": 3, "result": "a garnet"},
+				{"min": 4, "max": 5, "result": "an opal"},
+				{"min": 6, "max": 6, "result": "a ruby"}
+			]
+		}}
+	]
+}`
+
+// TestTableRoll calls diceroller.Table.Roll on a nested table, checking for valid return values.
+func TestTableRoll(t *testing.T) {
+	reseed()
+
+	table, err := LoadTableJSON(strings.NewReader(treasureJSON))
+	if err != nil {
+		t.Fatalf("have %v, wanted a valid table", err)
+	}
+
+	for _, want := range []string{"a gem: a garnet", "a gem: an opal", "a gem: a garnet", "nothing"} {
+		output, err := table.Roll()
+
+		if output.String() != want || output.Table != "Treasure" || output.Roll.DiscoveredRoll != "1d100" || err != nil {
+			t.Errorf("have %q from %q %v, wanted %q from Treasure, err %v", output.String(), output.Table, output.Roll, want, err)
+		}
+
+		if (output.Next != nil) != (output.Roll.Total > 50) {
+			t.Errorf("have %v after rolling %d, wanted a nested result only above 50", output.Next, output.Roll.Total)
+		}
+	}
+
+	gap := Table{Name: "Gap", Dice: "1d1", Entries: []TableEntry{{Min: 2, Max: 6, Result: "never"}}}
+	if output, err := gap.Roll(); !errors.Is(err, ErrNoTableEntry) || !reflect.DeepEqual(output, TableResult{}) {
+		t.Errorf("have %v, %v, wanted ErrNoTableEntry", output, err)
+	}
+}
+
+// TestLoadTableCSV calls diceroller.LoadTableCSV with a percentile table, checking for valid return values.
+func TestLoadTableCSV(t *testing.T) {
+	input := "Range,Encounter\n01-50,nothing\n51–90,\"goblins, 2d4 of them\"\n91,an owlbear\n92-00,a dragon\n"
+
+	want := Table{Name: "Encounters", Dice: "1d100", Entries: []TableEntry{
+		{Min: 1, Max: 50, Result: "nothing"},
+		{Min: 51, Max: 90, Result: "goblins, 2d4 of them"},
+		{Min: 91, Max: 91, Result: "an owlbear"},
+		{Min: 92, Max: 100, Result: "a dragon"},
+	}}
+
+	if output, err := LoadTableCSV(strings.NewReader(input), "Encounters", "1d100"); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", output, want, err)
+	}
+
+	for _, input := range []string{"", "01-50\n", "01-50,nothing\nlots,more\n", "01-50,nothing\n40-60,overlap\n", "60-40,backwards\n", "1-,x\n"} {
+		if output, err := LoadTableCSV(strings.NewReader(input), "Bad", "1d100"); err == nil {
+			t.Errorf("have %v, nil error for %q, wanted an error", output, input)
+		}
+	}
+}
+
+// TestLoadTableJSONErrors calls diceroller.LoadTableJSON with tables it can't load, checking for errors.
+func TestLoadTableJSONErrors(t *testing.T) {
+	nested := `{"name": "a", "roll": "1d6", "entries": [{"min": 1, "max": 6, "result": "deeper", "table": %s}]}`
+	deep := `{"name": "a", "roll": "1d6", "entries": [{"min": 1, "max": 6, "result": "bottom"}]}`
+	for range maxTableDepth {
+		deep = strings.Replace(nested, "%s", deep, 1)
+	}
+
+	for _, input := range []string{
+		`{"name": "a", "roll": "1d6", "entries": [], "colour": "red"}`,
+		`{"name": "a", "roll": "1d0", "entries": [{"min": 1, "max": 1}]}`,
+		`{"name": "a", "roll": "1d6", "entries": []}`,
+		`{"name": "a", "roll": "1d6", "entries": [{"min": 1, "max": 6, "table": {"name": "b", "roll": "", "entries": []}}]}`,
+		deep,
+		`not json`,
+	} {
+		if output, err := LoadTableJSON(strings.NewReader(input)); err == nil || !reflect.DeepEqual(output, Table{}) {
+			t.Errorf("have %v, nil error for %q, wanted an error", output, input)
+		}
+	}
+}
+
+// BenchmarkTableRoll benchmarks diceroller.Table.Roll.
+func BenchmarkTableRoll(b *testing.B) {
+	table, _ := LoadTableJSON(strings.NewReader(treasureJSON))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = table.Roll()
+	}
+}
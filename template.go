@@ -0,0 +1,62 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// TemplateRoll is one roll as FormatTemplate gives it to a template.
+type TemplateRoll struct {
+	Expression string   // The discovered roll, e.g. '4d6kh3+1'.
+	Results    []int    // Each die, in the order it was rolled, or sorted if the roll asked for it.
+	Kept       []int    // The dice which count towards the total.
+	Dropped    []int    // The dice which don't count towards the total, e.g. the lowest of '4d6kh3'.
+	Modifier   int      // The modifier added to the total.
+	Total      int      // The total.
+	Text       string   // The roll prettified, e.g. '6 + ~~3~~ + 6 + 4 (+1) = 17'.
+	Roll       DiceRoll // Everything else, e.g. .Roll.NaturalMax.
+}
+
+/*
+ * FormatTemplate renders a DiceRoll with a text/template, so any format can be had without a function for each one. The template
+ *   is executed with a TemplateRoll, and can have any functions, such as FuncMap's.
+ * e.g. FormatTemplate(template.Must(template.New("roll").Parse(`{{.Expression}} => {{.Total}} {{.Dropped}}`)), roll) // "4d6kh3 => 16 [3]"
+ */
+func FormatTemplate(tmpl *template.Template, roll DiceRoll) (string, error) {
+	data := TemplateRoll{Expression: roll.DiscoveredRoll, Results: roll.Results, Kept: make([]int, 0, len(roll.Results)),
+		Dropped: make([]int, 0, len(roll.Dropped)), Modifier: roll.Modifier, Total: roll.Total, Text: PrettifyOne(roll), Roll: roll}
+
+	for i, result := range roll.Results {
+		if slices.Contains(roll.Dropped, i) {
+			data.Dropped = append(data.Dropped, result)
+		} else {
+			data.Kept = append(data.Kept, result)
+		}
+	}
+
+	var output strings.Builder
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
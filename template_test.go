@@ -0,0 +1,71 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+var formatTemplateTests = map[string][]string{
+	`{{.Expression}} => {{.Total}}`:                               {"4d6kh3+1 => 17", "1d20 => 20"},
+	`{{.Results}} {{.Kept}} {{.Dropped}} {{.Modifier}}`:           {"[6 3 6 4] [6 6 4] [3] 1", "[20] [20] [] 0"},
+	`{{.Text}}{{if .Roll.NaturalMax}}, a critical!{{end}}`:        {"6 + ~~3~~ + 6 + 4 (+1) = 17", "20 (nat 20), a critical!"},
+	`{{range $i, $die := .Kept}}{{if $i}}/{{end}}{{$die}}{{end}}`: {"6/6/4", "20"},
+}
+
+// TestFormatTemplate calls diceroller.FormatTemplate with templates, checking for valid return values, and that a template which
+// can't be executed is an error.
+func TestFormatTemplate(t *testing.T) {
+	rolls, _ := NewRoller(WithSeed(42, 1024)).RollDetails("4d6kh3+1", "1d20")
+
+	for text, want := range formatTemplateTests {
+		tmpl := template.Must(template.New("roll").Parse(text))
+
+		for i, roll := range rolls {
+			if output, err := FormatTemplate(tmpl, roll); output != want[i] || err != nil {
+				t.Errorf("have %q, %v, wanted %q from %q", output, err, want[i], text)
+			}
+		}
+	}
+
+	// Templates can have other functions, such as FuncMap's.
+	tmpl := template.Must(template.New("roll").Funcs(FuncMap()).Parse(`{{.Roll | prettifyFull}}`))
+	if output, err := FormatTemplate(tmpl, rolls[0]); output != "4d6kh3+1: 6 + ~~3~~ + 6 + 4 (+1) = 17" || err != nil {
+		t.Errorf("have %q, %v, wanted the roll prettified", output, err)
+	}
+
+	tmpl = template.Must(template.New("roll").Parse(`{{.Nope}}`))
+	if output, err := FormatTemplate(tmpl, rolls[0]); output != "" || err == nil || !strings.Contains(err.Error(), "Nope") {
+		t.Errorf("have %q, %v, wanted an error", output, err)
+	}
+}
+
+// BenchmarkFormatTemplate benchmarks diceroller.FormatTemplate.
+func BenchmarkFormatTemplate(b *testing.B) {
+	reseed()
+
+	rolls, _ := RollDetails("4d6kh3+1")
+	tmpl := template.Must(template.New("roll").Parse(`{{.Expression}}: {{.Kept}} => {{.Total}}`))
+
+	for i := 0; i < b.N; i++ {
+		_, _ = FormatTemplate(tmpl, rolls[0])
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TensionEvent holds the details of a tension pool being rolled.
+type TensionEvent struct {
+	Roll         DiceRoll // The pool of d6s.
+	Ones         int      // How many dice came up 1.
+	Complication bool     // Whether any dice came up 1, so something goes wrong.
+	Emptied      bool     // Whether the pool was full, so it was rolled and emptied.
+}
+
+// TensionPool is a growing pool of d6s: the GM adds one each time the players dawdle, and when it's rolled, any 1 means a complication
+// (a wandering monster, a torch guttering out). When it fills, it's rolled and emptied. It's safe for concurrent use.
+type TensionPool struct {
+	OnComplication func(TensionEvent) // Optional hook called for every roll with a complication.
+
+	mu   sync.Mutex
+	size int // How many dice fill the pool.
+	dice int // How many dice are in the pool.
+}
+
+/*
+ * NewTensionPool returns an empty TensionPool which fills at a number of dice, usually 6.
+ */
+func NewTensionPool(size int) (*TensionPool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("a tension pool needs to hold at least one die, not %d", size)
+	}
+
+	return &TensionPool{size: size}, nil
+}
+
+/*
+ * Add puts a die in the pool. If that fills it, the pool is rolled and emptied, and the roll is returned with rolled set to true.
+ */
+func (pool *TensionPool) Add() (event TensionEvent, rolled bool, err error) {
+	pool.mu.Lock()
+	pool.dice++
+
+	if pool.dice < pool.size {
+		pool.mu.Unlock()
+		return
+	}
+
+	event, err = pool.roll()
+	if err == nil {
+		event.Emptied = true
+		pool.dice = 0
+	}
+	pool.mu.Unlock()
+
+	if err != nil {
+		return TensionEvent{}, false, err
+	}
+
+	pool.notify(event)
+
+	return event, true, nil
+}
+
+/*
+ * Roll rolls the dice in the pool, leaving them there, e.g. when the players do something risky.
+ */
+func (pool *TensionPool) Roll() (TensionEvent, error) {
+	pool.mu.Lock()
+	event, err := pool.roll()
+	pool.mu.Unlock()
+
+	if err != nil {
+		return TensionEvent{}, err
+	}
+
+	pool.notify(event)
+
+	return event, nil
+}
+
+/*
+ * Dice returns how many dice are in the pool.
+ */
+func (pool *TensionPool) Dice() int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.dice
+}
+
+/*
+ * Empty takes every die out of the pool, without rolling them.
+ */
+func (pool *TensionPool) Empty() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.dice = 0
+}
+
+/*
+ * roll rolls the dice in the pool and counts the 1s. The caller must hold the lock.
+ */
+func (pool *TensionPool) roll() (event TensionEvent, err error) {
+	if event.Roll, err = postProcess(newRollSpec(pool.dice, DieSpec{Faces: 6}, 0).roll()); err != nil {
+		return
+	}
+
+	for _, result := range event.Roll.Results {
+		if result == 1 {
+			event.Ones++
+		}
+	}
+
+	event.Complication = event.Ones > 0
+
+	return
+}
+
+/*
+ * notify calls the OnComplication hook if the roll had a complication. It's called without the lock held, so the hook can use the pool.
+ */
+func (pool *TensionPool) notify(event TensionEvent) {
+	if event.Complication && pool.OnComplication != nil {
+		pool.OnComplication(event)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTensionPool adds dice to a diceroller.TensionPool until it fills and empties, checking the rolls and complications.
+func TestTensionPool(t *testing.T) {
+	reseed()
+
+	pool, err := NewTensionPool(6)
+	if err != nil {
+		t.Fatalf("have err %v, wanted nil", err)
+	}
+
+	var complications []TensionEvent
+	pool.OnComplication = func(event TensionEvent) {
+		complications = append(complications, event)
+	}
+
+	for i := 1; i < 6; i++ {
+		if _, rolled, err := pool.Add(); rolled || err != nil || pool.Dice() != i {
+			t.Fatalf("have rolled %v with %d dice, err %v, wanted %d dice", rolled, pool.Dice(), err, i)
+		}
+	}
+
+	first, err := pool.Roll()
+	if len(first.Roll.Results) != 5 || first.Complication || first.Emptied || pool.Dice() != 5 || err != nil {
+		t.Errorf("have %+v with %d dice, err %v, wanted 5 dice rolled and left, with no complication", first, pool.Dice(), err)
+	}
+
+	// The sixth die fills the pool, and it comes up with two 1s.
+	event, rolled, err := pool.Add()
+	if !rolled || len(event.Roll.Results) != 6 || event.Ones != 2 || !event.Complication || !event.Emptied || pool.Dice() != 0 || err != nil {
+		t.Errorf("have %+v, rolled %v with %d dice, err %v, wanted 6 dice rolled and emptied, with two 1s", event, rolled, pool.Dice(), err)
+	}
+
+	if len(complications) != 1 || !reflect.DeepEqual(complications[0], event) {
+		t.Errorf("have %v, wanted just %v", complications, event)
+	}
+
+	pool.Add()
+	pool.Empty()
+
+	if pool.Dice() != 0 {
+		t.Errorf("have %d dice, wanted 0", pool.Dice())
+	}
+
+	if _, err := NewTensionPool(0); err == nil {
+		t.Errorf("have nil error for a pool of no dice, wanted an error")
+	}
+}
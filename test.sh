@@ -9,4 +9,5 @@
 
 # 3. Tests, benchmarking and code coverage report.
 go test -v ./... -vet=all -coverprofile=coverage.out -bench=.
+(cd v2 && go test -v ./... -vet=all -bench=.)
 go tool cover -html=coverage.out
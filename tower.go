@@ -0,0 +1,110 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DelayDistribution is how a DiceTower's pauses vary from die to die.
+type DelayDistribution int
+
+const (
+	DelayFixed   DelayDistribution = iota // Every pause is the mean.
+	DelayUniform                          // Pauses are spread evenly from the mean minus the spread to the mean plus the spread.
+	DelayNormal                           // Pauses follow a bell curve around the mean, with the spread as its standard deviation.
+)
+
+/*
+ * String returns the distribution's name.
+ */
+func (distribution DelayDistribution) String() string {
+	switch distribution {
+	case DelayFixed:
+		return "fixed"
+	case DelayUniform:
+		return "uniform"
+	case DelayNormal:
+		return "normal"
+	default:
+		return fmt.Sprintf("DelayDistribution(%d)", int(distribution))
+	}
+}
+
+// DiceTower paces the reveal of a roll's dice one at a time, with a pause before each, so a live stream's rolls feel like dice
+// tumbling out of a real tower. The zero value reveals every die at once.
+type DiceTower struct {
+	Distribution DelayDistribution // How the pauses vary.
+	Mean         time.Duration     // The average pause before each die.
+	Spread       time.Duration     // How far the pauses stray from the mean, by the distribution. Ignored for DelayFixed.
+}
+
+/*
+ * Delay returns how long to pause before revealing one die, from the package's random source. It's never negative.
+ */
+func (tower DiceTower) Delay() time.Duration {
+	delay := tower.Mean
+
+	switch tower.Distribution {
+	case DelayUniform:
+		if tower.Spread > 0 {
+			delay += time.Duration(random.Int64N(int64(2*tower.Spread)+1)) - tower.Spread
+		}
+	case DelayNormal:
+		delay += time.Duration(random.NormFloat64() * float64(tower.Spread))
+	}
+
+	return max(delay, 0)
+}
+
+/*
+ * Delays returns how long to pause before revealing each die of a roll, as Delay does.
+ */
+func (tower DiceTower) Delays(roll DiceRoll) []time.Duration {
+	output := make([]time.Duration, len(roll.Results))
+	for i := range output {
+		output[i] = tower.Delay()
+	}
+
+	return output
+}
+
+/*
+ * Reveal calls reveal with each die of a roll in turn, after pausing for it, and returns early with the context's error if it's
+ *   cancelled, e.g. when the viewer leaves.
+ * e.g. tower.Reveal(ctx, roll, func(index, result int) { fmt.Printf("die %d: %d\n", index+1, result) })
+ */
+func (tower DiceTower) Reveal(ctx context.Context, roll DiceRoll, reveal func(index, result int)) error {
+	for i, delay := range tower.Delays(roll) {
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		reveal(i, roll.Results[i])
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type diceTowerTest struct {
+	tower DiceTower
+	want  []time.Duration
+}
+
+var diceTowerTests = []diceTowerTest{
+	{DiceTower{DelayFixed, 100 * time.Millisecond, 20 * time.Millisecond}, []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond}},
+	{DiceTower{DelayUniform, 100 * time.Millisecond, 20 * time.Millisecond}, []time.Duration{117676838, 99873110, 116085195}},
+	{DiceTower{DelayNormal, 100 * time.Millisecond, 20 * time.Millisecond}, []time.Duration{122806610, 100136201, 119952129}},
+	{DiceTower{}, []time.Duration{0, 0, 0}},
+}
+
+// TestDiceTowerDelays calls diceroller.DiceTower.Delays with each distribution, checking for valid return values.
+func TestDiceTowerDelays(t *testing.T) {
+	reseed()
+
+	for _, test := range diceTowerTests {
+		if output := test.tower.Delays(DiceRoll{Results: []int{1, 2, 3}}); !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %v, wanted %v for %+v", output, test.want, test.tower)
+		}
+	}
+
+	uniform := DiceTower{DelayUniform, 100 * time.Millisecond, 20 * time.Millisecond}
+	normal := DiceTower{DelayNormal, 10 * time.Millisecond, time.Second}
+
+	for range 1000 {
+		if delay := uniform.Delay(); delay < 80*time.Millisecond || delay > 120*time.Millisecond {
+			t.Errorf("have %v, wanted 80ms to 120ms", delay)
+		}
+
+		if delay := normal.Delay(); delay < 0 {
+			t.Errorf("have %v, wanted it to never be negative", delay)
+		}
+	}
+}
+
+// TestDiceTowerReveal calls diceroller.DiceTower.Reveal, checking every die is revealed in order, and that cancelling stops it.
+func TestDiceTowerReveal(t *testing.T) {
+	roll := DiceRoll{Results: []int{4, 1, 6}}
+
+	var revealed []int
+	err := DiceTower{Mean: time.Millisecond}.Reveal(context.Background(), roll, func(index, result int) {
+		if index != len(revealed) {
+			t.Errorf("have index %d, wanted %d", index, len(revealed))
+		}
+
+		revealed = append(revealed, result)
+	})
+
+	if !reflect.DeepEqual(revealed, roll.Results) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", revealed, roll.Results, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = DiceTower{Mean: time.Hour}.Reveal(ctx, roll, func(index, result int) {
+		t.Errorf("have %d revealed, wanted nothing after cancelling", result)
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("have err %v, wanted context.Canceled", err)
+	}
+}
+
+// TestDelayDistributionString calls diceroller.DelayDistribution.String, checking for valid return values.
+func TestDelayDistributionString(t *testing.T) {
+	for distribution, want := range map[DelayDistribution]string{DelayFixed: "fixed", DelayUniform: "uniform", DelayNormal: "normal", 7: "DelayDistribution(7)"} {
+		if distribution.String() != want {
+			t.Errorf("have %q, wanted %q", distribution.String(), want)
+		}
+	}
+}
+
+// BenchmarkDiceTowerDelays benchmarks diceroller.DiceTower.Delays.
+func BenchmarkDiceTowerDelays(b *testing.B) {
+	tower := DiceTower{DelayNormal, 100 * time.Millisecond, 20 * time.Millisecond}
+	roll := DiceRoll{Results: make([]int, 10)}
+
+	for i := 0; i < b.N; i++ {
+		_ = tower.Delays(roll)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package core parses and rolls dice: the part of diceroller most programs need. It wraps the flat github.com/vaughany/diceroller
+// API, so both can be used together and give the same results.
+package core
+
+import (
+	"github.com/vaughany/diceroller"
+)
+
+// The types are diceroller's own, so rolls and Rollers can be passed between the two.
+type (
+	DiceRoll     = diceroller.DiceRoll     // A roll's details: the dice, each result, and the total.
+	DieSpec      = diceroller.DieSpec      // A die to roll, which can be weighted.
+	Roller       = diceroller.Roller       // Rolls dice with its own random source, limits and notation.
+	RollerOption = diceroller.RollerOption // Configures a Roller made with NewRoller.
+	RandSource   = diceroller.RandSource   // Where a Roller's random numbers come from.
+)
+
+// The errors are diceroller's own, so errors.Is works with either.
+var (
+	ErrNoRollFound   = diceroller.ErrNoRollFound
+	ErrZeroFaces     = diceroller.ErrZeroFaces
+	ErrZeroDice      = diceroller.ErrZeroDice
+	ErrNotJustRoll   = diceroller.ErrNotJustRoll
+	ErrLimitExceeded = diceroller.ErrLimitExceeded
+	ErrOverflow      = diceroller.ErrOverflow
+)
+
+/*
+ * RollOne rolls the first roll in the string and returns the total, as diceroller.RollOne does.
+ * e.g. RollOne("2d6+3") // 10
+ */
+func RollOne(input string) (int, error) {
+	return diceroller.RollOne(input)
+}
+
+/*
+ * Roll rolls each string and returns their totals, as diceroller.Roll does.
+ * e.g. Roll("2d6+3", "1d20") // [10 14]
+ */
+func Roll(input ...string) ([]int, error) {
+	return diceroller.Roll(input...)
+}
+
+/*
+ * RollTotal rolls each string and returns the sum of their totals, as diceroller.RollTotal does.
+ * e.g. RollTotal("2d6+3", "1d20") // 24
+ */
+func RollTotal(input ...string) (int, error) {
+	return diceroller.RollTotal(input...)
+}
+
+/*
+ * RollDetails rolls each string and returns each roll's details, as diceroller.RollDetails does.
+ * e.g. RollDetails("2d6+3") // [{2d6+3 6 2 3 [4 3] 10 ...}]
+ */
+func RollDetails(input ...string) ([]DiceRoll, error) {
+	return diceroller.RollDetails(input...)
+}
+
+/*
+ * RollDie rolls a die, which can be weighted, a number of times and adds the modifier, as diceroller.RollDie does.
+ * e.g. RollDie(DieSpec{Faces: 6}, 2, 3) // {2d6+3 6 2 3 [4 3] 10 ...}
+ */
+func RollDie(die DieSpec, rolls, modifier int) (DiceRoll, error) {
+	return diceroller.RollDie(die, rolls, modifier)
+}
+
+/*
+ * Parse returns each roll found in the strings, without rolling them, as diceroller.Parse does.
+ * e.g. Parse("roll 2d6+3 and 1d20") // [2d6+3 1d20]
+ */
+func Parse(input ...string) ([]string, error) {
+	return diceroller.Parse(input...)
+}
+
+/*
+ * SetSeed seeds the package's random source, which this package shares with diceroller, as diceroller.SetSeed does.
+ */
+func SetSeed(seed uint64) {
+	diceroller.SetSeed(seed)
+}
+
+/*
+ * NewRoller returns a Roller configured with the options, as diceroller.NewRoller does.
+ * e.g. NewRoller(WithSeed(1, 2), WithMaxDice(100))
+ */
+func NewRoller(options ...RollerOption) *Roller {
+	return diceroller.NewRoller(options...)
+}
+
+/*
+ * WithSeed makes a Roller deterministic, so two with the same seeds roll the same dice, as diceroller.WithSeed does.
+ */
+func WithSeed(seed1, seed2 uint64) RollerOption {
+	return diceroller.WithSeed(seed1, seed2)
+}
+
+/*
+ * WithRandSource makes a Roller's dice come from the source, as diceroller.WithRandSource does.
+ */
+func WithRandSource(source RandSource) RollerOption {
+	return diceroller.WithRandSource(source)
+}
+
+/*
+ * WithCryptoRand makes a Roller's dice come from crypto/rand, as diceroller.WithCryptoRand does.
+ */
+func WithCryptoRand() RollerOption {
+	return diceroller.WithCryptoRand()
+}
+
+/*
+ * WithMaxDice limits how many dice a Roller will roll at once, as diceroller.WithMaxDice does.
+ */
+func WithMaxDice(max int) RollerOption {
+	return diceroller.WithMaxDice(max)
+}
+
+/*
+ * WithMaxFaces limits how many faces a Roller's dice can have, as diceroller.WithMaxFaces does.
+ */
+func WithMaxFaces(max int) RollerOption {
+	return diceroller.WithMaxFaces(max)
+}
+
+/*
+ * WithMaxTotalDice limits how many dice a Roller will roll in one call, as diceroller.WithMaxTotalDice does.
+ */
+func WithMaxTotalDice(max int) RollerOption {
+	return diceroller.WithMaxTotalDice(max)
+}
@@ -0,0 +1,77 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vaughany/diceroller"
+)
+
+// TestRollerSame calls core's functions and diceroller's with Rollers seeded the same, checking they roll the same dice.
+func TestRollerSame(t *testing.T) {
+	have, err := NewRoller(WithSeed(42, 1024), WithMaxDice(10)).RollDetails("2d6+3", "4d6kh3")
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	want, _ := diceroller.NewRoller(diceroller.WithSeed(42, 1024), diceroller.WithMaxDice(10)).RollDetails("2d6+3", "4d6kh3")
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, wanted %v", have, want)
+	}
+}
+
+// TestPackageSame calls core's package functions and diceroller's after seeding them the same, checking they roll the same dice.
+func TestPackageSame(t *testing.T) {
+	SetSeed(42)
+	have, err := RollDetails("3d6", "1d20+5")
+	if err != nil {
+		t.Fatalf("have err %v", err)
+	}
+
+	diceroller.SetSeed(42)
+	want, _ := diceroller.RollDetails("3d6", "1d20+5")
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, wanted %v", have, want)
+	}
+
+	if parsed, err := Parse("roll 2d6+3 and 1d20"); !reflect.DeepEqual(parsed, []string{"2d6+3", "1d20"}) || err != nil {
+		t.Errorf("have %v, err %v, wanted [2d6+3 1d20]", parsed, err)
+	}
+}
+
+// TestErrors calls core's functions with bad rolls, checking the errors are diceroller's own.
+func TestErrors(t *testing.T) {
+	if _, err := RollOne("nothing"); !errors.Is(err, diceroller.ErrNoRollFound) || !errors.Is(err, ErrNoRollFound) {
+		t.Errorf("have err %v, wanted %v", err, ErrNoRollFound)
+	}
+
+	if _, err := NewRoller(WithMaxDice(2)).RollOne("3d6"); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("have err %v, wanted %v", err, ErrLimitExceeded)
+	}
+}
+
+// BenchmarkRollDetails benchmarks core.RollDetails.
+func BenchmarkRollDetails(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollDetails("4d6kh3")
+	}
+}
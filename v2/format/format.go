@@ -0,0 +1,100 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package format displays and encodes dice rolls: nicely for people, and as canonical text for storing. It wraps the flat
+// github.com/vaughany/diceroller API, so rolls from either can be formatted with either.
+package format
+
+import (
+	"text/template"
+
+	"github.com/vaughany/diceroller"
+)
+
+// The types are diceroller's own.
+type (
+	Formatter    = diceroller.Formatter    // Displays rolls nicely, with options for how.
+	TemplateRoll = diceroller.TemplateRoll // One roll as FormatTemplate gives it to a template.
+)
+
+/*
+ * Prettify displays each roll nicely, as diceroller.Prettify does.
+ * e.g. []string{"1 + 2 + 3 + 4 = 10"}
+ */
+func Prettify(input []diceroller.DiceRoll) []string {
+	return diceroller.Prettify(input)
+}
+
+/*
+ * PrettifyFull displays each roll nicely, with the roll itself, as diceroller.PrettifyFull does.
+ */
+func PrettifyFull(input []diceroller.DiceRoll) []string {
+	return diceroller.PrettifyFull(input)
+}
+
+/*
+ * PrettifyOne displays one roll nicely, as diceroller.PrettifyOne does.
+ */
+func PrettifyOne(input diceroller.DiceRoll) string {
+	return diceroller.PrettifyOne(input)
+}
+
+/*
+ * PrettifyOneFull displays one roll nicely, with the roll itself, as diceroller.PrettifyOneFull does.
+ */
+func PrettifyOneFull(input diceroller.DiceRoll) string {
+	return diceroller.PrettifyOneFull(input)
+}
+
+/*
+ * PrettifyHTML displays each roll nicely as HTML, as diceroller.PrettifyHTML does.
+ */
+func PrettifyHTML(input []diceroller.DiceRoll) []string {
+	return diceroller.PrettifyHTML(input)
+}
+
+/*
+ * PrettifyHTMLFull displays each roll nicely as HTML, with the roll itself, as diceroller.PrettifyHTMLFull does.
+ */
+func PrettifyHTMLFull(input []diceroller.DiceRoll) []string {
+	return diceroller.PrettifyHTMLFull(input)
+}
+
+/*
+ * FormatTemplate renders a roll with a text/template, as diceroller.FormatTemplate does.
+ * e.g. FormatTemplate(template.Must(template.New("roll").Parse(`{{.Expression}} => {{.Total}}`)), roll) // "4d6kh3 => 16"
+ */
+func FormatTemplate(tmpl *template.Template, roll diceroller.DiceRoll) (string, error) {
+	return diceroller.FormatTemplate(tmpl, roll)
+}
+
+/*
+ * FormatCanonical encodes a roll as one stable line of text, as diceroller.FormatCanonical does.
+ * e.g. FormatCanonical(roll) // "4d6kh3+1=[3,1x,6,4]=14"
+ */
+func FormatCanonical(input diceroller.DiceRoll) string {
+	return diceroller.FormatCanonical(input)
+}
+
+/*
+ * ParseCanonical decodes a roll encoded by FormatCanonical, as diceroller.ParseCanonical does.
+ * e.g. ParseCanonical("4d6kh3+1=[3,1x,6,4]=14") // {4d6kh3+1 6 4 1 [3 1 6 4] 14 [] [1] ...}
+ */
+func ParseCanonical(input string) (diceroller.DiceRoll, error) {
+	return diceroller.ParseCanonical(input)
+}
@@ -0,0 +1,74 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package format
+
+import (
+	"reflect"
+	"testing"
+	"text/template"
+
+	"github.com/vaughany/diceroller"
+)
+
+var roll = diceroller.DiceRoll{DiscoveredRoll: "4d6kh3+1", Faces: 6, Rolls: 4, Modifier: 1, Results: []int{3, 1, 6, 4}, Total: 14,
+	NaturalTotal: 13, Dropped: []int{1}}
+
+// TestFormatSame calls format's functions and diceroller's with the same roll, checking they display it the same.
+func TestFormatSame(t *testing.T) {
+	rolls := []diceroller.DiceRoll{roll}
+
+	for _, test := range [][2]any{
+		{Prettify(rolls), diceroller.Prettify(rolls)},
+		{PrettifyFull(rolls), diceroller.PrettifyFull(rolls)},
+		{PrettifyOne(roll), diceroller.PrettifyOne(roll)},
+		{PrettifyOneFull(roll), diceroller.PrettifyOneFull(roll)},
+		{PrettifyHTML(rolls), diceroller.PrettifyHTML(rolls)},
+		{PrettifyHTMLFull(rolls), diceroller.PrettifyHTMLFull(rolls)},
+		{Formatter{ShowRoll: true}.Format(roll), diceroller.Formatter{ShowRoll: true}.Format(roll)},
+	} {
+		if !reflect.DeepEqual(test[0], test[1]) {
+			t.Errorf("have %v, wanted %v", test[0], test[1])
+		}
+	}
+
+	tmpl := template.Must(template.New("roll").Parse(`{{.Expression}} => {{.Total}}`))
+	if output, err := FormatTemplate(tmpl, roll); output != "4d6kh3+1 => 14" || err != nil {
+		t.Errorf("have %q, err %v, wanted %q", output, err, "4d6kh3+1 => 14")
+	}
+}
+
+// TestCanonical calls format.FormatCanonical and format.ParseCanonical, checking a roll comes back the same.
+func TestCanonical(t *testing.T) {
+	text := FormatCanonical(roll)
+	if text != "4d6kh3+1=[3,1x,6,4]=14" {
+		t.Errorf("have %q, wanted %q", text, "4d6kh3+1=[3,1x,6,4]=14")
+	}
+
+	output, err := ParseCanonical(text)
+	if err != nil || FormatCanonical(output) != text {
+		t.Errorf("have %v, err %v, wanted %v", output, err, roll)
+	}
+}
+
+// BenchmarkPrettifyOne benchmarks format.PrettifyOne.
+func BenchmarkPrettifyOne(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = PrettifyOne(roll)
+	}
+}
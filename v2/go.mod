@@ -0,0 +1,8 @@
+module github.com/vaughany/diceroller/v2
+
+go 1.23.0
+
+require github.com/vaughany/diceroller v0.0.0-00010101000000-000000000000
+
+// The flat API in the parent directory does the work; these packages wrap it.
+replace github.com/vaughany/diceroller => ../
@@ -0,0 +1,76 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package integrations makes a Roller understand other tools' and people's dice notations: Roll20's, Foundry VTT's, plain English
+// and other letters for the 'd', as well as giving templates functions to roll with. It wraps the flat
+// github.com/vaughany/diceroller API, so its options work with a Roller from either.
+package integrations
+
+import (
+	"github.com/vaughany/diceroller"
+)
+
+/*
+ * WithRoll20 makes a Roller understand Roll20's inline rolls, as diceroller.WithRoll20 does.
+ * e.g. core.NewRoller(WithRoll20()).RollOne("[[2d20kh1+5]]") // 22
+ */
+func WithRoll20() diceroller.RollerOption {
+	return diceroller.WithRoll20()
+}
+
+/*
+ * WithFoundry makes a Roller understand Foundry VTT's dice formulas, with '@' references into the data, as diceroller.WithFoundry does.
+ * e.g. core.NewRoller(WithFoundry(map[string]any{"prof": 2})).RollOne("1d20 + @prof") // 16
+ */
+func WithFoundry(data map[string]any) diceroller.RollerOption {
+	return diceroller.WithFoundry(data)
+}
+
+/*
+ * WithNaturalLanguage makes a Roller understand phrases such as 'roll two six-sided dice plus three', as
+ *   diceroller.WithNaturalLanguage does.
+ * e.g. core.NewRoller(WithNaturalLanguage()).RollOne("three d twenties") // 31
+ */
+func WithNaturalLanguage() diceroller.RollerOption {
+	return diceroller.WithNaturalLanguage()
+}
+
+/*
+ * ParseNaturalLanguage turns a phrase into a roll in the 'nDn+n' format, as diceroller.ParseNaturalLanguage does.
+ * e.g. ParseNaturalLanguage("four d sixes, drop the lowest") // "4d6kh3"
+ */
+func ParseNaturalLanguage(phrase string) (string, error) {
+	return diceroller.ParseNaturalLanguage(phrase)
+}
+
+/*
+ * WithDiceLetters makes a Roller accept other letters for the 'd', as diceroller.WithDiceLetters does.
+ * e.g. core.NewRoller(WithDiceLetters('W')).RollOne("2W6+1") // 8
+ */
+func WithDiceLetters(letters ...rune) diceroller.RollerOption {
+	return diceroller.WithDiceLetters(letters...)
+}
+
+/*
+ * FuncMap returns functions for text/template and html/template which roll with the package's default Roller, as
+ *   diceroller.FuncMap does.
+ * e.g. template.New("page").Funcs(FuncMap()).Parse(`You hit for {{roll "2d6+3"}}`)
+ */
+func FuncMap() map[string]any {
+	return diceroller.FuncMap()
+}
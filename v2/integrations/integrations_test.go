@@ -0,0 +1,74 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package integrations
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/vaughany/diceroller"
+)
+
+// TestOptionsSame calls Rollers made with integrations' options and diceroller's, seeded the same, checking they roll the same.
+func TestOptionsSame(t *testing.T) {
+	for _, test := range []struct {
+		have, want diceroller.RollerOption
+		input      string
+	}{
+		{WithRoll20(), diceroller.WithRoll20(), "[[2d20kh1+5]]"},
+		{WithFoundry(map[string]any{"prof": 2}), diceroller.WithFoundry(map[string]any{"prof": 2}), "1d20 + @prof"},
+		{WithNaturalLanguage(), diceroller.WithNaturalLanguage(), "three d twenties"},
+		{WithDiceLetters('W'), diceroller.WithDiceLetters('W'), "2W6+1"},
+	} {
+		have, err := diceroller.NewRoller(diceroller.WithSeed(42, 1024), test.have).RollDetails(test.input)
+		if err != nil {
+			t.Errorf("have err %v for %q", err, test.input)
+		}
+
+		want, _ := diceroller.NewRoller(diceroller.WithSeed(42, 1024), test.want).RollDetails(test.input)
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("have %v, wanted %v for %q", have, want, test.input)
+		}
+	}
+
+	if output, err := ParseNaturalLanguage("four d sixes, drop the lowest"); output != "4d6kh3" || err != nil {
+		t.Errorf("have %q, err %v, wanted %q", output, err, "4d6kh3")
+	}
+}
+
+// TestFuncMap calls integrations.FuncMap in a template, checking it rolls.
+func TestFuncMap(t *testing.T) {
+	var output strings.Builder
+
+	tmpl := template.Must(template.New("page").Funcs(FuncMap()).Parse(`{{roll "1d1+2"}}`))
+	if err := tmpl.Execute(&output, nil); err != nil || output.String() != "3" {
+		t.Errorf("have %q, err %v, wanted %q", output.String(), err, "3")
+	}
+}
+
+// BenchmarkWithRoll20 benchmarks a Roller made with integrations.WithRoll20.
+func BenchmarkWithRoll20(b *testing.B) {
+	roller := diceroller.NewRoller(WithRoll20())
+
+	for i := 0; i < b.N; i++ {
+		_, _ = roller.RollOne("[[2d20kh1+5]]")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package stats works out the odds of dice rolls, exactly or by simulation, without caring how they came up. It wraps the flat
+// github.com/vaughany/diceroller API.
+package stats
+
+import (
+	"github.com/vaughany/diceroller"
+)
+
+// The types are diceroller's own, so their methods (AtLeast, Histogram, CSV and so on) come with them.
+type (
+	RollDistribution = diceroller.RollDistribution // The exact probability of every possible total of a roll.
+	Simulation       = diceroller.Simulation       // The results of rolling a roll many times.
+	Histogram        = diceroller.Histogram        // A distribution or simulation, ready to be drawn.
+	SuccessGrid      = diceroller.SuccessGrid      // The chance of a roll plus each bonus meeting or beating each DC.
+)
+
+/*
+ * Distribution works out the exact probability of every possible total of one roll, as diceroller.Distribution does.
+ * e.g. Distribution("2d6") // {2d6 2 12 7 2.41 [0.027 0.055 0.083 ...]}
+ */
+func Distribution(input string) (RollDistribution, error) {
+	return diceroller.Distribution(input)
+}
+
+/*
+ * Odds answers a question such as 'at least 18 on 3d6' with the exact probability, as diceroller.Odds does.
+ * e.g. Odds("at least 18 on 3d6") // 0.004629629629629629
+ */
+func Odds(question string) (float64, error) {
+	return diceroller.Odds(question)
+}
+
+/*
+ * ProbabilityAtLeast returns the chance of one roll totalling the target or more, as diceroller.ProbabilityAtLeast does.
+ * e.g. ProbabilityAtLeast("2d6+3", 11) // 0.41666666666666663
+ */
+func ProbabilityAtLeast(input string, target int) (float64, error) {
+	return diceroller.ProbabilityAtLeast(input, target)
+}
+
+/*
+ * ExpectedValue returns the average total of one roll, without rolling it, as diceroller.ExpectedValue does.
+ * e.g. ExpectedValue("2d6+2") // 9
+ */
+func ExpectedValue(input string) (float64, error) {
+	return diceroller.ExpectedValue(input)
+}
+
+/*
+ * MinValue returns the lowest total one roll can come to, as diceroller.MinValue does.
+ * e.g. MinValue("4d6kh3+1") // 4
+ */
+func MinValue(input string) (int, error) {
+	return diceroller.MinValue(input)
+}
+
+/*
+ * MaxValue returns the highest total one roll can come to, as diceroller.MaxValue does.
+ * e.g. MaxValue("4d6kh3+1") // 19
+ */
+func MaxValue(input string) (int, error) {
+	return diceroller.MaxValue(input)
+}
+
+/*
+ * Simulate rolls one roll many times and returns how often each total came up, as diceroller.Simulate does.
+ * e.g. Simulate("3d6", 1_000_000) // {3d6 1000000 3 18 10.5 2.96 [4610 13904 ...]}
+ */
+func Simulate(input string, rolls int) (Simulation, error) {
+	return diceroller.Simulate(input, rolls)
+}
+
+/*
+ * NewSuccessGrid works out the chance of one roll plus each bonus meeting or beating each DC, as diceroller.NewSuccessGrid does.
+ * e.g. NewSuccessGrid("2d20kh1", 0, 5, 10, 20)
+ */
+func NewSuccessGrid(input string, minBonus, maxBonus, minDC, maxDC int) (SuccessGrid, error) {
+	return diceroller.NewSuccessGrid(input, minBonus, maxBonus, minDC, maxDC)
+}
@@ -0,0 +1,61 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package stats
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vaughany/diceroller"
+)
+
+// TestStatsSame calls stats' functions and diceroller's with the same rolls, checking they give the same answers.
+func TestStatsSame(t *testing.T) {
+	for _, test := range []struct {
+		have, want any
+	}{
+		{first(Distribution("2d6")), first(diceroller.Distribution("2d6"))},
+		{first(Odds("at least 18 on 3d6")), first(diceroller.Odds("at least 18 on 3d6"))},
+		{first(ProbabilityAtLeast("2d6+3", 11)), first(diceroller.ProbabilityAtLeast("2d6+3", 11))},
+		{first(ExpectedValue("2d6+2")), 9.0},
+		{first(MinValue("4d6kh3+1")), 4},
+		{first(MaxValue("4d6kh3+1")), 19},
+		{first(NewSuccessGrid("1d20", 0, 1, 10, 11)), first(diceroller.NewSuccessGrid("1d20", 0, 1, 10, 11))},
+	} {
+		if !reflect.DeepEqual(test.have, test.want) {
+			t.Errorf("have %v, wanted %v", test.have, test.want)
+		}
+	}
+
+	if output, err := Simulate("3d6", 1000); output.Rolls != 1000 || output.Min < 3 || output.Max > 18 || err != nil {
+		t.Errorf("have %v, err %v, wanted 1000 rolls between 3 and 18", output, err)
+	}
+}
+
+// first returns the first of two return values, for comparing them in a table.
+func first[T any](value T, _ error) T {
+	return value
+}
+
+// BenchmarkDistribution benchmarks stats.Distribution.
+func BenchmarkDistribution(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = Distribution("4d6kh3")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+/*
+ * ExpectedValue returns the average total of one roll in the 'nDn+n' format, without rolling it, e.g. to compare 2d6+2 with 1d12+3
+ *   when balancing an encounter. Rolls which keep all their dice are worked out directly, so even '99999d99999' is instant, and rolls
+ *   which drop dice are worked out from their Distribution.
+ * e.g. ExpectedValue("2d6+2") // 9
+ */
+func ExpectedValue(input string) (float64, error) {
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return 0, err
+	}
+
+	if spec.drops() {
+		dist, err := spec.distribution()
+		if err != nil {
+			return 0, err
+		}
+
+		return dist.mean(), nil
+	}
+
+	return float64(spec.rolls)*(float64(spec.die.Faces+1)/2+float64(spec.dieModifier)) + float64(spec.modifier), nil
+}
+
+/*
+ * MinValue returns the lowest total one roll in the 'nDn+n' format can come to, without rolling it.
+ * e.g. MinValue("4d6kh3+1") // 4
+ */
+func MinValue(input string) (int, error) {
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return 0, err
+	}
+
+	return spec.kept()*(1+spec.dieModifier) + spec.modifier, nil
+}
+
+/*
+ * MaxValue returns the highest total one roll in the 'nDn+n' format can come to, without rolling it.
+ * e.g. MaxValue("4d6kh3+1") // 19
+ */
+func MaxValue(input string) (int, error) {
+	spec, err := parseWholeRoll(input)
+	if err != nil {
+		return 0, err
+	}
+
+	return spec.kept()*(spec.die.Faces+spec.dieModifier) + spec.modifier, nil
+}
+
+/*
+ * drops returns whether a rollSpec drops any of its dice.
+ */
+func (spec rollSpec) drops() bool {
+	return spec.keep != "" && spec.keepCount < spec.rolls
+}
+
+/*
+ * kept returns how many of a rollSpec's dice count towards the total.
+ */
+func (spec rollSpec) kept() int {
+	if spec.drops() {
+		return spec.keepCount
+	}
+
+	return spec.rolls
+}
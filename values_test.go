@@ -0,0 +1,76 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"math"
+	"testing"
+)
+
+type valuesTest struct {
+	input            string
+	wantMin, wantMax int
+	wantMean         float64
+}
+
+var valuesTests = []valuesTest{
+	{"1d6", 1, 6, 3.5},
+	{"2d6+2", 4, 14, 9},
+	{"1d12+3", 4, 15, 9.5},
+	{"4d6kh3+1", 4, 19, 13.244598765432098},
+	{"d20adv", 1, 20, 13.825},
+	{"3d6--1", 0, 15, 7.5},
+	{"4d6++1-2", 6, 26, 16},
+	{"9999d9999", 9999, 99_980_001, 49_995_000},
+}
+
+// TestValues calls diceroller.ExpectedValue, diceroller.MinValue and diceroller.MaxValue, checking them against Distribution where it can.
+func TestValues(t *testing.T) {
+	for _, test := range valuesTests {
+		mean, errMean := ExpectedValue(test.input)
+		low, errMin := MinValue(test.input)
+		high, errMax := MaxValue(test.input)
+
+		if low != test.wantMin || high != test.wantMax || math.Abs(mean-test.wantMean) > 1e-9 || errMean != nil || errMin != nil || errMax != nil {
+			t.Errorf("have %d %d %v, wanted %d %d %v for %q, errs %v %v %v", low, high, mean, test.wantMin, test.wantMax, test.wantMean,
+				test.input, errMin, errMax, errMean)
+		}
+
+		if dist, err := Distribution(test.input); err == nil && (dist.Min != low || dist.Max != high || math.Abs(dist.Mean-mean) > 1e-9) {
+			t.Errorf("have %d %d %v, wanted the distribution's %d %d %v for %q", low, high, mean, dist.Min, dist.Max, dist.Mean, test.input)
+		}
+	}
+
+	for _, input := range []string{"", "2d6 please", "2d0"} {
+		_, errMean := ExpectedValue(input)
+		_, errMin := MinValue(input)
+		_, errMax := MaxValue(input)
+
+		if errMean == nil || errMin == nil || errMax == nil {
+			t.Errorf("have errs %v %v %v for %q, wanted errors", errMean, errMin, errMax, input)
+		}
+	}
+}
+
+// BenchmarkExpectedValue benchmarks diceroller.ExpectedValue.
+func BenchmarkExpectedValue(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ExpectedValue("2d6+2")
+	}
+}
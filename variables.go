@@ -0,0 +1,152 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// A variable added to or taken off a roll, e.g. '+STR' or '-prof'. Only names after a sign are variables, so the letters in the
+	// dice notation itself (the 'd' in '2d6', or 'kh' and 'adv') aren't mistaken for them.
+	variableRegex = regexp.MustCompile(`([+-])([A-Za-z_][A-Za-z0-9_]*)`)
+
+	// One or more plain modifiers, e.g. '+3-1+2', as left once the variables have been replaced with numbers.
+	modifierChainRegex = regexp.MustCompile(`^(?:[+-]\d{1,9})+$`)
+
+	// ErrVariableNotFound is returned when a roll uses a variable which isn't in the map of variables.
+	ErrVariableNotFound = errors.New("variable not found")
+)
+
+/*
+ * ResolveVariables replaces the variables in one roll, e.g. the 'STR' and 'prof' in '1d20+STR+prof', with their values, and adds them
+ *   and any other modifiers up into one, so the roll can go to the Roll functions. Variable names are case-sensitive, and can only be
+ *   added to or taken off the total; a name not in the map returns ErrVariableNotFound.
+ * e.g. ResolveVariables("1d20+STR+prof", map[string]int{"STR": 3, "prof": 2}) // "1d20+5"
+ */
+func ResolveVariables(input string, variables map[string]int) (string, error) {
+	input = inputReplacer.Replace(input)
+
+	var err error
+
+	input = variableRegex.ReplaceAllStringFunc(input, func(match string) string {
+		value, ok := variables[match[1:]]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("%w: %q", ErrVariableNotFound, match[1:])
+			}
+
+			return match
+		}
+
+		if match[0] == '-' {
+			value = -value
+		}
+
+		return fmt.Sprintf("%+d", value)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	output, err := foldModifiers(input)
+	if err != nil {
+		return "", err
+	}
+
+	// Check the whole thing is still one roll, so e.g. a modifier too big for the notation is an error rather than being cut short.
+	if _, err = parseWholeRoll(output); err != nil {
+		return "", err
+	}
+
+	return output, nil
+}
+
+/*
+ * foldModifiers adds up the modifiers at the end of a roll into one. The roll is the shortest start of the input which is a whole roll
+ *   by itself, followed by nothing but modifiers, so '4d6++1+3+2' splits as '4d6++1' and '+3+2', not '4d6+' and '+1+3+2'.
+ */
+func foldModifiers(input string) (string, error) {
+	for i := 1; i < len(input); i++ {
+		if !modifierChainRegex.MatchString(input[i:]) {
+			continue
+		}
+
+		if _, err := parseWholeRoll(input[:i]); err != nil {
+			continue
+		}
+
+		modifier, err := sumModifiers(input[i:])
+		if err != nil {
+			return "", err
+		}
+
+		if modifier == 0 {
+			return input[:i], nil
+		}
+
+		return input[:i] + fmt.Sprintf("%+d", modifier), nil
+	}
+
+	return input, nil
+}
+
+/*
+ * sumModifiers adds up a chain of modifiers such as '+3-1+2'.
+ */
+func sumModifiers(input string) (total int, err error) {
+	start := 0
+
+	for i := 1; i <= len(input); i++ {
+		if i < len(input) && input[i] != '+' && input[i] != '-' {
+			continue
+		}
+
+		value, err := strconv.Atoi(input[start:i])
+		if err != nil {
+			return 0, err
+		}
+
+		total += value
+		start = i
+	}
+
+	return
+}
+
+/*
+ * RollWithVariables resolves each roll's variables, as ResolveVariables does, and rolls them, returning all the details as RollDetails does.
+ * e.g. RollWithVariables(map[string]int{"STR": 3, "prof": 2}, "1d20+STR+prof") // [{1d20+5 20 1 5 [14] 19 ...}]
+ */
+func RollWithVariables(variables map[string]int, input ...string) ([]DiceRoll, error) {
+	resolved := make([]string, len(input))
+
+	for i, roll := range input {
+		var err error
+		if resolved[i], err = ResolveVariables(roll, variables); err != nil {
+			return nil, err
+		}
+	}
+
+	return RollDetails(resolved...)
+}
@@ -0,0 +1,90 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"errors"
+	"testing"
+)
+
+var testVariables = map[string]int{"STR": 3, "DEX": -1, "prof": 2, "level": 5, "zero": 0, "huge": 1_000_000}
+
+type resolveVariablesTest struct {
+	got     string
+	want    string
+	wantErr error
+}
+
+var resolveVariablesTests = []resolveVariablesTest{
+	{"1d20+STR+prof", "1d20+5", nil},
+	{"1d20 + STR + prof", "1d20+5", nil},
+	{"1d20+DEX", "1d20-1", nil},
+	{"1d20-DEX", "1d20+1", nil},
+	{"1d20+STR-STR", "1d20", nil},
+	{"1d20+zero", "1d20", nil},
+	{"2d6+4+level-1", "2d6+8", nil},
+	{"4d6++1+STR", "4d6++1+3", nil},
+	{"4d6kh3sd+prof", "4d6kh3sd+2", nil},
+	{"d20adv+STR+prof", "d20adv+5", nil},
+	{"1d20+5", "1d20+5", nil},
+	{"2d6", "2d6", nil},
+	{"1d20+str", "", ErrVariableNotFound},
+	{"1d20+STR+wis", "", ErrVariableNotFound},
+	{"1d20+huge", "", nil},
+	{"STR", "", nil},
+	{"1d20+STR please", "", nil},
+}
+
+// TestResolveVariables calls diceroller.ResolveVariables with many rolls, checking for valid return values.
+func TestResolveVariables(t *testing.T) {
+	for _, test := range resolveVariablesTests {
+		output, err := ResolveVariables(test.got, testVariables)
+
+		switch {
+		case test.want == "" && err == nil:
+			t.Errorf("have %q, nil error for %q, wanted an error", output, test.got)
+		case test.want != "" && (output != test.want || err != nil):
+			t.Errorf("have %q, wanted %q for %q, err %v", output, test.want, test.got, err)
+		case test.wantErr != nil && !errors.Is(err, test.wantErr):
+			t.Errorf("have err %v for %q, wanted %v", err, test.got, test.wantErr)
+		}
+	}
+}
+
+// TestRollWithVariables calls diceroller.RollWithVariables, checking the variables are added to each roll.
+func TestRollWithVariables(t *testing.T) {
+	reseed()
+
+	output, err := RollWithVariables(testVariables, "1d20+STR+prof", "2d6+level")
+	if len(output) != 2 || output[0].DiscoveredRoll != "1d20+5" || output[0].Modifier != 5 || output[1].DiscoveredRoll != "2d6+5" ||
+		err != nil {
+		t.Errorf("have %v, wanted rolls of 1d20+5 and 2d6+5, err %v", output, err)
+	}
+
+	if output, err := RollWithVariables(testVariables, "1d20", "1d20+cha"); !errors.Is(err, ErrVariableNotFound) || output != nil {
+		t.Errorf("have %v, err %v, wanted ErrVariableNotFound", output, err)
+	}
+}
+
+// BenchmarkResolveVariables benchmarks diceroller.ResolveVariables.
+func BenchmarkResolveVariables(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ResolveVariables("4d6++1+STR+prof", testVariables)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+// WFRPRoll holds the details of a Warhammer Fantasy Roleplay percentile test.
+type WFRPRoll struct {
+	Roll          DiceRoll // The d100 roll.
+	Skill         int      // The skill (or characteristic) value rolled against.
+	Success       bool     // Whether the roll was the skill or under, or 1 to 5, and wasn't 96 to 100.
+	SuccessLevels int      // The tens digit of the skill, minus the tens digit of the roll. Never negative on a success, or positive on a failure.
+	Double        bool     // Whether both digits of the roll were the same, e.g. 33, counting 100 as '00'.
+	Critical      bool     // Whether it was a double and a success.
+	Fumble        bool     // Whether it was a double and a failure.
+}
+
+/*
+ * RollWFRP rolls d100 under a skill value per the Warhammer Fantasy Roleplay 4th edition rules, working out the success levels from
+ *   the difference in tens digits, and spotting doubles for criticals and fumbles. 1 to 5 always succeeds, and 96 to 100 always fails.
+ * e.g. RollWFRP(45) // {{1d100 100 1 0 [95] 95 ...} 45 false -5 false false false}
+ */
+func RollWFRP(skill int) (output WFRPRoll, err error) {
+	return defaultRoller.RollWFRP(skill)
+}
+
+/*
+ * RollWFRP is like the package's RollWFRP, but rolls with the Roller's random source.
+ * e.g. roller.RollWFRP(45)
+ */
+func (roller *Roller) RollWFRP(skill int) (output WFRPRoll, err error) {
+	if output.Roll, err = roller.rollParsed(newRollSpec(1, DieSpec{Faces: 100}, 0)); err != nil {
+		return
+	}
+
+	if output.Roll, err = postProcess(output.Roll); err != nil {
+		return
+	}
+
+	output.Skill = skill
+	output.evaluate()
+
+	return
+}
+
+/*
+ * evaluate works out the success, success levels, and doubles from the roll and skill.
+ */
+func (output *WFRPRoll) evaluate() {
+	result := output.Roll.Total
+
+	switch {
+	case result <= 5:
+		output.Success = true
+	case result >= 96:
+		output.Success = false
+	default:
+		output.Success = result <= output.Skill
+	}
+
+	output.SuccessLevels = output.Skill/10 - result/10
+	if output.Success {
+		output.SuccessLevels = max(output.SuccessLevels, 0)
+	} else {
+		output.SuccessLevels = min(output.SuccessLevels, 0)
+	}
+
+	output.Double = result == 100 || (result < 100 && result%11 == 0)
+	output.Critical = output.Double && output.Success
+	output.Fumble = output.Double && !output.Success
+}
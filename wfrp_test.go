@@ -0,0 +1,74 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type wfrpEvaluateTest struct {
+	result, skill int
+	want          WFRPRoll
+}
+
+var wfrpEvaluateTests = []wfrpEvaluateTest{
+	{22, 45, WFRPRoll{Success: true, SuccessLevels: 2, Double: true, Critical: true}},
+	{45, 45, WFRPRoll{Success: true}},
+	{46, 45, WFRPRoll{SuccessLevels: 0}},
+	{73, 45, WFRPRoll{SuccessLevels: -3}},
+	{77, 45, WFRPRoll{SuccessLevels: -3, Double: true, Fumble: true}},
+	{3, 1, WFRPRoll{Success: true}},
+	{5, 0, WFRPRoll{Success: true}},
+	{96, 120, WFRPRoll{}},
+	{99, 120, WFRPRoll{Double: true, Fumble: true}},
+	{100, 120, WFRPRoll{Double: true, Fumble: true}},
+	{10, 45, WFRPRoll{Success: true, SuccessLevels: 3}},
+}
+
+// TestWFRPRollEvaluate calls diceroller.WFRPRoll.evaluate with many results and skills, checking the success levels and doubles.
+func TestWFRPRollEvaluate(t *testing.T) {
+	for _, test := range wfrpEvaluateTests {
+		output := WFRPRoll{Roll: DiceRoll{Total: test.result}, Skill: test.skill}
+		output.evaluate()
+
+		test.want.Roll, test.want.Skill = output.Roll, test.skill
+		if !reflect.DeepEqual(output, test.want) {
+			t.Errorf("have %+v, wanted %+v for %d against %d", output, test.want, test.result, test.skill)
+		}
+	}
+}
+
+// TestRollWFRP calls diceroller.RollWFRP, checking for valid return values.
+func TestRollWFRP(t *testing.T) {
+	reseed()
+
+	want := WFRPRoll{DiceRoll{DiscoveredRoll: "1d100", Faces: 100, Rolls: 1, Results: []int{95}, Total: 95, NaturalTotal: 95}, 45, false, -5, false, false, false}
+
+	if output, err := RollWFRP(45); !reflect.DeepEqual(output, want) || err != nil {
+		t.Errorf("have %v, wanted %v, err %v", output, want, err)
+	}
+}
+
+// BenchmarkRollWFRP benchmarks diceroller.RollWFRP.
+func BenchmarkRollWFRP(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollWFRP(45)
+	}
+}
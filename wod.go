@@ -0,0 +1,94 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import "fmt"
+
+// WoDRoll holds the details of a World of Darkness / Chronicles of Darkness dice pool roll.
+type WoDRoll struct {
+	Roll               DiceRoll // The pool of d10s. Results also holds the extra dice rolled for 10-again (or 9- or 8-again).
+	Successes          int      // How many dice came up 8 or more, or for a chance die, whether it came up 10.
+	Chance             bool     // Whether the pool was empty, so a single chance die was rolled.
+	DramaticFailure    bool     // Whether the chance die came up 1.
+	ExceptionalSuccess bool     // Whether there were five or more successes.
+}
+
+/*
+ * RollWoD rolls a pool of d10s, counting 8s and up as successes. Dice which come up 'again' or more (10, 9 or 8, or 0 for no rerolls)
+ *   are rolled again, adding to the pool. A pool of zero or less rolls a chance die instead, which only succeeds on a 10, isn't rolled
+ *   again, and is a dramatic failure on a 1.
+ * e.g. RollWoD(5, 10) // {{5d10 10 5 0 [10 1 5 10 4 6 10 7] 53 ...} 3 false false false}
+ */
+func RollWoD(pool, again int) (output WoDRoll, err error) {
+	return defaultRoller.RollWoD(pool, again)
+}
+
+/*
+ * RollWoD is like the package's RollWoD, but rolls with the Roller's random source.
+ * e.g. roller.RollWoD(5, 9)
+ */
+func (roller *Roller) RollWoD(pool, again int) (output WoDRoll, err error) {
+	switch again {
+	case 0, 8, 9, 10:
+	default:
+		err = fmt.Errorf("rerolls are 10-again, 9-again or 8-again (or 0 for none), not %d-again", again)
+		return
+	}
+
+	output.Chance = pool < 1
+	if output.Chance {
+		pool, again = 1, 0
+	}
+
+	dr, err := roller.rollParsed(newRollSpec(pool, DieSpec{Faces: 10}, 0))
+	if err != nil {
+		return
+	}
+
+	if again != 0 {
+		explodeResultsFrom(&dr, DieSpec{Faces: 10}, again, roller.source())
+	}
+
+	if output.Roll, err = postProcess(dr); err != nil {
+		return
+	}
+
+	output.evaluate()
+
+	return
+}
+
+/*
+ * evaluate counts the successes, and spots dramatic failures and exceptional successes.
+ */
+func (output *WoDRoll) evaluate() {
+	target := 8
+	if output.Chance {
+		target = 10
+	}
+
+	for _, result := range output.Roll.Results {
+		if result >= target {
+			output.Successes++
+		}
+	}
+
+	output.DramaticFailure = output.Chance && output.Roll.Results[0] == 1
+	output.ExceptionalSuccess = output.Successes >= 5
+}
@@ -0,0 +1,90 @@
+/*
+ * diceroller: A Go module to parse and simulate rolling dice for TTRPGs.
+ * Copyright (C) 2024 Paul Vaughan, github.com/vaughany.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diceroller
+
+import (
+	"reflect"
+	"testing"
+)
+
+type rollWoDTest struct {
+	pool, again int
+	want        WoDRoll
+}
+
+var rollWoDTests = []rollWoDTest{
+	{5, 10, WoDRoll{DiceRoll{DiscoveredRoll: "5d10", Faces: 10, Rolls: 5, Results: []int{10, 1, 5, 10, 4, 6, 10, 7}, Total: 53, NaturalTotal: 53}, 3, false, false, false}},
+	{8, 8, WoDRoll{DiceRoll{DiscoveredRoll: "8d10", Faces: 10, Rolls: 8, Results: []int{3, 2, 2, 10, 9, 2, 5, 10, 2, 7, 3}, Total: 55, NaturalTotal: 55}, 3, false, false, false}},
+	{0, 10, WoDRoll{DiceRoll{DiscoveredRoll: "1d10", Faces: 10, Rolls: 1, Results: []int{3}, Total: 3, NaturalTotal: 3}, 0, true, false, false}},
+	{3, 0, WoDRoll{DiceRoll{DiscoveredRoll: "3d10", Faces: 10, Rolls: 3, Results: []int{5, 1, 10}, Total: 16, NaturalTotal: 16}, 1, false, false, false}},
+}
+
+// TestRollWoD calls diceroller.RollWoD with many pools and rerolls, checking for valid return values.
+func TestRollWoD(t *testing.T) {
+	reseed()
+
+	for _, test := range rollWoDTests {
+		output, err := RollWoD(test.pool, test.again)
+
+		if !reflect.DeepEqual(output, test.want) || err != nil {
+			t.Errorf("have %v, wanted %v, err %v", output, test.want, err)
+		}
+	}
+
+	if _, err := RollWoD(5, 7); err == nil {
+		t.Errorf("have nil error for 7-again, wanted an error")
+	}
+}
+
+type wodEvaluateTest struct {
+	results                []int
+	chance                 bool
+	wantSuccesses          int
+	wantDramaticFailure    bool
+	wantExceptionalSuccess bool
+}
+
+var wodEvaluateTests = []wodEvaluateTest{
+	{[]int{8, 9, 10, 7, 1}, false, 3, false, false},
+	{[]int{8, 8, 8, 8, 8}, false, 5, false, true},
+	{[]int{1, 1, 1}, false, 0, false, false},
+	{[]int{9}, true, 0, false, false},
+	{[]int{10}, true, 1, false, false},
+	{[]int{1}, true, 0, true, false},
+}
+
+// TestWoDRollEvaluate calls diceroller.WoDRoll.evaluate with many pools and chance dice, checking the successes and failures.
+func TestWoDRollEvaluate(t *testing.T) {
+	for _, test := range wodEvaluateTests {
+		output := WoDRoll{Roll: DiceRoll{Results: test.results}, Chance: test.chance}
+		output.evaluate()
+
+		if output.Successes != test.wantSuccesses || output.DramaticFailure != test.wantDramaticFailure ||
+			output.ExceptionalSuccess != test.wantExceptionalSuccess {
+			t.Errorf("have %+v, wanted %d %v %v for %v", output, test.wantSuccesses, test.wantDramaticFailure, test.wantExceptionalSuccess, test.results)
+		}
+	}
+}
+
+// BenchmarkRollWoD benchmarks diceroller.RollWoD.
+func BenchmarkRollWoD(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = RollWoD(7, 10)
+	}
+}